@@ -0,0 +1,149 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	mydb "grpcbank/src/adapter/database"
+	"grpcbank/src/config"
+	dbmigration "grpcbank/src/db"
+	"log/slog"
+	"strconv"
+)
+
+const migrateUsage = "usage: grpcbank migrate [flags] up | down [N] | goto VERSION | version | force VERSION"
+
+// runMigrate runs the migrate subcommand args against the database of cfg.
+func runMigrate(cfg config.DatabaseConfig, args []string, logger *slog.Logger) error {
+	if len(args) == 0 {
+		return errors.New(migrateUsage)
+	}
+
+	if cfg.Driver == "memory" {
+		return errors.New("the memory driver has no schema to migrate")
+	}
+
+	command, operands := args[0], args[1:]
+
+	migrator, err := newMigrator(cfg)
+
+	if err != nil {
+		return err
+	}
+
+	defer migrator.Close()
+
+	switch {
+	case command == "up" && len(operands) == 0:
+		err = migrator.Up()
+	case command == "down" && len(operands) <= 1:
+		steps := 1
+
+		if len(operands) == 1 {
+			if steps, err = strconv.Atoi(operands[0]); err != nil || steps < 1 {
+				return fmt.Errorf("invalid number of migrations to revert %q", operands[0])
+			}
+		}
+
+		err = migrator.Down(steps)
+	case command == "goto" && len(operands) == 1:
+		version, parseErr := strconv.ParseUint(operands[0], 10, 0)
+
+		if parseErr != nil {
+			return fmt.Errorf("invalid version %q", operands[0])
+		}
+
+		err = migrator.Goto(uint(version))
+	case command == "force" && len(operands) == 1:
+		version, parseErr := strconv.Atoi(operands[0])
+
+		if parseErr != nil || version < -1 {
+			return fmt.Errorf("invalid version %q, expected a migration version or -1", operands[0])
+		}
+
+		err = migrator.Force(version)
+	case command == "version" && len(operands) == 0:
+	default:
+		return errors.New(migrateUsage)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return logVersion(migrator, logger)
+}
+
+// migrateDatabase applies the pending migrations before the server opens the database of cfg.
+func migrateDatabase(cfg config.DatabaseConfig, logger *slog.Logger) error {
+	migrator, err := newMigrator(cfg)
+
+	if err != nil {
+		return err
+	}
+
+	defer migrator.Close()
+
+	if err := migrator.Up(); err != nil {
+		return fmt.Errorf("can't migrate database : %w", err)
+	}
+
+	return logVersion(migrator, logger)
+}
+
+// newMigrator migrates the database of cfg over a connection of its own, which the migrator
+// closes, so that the locks it holds don't tie up the pool of the server.
+func newMigrator(cfg config.DatabaseConfig) (*dbmigration.Migrator, error) {
+	conn, err := openSQL(cfg)
+
+	if err != nil {
+		return nil, fmt.Errorf("can't connect database : %w", err)
+	}
+
+	var migrator *dbmigration.Migrator
+
+	if cfg.Driver == "sqlite" {
+		migrator, err = dbmigration.NewSQLiteMigrator(conn)
+	} else {
+		migrator, err = dbmigration.NewPostgresMigrator(conn)
+	}
+
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	return migrator, nil
+}
+
+// openSQL opens a connection pool to the Postgres or SQLite database of cfg.
+func openSQL(cfg config.DatabaseConfig) (*sql.DB, error) {
+	if cfg.Driver == "sqlite" {
+		return mydb.OpenSQLite(cfg.SQLiteFile)
+	}
+
+	return sql.Open("pgx", cfg.DSN)
+}
+
+func logVersion(migrator *dbmigration.Migrator, logger *slog.Logger) error {
+	version, dirty, err := migrator.Version()
+
+	if errors.Is(err, dbmigration.ErrNoVersion) {
+		logger.Info("Database has no migration applied")
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if dirty {
+		logger.Warn("Database is dirty, repair its schema then run migrate force with the version it matches",
+			slog.Uint64("version", uint64(version)))
+		return nil
+	}
+
+	logger.Info("Database schema version", slog.Uint64("version", uint64(version)))
+
+	return nil
+}
@@ -0,0 +1,179 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"grpcbank/src/config"
+	dbmigration "grpcbank/src/db"
+	"log/slog"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func newSQLiteConfig(t *testing.T) config.DatabaseConfig {
+	return config.DatabaseConfig{Driver: "sqlite", SQLiteFile: filepath.Join(t.TempDir(), "bank.db")}
+}
+
+// migrate runs the migrate subcommand args and returns what it logged.
+func migrate(cfg config.DatabaseConfig, args ...string) (string, error) {
+	var logs bytes.Buffer
+	err := runMigrate(cfg, args, slog.New(slog.NewTextHandler(&logs, nil)))
+
+	return logs.String(), err
+}
+
+func mustMigrate(t *testing.T, cfg config.DatabaseConfig, args ...string) string {
+	t.Helper()
+
+	logs, err := migrate(cfg, args...)
+
+	if err != nil {
+		t.Fatalf("migrate %v : %v", args, err)
+	}
+
+	return logs
+}
+
+func schemaVersion(t *testing.T, cfg config.DatabaseConfig) (uint, bool) {
+	t.Helper()
+
+	migrator, err := newMigrator(cfg)
+
+	if err != nil {
+		t.Fatalf("newMigrator : %v", err)
+	}
+
+	defer migrator.Close()
+
+	version, dirty, err := migrator.Version()
+
+	if err != nil {
+		t.Fatalf("Version : %v", err)
+	}
+
+	return version, dirty
+}
+
+func expectSchemaVersion(t *testing.T, cfg config.DatabaseConfig, want uint) {
+	t.Helper()
+
+	if version, dirty := schemaVersion(t, cfg); version != want || dirty {
+		t.Errorf("schema version = %d, dirty %v, want %d", version, dirty, want)
+	}
+}
+
+func TestMigrateMovesBetweenVersions(t *testing.T) {
+	cfg := newSQLiteConfig(t)
+
+	logs := mustMigrate(t, cfg, "version")
+
+	if !strings.Contains(logs, "Database has no migration applied") {
+		t.Errorf("version of a new database logged %q", logs)
+	}
+
+	mustMigrate(t, cfg, "up")
+	latest, _ := schemaVersion(t, cfg)
+
+	if latest < 3 {
+		t.Fatalf("latest version = %d, want a few migrations", latest)
+	}
+
+	mustMigrate(t, cfg, "up")
+	expectSchemaVersion(t, cfg, latest)
+
+	mustMigrate(t, cfg, "down")
+	expectSchemaVersion(t, cfg, latest-1)
+
+	mustMigrate(t, cfg, "down", "2")
+	expectSchemaVersion(t, cfg, latest-3)
+
+	mustMigrate(t, cfg, "goto", "1")
+	expectSchemaVersion(t, cfg, 1)
+
+	logs = mustMigrate(t, cfg, "goto", "2")
+	expectSchemaVersion(t, cfg, 2)
+
+	if !strings.Contains(logs, "Database schema version") || !strings.Contains(logs, "version=2") {
+		t.Errorf("goto 2 logged %q, want the schema version", logs)
+	}
+}
+
+func TestMigrateRejectsCommands(t *testing.T) {
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{nil, migrateUsage},
+		{[]string{"sideways"}, migrateUsage},
+		{[]string{"up", "3"}, migrateUsage},
+		{[]string{"down", "1", "2"}, migrateUsage},
+		{[]string{"down", "0"}, `invalid number of migrations to revert "0"`},
+		{[]string{"down", "two"}, `invalid number of migrations to revert "two"`},
+		{[]string{"goto"}, migrateUsage},
+		{[]string{"goto", "-1"}, `invalid version "-1"`},
+		{[]string{"force"}, migrateUsage},
+		{[]string{"force", "-2"}, `invalid version "-2"`},
+		{[]string{"version", "1"}, migrateUsage},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.args), func(t *testing.T) {
+			cfg := newSQLiteConfig(t)
+
+			if _, err := migrate(cfg, tt.args...); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("migrate %v = %v, want %q", tt.args, err, tt.want)
+			}
+		})
+	}
+
+	if _, err := migrate(config.DatabaseConfig{Driver: "memory"}, "up"); err == nil {
+		t.Errorf("migrate up of the memory driver succeeded, want an error")
+	}
+}
+
+func TestMigrateRefusesDirtyDatabaseUntilForced(t *testing.T) {
+	cfg := newSQLiteConfig(t)
+	mustMigrate(t, cfg, "goto", "2")
+
+	// a migration that failed half way leaves its version dirty
+	conn, err := openSQL(cfg)
+
+	if err != nil {
+		t.Fatalf("openSQL : %v", err)
+	}
+
+	if _, err := conn.Exec("UPDATE schema_migrations SET version = 3, dirty = 1"); err != nil {
+		t.Fatalf("Can't mark database dirty : %v", err)
+	}
+
+	conn.Close()
+
+	for _, args := range [][]string{{"up"}, {"down"}, {"goto", "1"}} {
+		if _, err := migrate(cfg, args...); !errors.Is(err, dbmigration.ErrDirty) {
+			t.Errorf("migrate %v of a dirty database = %v, want %v", args, err, dbmigration.ErrDirty)
+		}
+	}
+
+	if version, dirty := schemaVersion(t, cfg); version != 3 || !dirty {
+		t.Fatalf("schema version = %d, dirty %v, want 3, dirty", version, dirty)
+	}
+
+	logs := mustMigrate(t, cfg, "version")
+
+	if !strings.Contains(logs, "Database is dirty") {
+		t.Errorf("version of a dirty database logged %q", logs)
+	}
+
+	// the schema is still the one of version 2
+	mustMigrate(t, cfg, "force", "2")
+	expectSchemaVersion(t, cfg, 2)
+
+	mustMigrate(t, cfg, "up")
+	latest, dirty := schemaVersion(t, cfg)
+
+	if latest <= 2 || dirty {
+		t.Errorf("schema version after up = %d, dirty %v, want the latest", latest, dirty)
+	}
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"grpcbank/src/application"
+	"grpcbank/src/application/domain"
+	"grpcbank/src/config"
+	dbmigration "grpcbank/src/db"
+	"log/slog"
+)
+
+// runSeed opens the accounts of the fixture files args, or of the demo fixtures when args is
+// empty, in the database of cfg once it has been migrated.
+func runSeed(cfg config.Config, args []string, logger *slog.Logger) error {
+	if cfg.Database.Driver == "memory" {
+		return errors.New("the memory driver is seeded with the demo accounts on startup")
+	}
+
+	var fixtures []domain.AccountFixture
+
+	if len(args) == 0 {
+		demoFixtures, err := demoAccountFixtures()
+
+		if err != nil {
+			return err
+		}
+
+		fixtures = demoFixtures
+	}
+
+	for _, path := range args {
+		fixturesConfig, err := config.LoadFixtures(path)
+
+		if err != nil {
+			return err
+		}
+
+		fileFixtures, err := toAccountFixtures(fixturesConfig)
+
+		if err != nil {
+			return fmt.Errorf("invalid fixture file %v : %w", path, err)
+		}
+
+		fixtures = append(fixtures, fileFixtures...)
+	}
+
+	store, sqlDB, err := openStorage(cfg, logger)
+
+	if err != nil {
+		return err
+	}
+
+	defer sqlDB.Close()
+
+	opened, err := application.SeedAccounts(context.Background(), store, fixtures)
+
+	if err != nil {
+		return err
+	}
+
+	logger.Info("Seeded accounts", slog.Int("opened", opened), slog.Int("skipped", len(fixtures)-opened))
+
+	return nil
+}
+
+// demoAccountFixtures returns the demo accounts built into the binary.
+func demoAccountFixtures() ([]domain.AccountFixture, error) {
+	fixturesConfig, err := config.ParseFixtures(dbmigration.DemoFixturesFile, dbmigration.DemoFixtures)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return toAccountFixtures(fixturesConfig)
+}
+
+// toAccountFixtures checks every account of cfg and reports all the invalid ones at once.
+func toAccountFixtures(cfg config.FixturesConfig) ([]domain.AccountFixture, error) {
+	fixtures := make([]domain.AccountFixture, 0, len(cfg.Accounts))
+	var errs []error
+
+	for i, acct := range cfg.Accounts {
+		accountUuid, err := uuid.Parse(acct.AccountUuid)
+
+		if err != nil {
+			errs = append(errs, fmt.Errorf("account %d has an invalid account_uuid %q", i, acct.AccountUuid))
+		}
+
+		if acct.AccountNumber == "" || acct.AccountName == "" || acct.Currency == "" {
+			errs = append(errs, fmt.Errorf("account %d needs an account_number, an account_name and a currency", i))
+		}
+
+		initialDeposit := acct.InitialDeposit
+
+		if initialDeposit == "" {
+			initialDeposit = "0"
+		}
+
+		deposit, err := domain.ParseMoney(acct.Currency, initialDeposit)
+
+		if err != nil {
+			errs = append(errs, fmt.Errorf("account %d has an invalid initial_deposit : %w", i, err))
+		} else if deposit.IsNegative() {
+			errs = append(errs, fmt.Errorf("account %d has a negative initial_deposit", i))
+		}
+
+		fixtures = append(fixtures, domain.AccountFixture{
+			AccountUuid:    accountUuid,
+			AccountNumber:  acct.AccountNumber,
+			AccountName:    acct.AccountName,
+			OwnerSubject:   acct.OwnerSubject,
+			InitialDeposit: deposit,
+		})
+	}
+
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	return fixtures, nil
+}
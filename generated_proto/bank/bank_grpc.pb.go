@@ -22,7 +22,10 @@ const (
 	BankService_GetCurrentBalance_FullMethodName     = "/bank.BankService/GetCurrentBalance"
 	BankService_FetchExchangeRates_FullMethodName    = "/bank.BankService/FetchExchangeRates"
 	BankService_SummarizeTransactions_FullMethodName = "/bank.BankService/SummarizeTransactions"
+	BankService_ProcessTransactions_FullMethodName   = "/bank.BankService/ProcessTransactions"
 	BankService_TransferMultiple_FullMethodName      = "/bank.BankService/TransferMultiple"
+	BankService_ListTransactions_FullMethodName      = "/bank.BankService/ListTransactions"
+	BankService_WatchBalance_FullMethodName          = "/bank.BankService/WatchBalance"
 )
 
 // BankServiceClient is the client API for BankService service.
@@ -32,7 +35,10 @@ type BankServiceClient interface {
 	GetCurrentBalance(ctx context.Context, in *CurrentBalanceRequest, opts ...grpc.CallOption) (*CurrentBalanceResponse, error)
 	FetchExchangeRates(ctx context.Context, in *ExchangeRateRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ExchangeRateResponse], error)
 	SummarizeTransactions(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[Transaction, TransactionSummary], error)
+	ProcessTransactions(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[ProcessTransactionsRequest, TransactionResult], error)
 	TransferMultiple(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[TransferRequest, TransferResponse], error)
+	ListTransactions(ctx context.Context, in *ListTransactionsRequest, opts ...grpc.CallOption) (*ListTransactionsResponse, error)
+	WatchBalance(ctx context.Context, in *WatchBalanceRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[BalanceUpdate], error)
 }
 
 type bankServiceClient struct {
@@ -85,9 +91,22 @@ func (c *bankServiceClient) SummarizeTransactions(ctx context.Context, opts ...g
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type BankService_SummarizeTransactionsClient = grpc.ClientStreamingClient[Transaction, TransactionSummary]
 
+func (c *bankServiceClient) ProcessTransactions(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[ProcessTransactionsRequest, TransactionResult], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &BankService_ServiceDesc.Streams[2], BankService_ProcessTransactions_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ProcessTransactionsRequest, TransactionResult]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type BankService_ProcessTransactionsClient = grpc.BidiStreamingClient[ProcessTransactionsRequest, TransactionResult]
+
 func (c *bankServiceClient) TransferMultiple(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[TransferRequest, TransferResponse], error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
-	stream, err := c.cc.NewStream(ctx, &BankService_ServiceDesc.Streams[2], BankService_TransferMultiple_FullMethodName, cOpts...)
+	stream, err := c.cc.NewStream(ctx, &BankService_ServiceDesc.Streams[3], BankService_TransferMultiple_FullMethodName, cOpts...)
 	if err != nil {
 		return nil, err
 	}
@@ -98,6 +117,35 @@ func (c *bankServiceClient) TransferMultiple(ctx context.Context, opts ...grpc.C
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type BankService_TransferMultipleClient = grpc.BidiStreamingClient[TransferRequest, TransferResponse]
 
+func (c *bankServiceClient) ListTransactions(ctx context.Context, in *ListTransactionsRequest, opts ...grpc.CallOption) (*ListTransactionsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListTransactionsResponse)
+	err := c.cc.Invoke(ctx, BankService_ListTransactions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bankServiceClient) WatchBalance(ctx context.Context, in *WatchBalanceRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[BalanceUpdate], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &BankService_ServiceDesc.Streams[4], BankService_WatchBalance_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchBalanceRequest, BalanceUpdate]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type BankService_WatchBalanceClient = grpc.ServerStreamingClient[BalanceUpdate]
+
 // BankServiceServer is the server API for BankService service.
 // All implementations must embed UnimplementedBankServiceServer
 // for forward compatibility.
@@ -105,7 +153,10 @@ type BankServiceServer interface {
 	GetCurrentBalance(context.Context, *CurrentBalanceRequest) (*CurrentBalanceResponse, error)
 	FetchExchangeRates(*ExchangeRateRequest, grpc.ServerStreamingServer[ExchangeRateResponse]) error
 	SummarizeTransactions(grpc.ClientStreamingServer[Transaction, TransactionSummary]) error
+	ProcessTransactions(grpc.BidiStreamingServer[ProcessTransactionsRequest, TransactionResult]) error
 	TransferMultiple(grpc.BidiStreamingServer[TransferRequest, TransferResponse]) error
+	ListTransactions(context.Context, *ListTransactionsRequest) (*ListTransactionsResponse, error)
+	WatchBalance(*WatchBalanceRequest, grpc.ServerStreamingServer[BalanceUpdate]) error
 	mustEmbedUnimplementedBankServiceServer()
 }
 
@@ -125,9 +176,18 @@ func (UnimplementedBankServiceServer) FetchExchangeRates(*ExchangeRateRequest, g
 func (UnimplementedBankServiceServer) SummarizeTransactions(grpc.ClientStreamingServer[Transaction, TransactionSummary]) error {
 	return status.Errorf(codes.Unimplemented, "method SummarizeTransactions not implemented")
 }
+func (UnimplementedBankServiceServer) ProcessTransactions(grpc.BidiStreamingServer[ProcessTransactionsRequest, TransactionResult]) error {
+	return status.Errorf(codes.Unimplemented, "method ProcessTransactions not implemented")
+}
 func (UnimplementedBankServiceServer) TransferMultiple(grpc.BidiStreamingServer[TransferRequest, TransferResponse]) error {
 	return status.Errorf(codes.Unimplemented, "method TransferMultiple not implemented")
 }
+func (UnimplementedBankServiceServer) ListTransactions(context.Context, *ListTransactionsRequest) (*ListTransactionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListTransactions not implemented")
+}
+func (UnimplementedBankServiceServer) WatchBalance(*WatchBalanceRequest, grpc.ServerStreamingServer[BalanceUpdate]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchBalance not implemented")
+}
 func (UnimplementedBankServiceServer) mustEmbedUnimplementedBankServiceServer() {}
 func (UnimplementedBankServiceServer) testEmbeddedByValue()                     {}
 
@@ -185,6 +245,13 @@ func _BankService_SummarizeTransactions_Handler(srv interface{}, stream grpc.Ser
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type BankService_SummarizeTransactionsServer = grpc.ClientStreamingServer[Transaction, TransactionSummary]
 
+func _BankService_ProcessTransactions_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(BankServiceServer).ProcessTransactions(&grpc.GenericServerStream[ProcessTransactionsRequest, TransactionResult]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type BankService_ProcessTransactionsServer = grpc.BidiStreamingServer[ProcessTransactionsRequest, TransactionResult]
+
 func _BankService_TransferMultiple_Handler(srv interface{}, stream grpc.ServerStream) error {
 	return srv.(BankServiceServer).TransferMultiple(&grpc.GenericServerStream[TransferRequest, TransferResponse]{ServerStream: stream})
 }
@@ -192,6 +259,35 @@ func _BankService_TransferMultiple_Handler(srv interface{}, stream grpc.ServerSt
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type BankService_TransferMultipleServer = grpc.BidiStreamingServer[TransferRequest, TransferResponse]
 
+func _BankService_ListTransactions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTransactionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BankServiceServer).ListTransactions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BankService_ListTransactions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BankServiceServer).ListTransactions(ctx, req.(*ListTransactionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BankService_WatchBalance_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchBalanceRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(BankServiceServer).WatchBalance(m, &grpc.GenericServerStream[WatchBalanceRequest, BalanceUpdate]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type BankService_WatchBalanceServer = grpc.ServerStreamingServer[BalanceUpdate]
+
 // BankService_ServiceDesc is the grpc.ServiceDesc for BankService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -203,6 +299,10 @@ var BankService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetCurrentBalance",
 			Handler:    _BankService_GetCurrentBalance_Handler,
 		},
+		{
+			MethodName: "ListTransactions",
+			Handler:    _BankService_ListTransactions_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -215,12 +315,23 @@ var BankService_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _BankService_SummarizeTransactions_Handler,
 			ClientStreams: true,
 		},
+		{
+			StreamName:    "ProcessTransactions",
+			Handler:       _BankService_ProcessTransactions_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
 		{
 			StreamName:    "TransferMultiple",
 			Handler:       _BankService_TransferMultiple_Handler,
 			ServerStreams: true,
 			ClientStreams: true,
 		},
+		{
+			StreamName:    "WatchBalance",
+			Handler:       _BankService_WatchBalance_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "proto/bank/bank.proto",
 }
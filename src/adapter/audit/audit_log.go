@@ -0,0 +1,72 @@
+package audit
+
+import (
+	"encoding/json"
+	"grpcbank/src/application/domain"
+	"io"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+)
+
+// JSONAuditLog writes one JSON object per audit event.
+type JSONAuditLog struct {
+	mu     sync.Mutex
+	w      io.Writer
+	logger *slog.Logger
+}
+
+type auditEntry struct {
+	Timestamp  time.Time `json:"timestamp"`
+	Event      string    `json:"event"`
+	Subject    string    `json:"subject"`
+	Roles      []string  `json:"roles"`
+	Operation  string    `json:"operation"`
+	Permission string    `json:"permission,omitempty"`
+	Resource   string    `json:"resource,omitempty"`
+	Reason     string    `json:"reason"`
+}
+
+func NewJSONAuditLog(w io.Writer, logger *slog.Logger) *JSONAuditLog {
+	return &JSONAuditLog{
+		w:      w,
+		logger: logger,
+	}
+}
+
+// OpenJSONAuditLog appends audit events to the file at path.
+func OpenJSONAuditLog(path string, logger *slog.Logger) (*JSONAuditLog, *os.File, error) {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return NewJSONAuditLog(file, logger), file, nil
+}
+
+func (l *JSONAuditLog) RecordDenial(event domain.AuditEvent) {
+	line, err := json.Marshal(auditEntry{
+		Timestamp:  event.Timestamp,
+		Event:      "access_denied",
+		Subject:    event.Subject,
+		Roles:      event.Roles,
+		Operation:  event.Operation,
+		Permission: string(event.Permission),
+		Resource:   event.Resource,
+		Reason:     event.Reason,
+	})
+
+	if err != nil {
+		l.logger.Error("Can't encode audit event", slog.Any("error", err))
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if _, err := l.w.Write(append(line, '\n')); err != nil {
+		l.logger.Error("Can't write audit event", slog.Any("error", err))
+	}
+}
@@ -0,0 +1,164 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4/stdlib"
+	"grpcbank/src/application/domain"
+	"log/slog"
+	"time"
+)
+
+const balanceUpdateChannel = "bank_balance_updates"
+
+// publishTimeout bounds the notification of a committed update, which is then delivered locally.
+const publishTimeout = 5 * time.Second
+
+// BalanceRelay shares balance updates between server instances with Postgres LISTEN/NOTIFY.
+type BalanceRelay struct {
+	conn   *sql.DB
+	logger *slog.Logger
+}
+
+type balanceUpdatePayload struct {
+	AccountNumber   string    `json:"account_number"`
+	Currency        string    `json:"currency"`
+	Balance         string    `json:"balance"`
+	Version         int64     `json:"version"`
+	TransactionUuid uuid.UUID `json:"transaction_uuid"`
+	TransactionType string    `json:"transaction_type"`
+	Amount          string    `json:"amount"`
+	Timestamp       time.Time `json:"timestamp"`
+	Notes           string    `json:"notes"`
+}
+
+func NewBalanceRelay(conn *sql.DB, logger *slog.Logger) *BalanceRelay {
+	return &BalanceRelay{
+		conn:   conn,
+		logger: logger,
+	}
+}
+
+func (r *BalanceRelay) PublishBalanceUpdate(ctx context.Context, update domain.BalanceUpdate) error {
+	payload, err := toBalanceUpdatePayload(update)
+
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, publishTimeout)
+	defer cancel()
+
+	_, err = r.conn.ExecContext(ctx, "SELECT pg_notify($1, $2)", balanceUpdateChannel, payload)
+
+	return err
+}
+
+func toBalanceUpdatePayload(update domain.BalanceUpdate) (string, error) {
+	payload, err := json.Marshal(balanceUpdatePayload{
+		AccountNumber:   update.AccountNumber,
+		Currency:        update.Balance.Currency,
+		Balance:         update.Balance.String(),
+		Version:         update.Version,
+		TransactionUuid: update.Transaction.TransactionUuid,
+		TransactionType: update.Transaction.TransactionType,
+		Amount:          update.Transaction.Amount.String(),
+		Timestamp:       update.Transaction.Timestamp,
+		Notes:           update.Transaction.Notes,
+	})
+
+	return string(payload), err
+}
+
+// Listen delivers every balance update notified by any instance until ctx is done,
+// reconnecting after connection failures.
+func (r *BalanceRelay) Listen(ctx context.Context, deliver func(domain.BalanceUpdate)) {
+	for ctx.Err() == nil {
+		if err := r.listen(ctx, deliver); err != nil && ctx.Err() == nil {
+			r.logger.ErrorContext(ctx, "Balance update listener failed, reconnecting", slog.Any("error", err))
+
+			select {
+			case <-ctx.Done():
+			case <-time.After(time.Second):
+			}
+		}
+	}
+}
+
+func (r *BalanceRelay) listen(ctx context.Context, deliver func(domain.BalanceUpdate)) error {
+	conn, err := r.conn.Conn(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	defer conn.Close()
+
+	return conn.Raw(func(driverConn any) error {
+		stdlibConn, ok := driverConn.(*stdlib.Conn)
+
+		if !ok {
+			return fmt.Errorf("unexpected driver connection %T", driverConn)
+		}
+
+		pgxConn := stdlibConn.Conn()
+
+		if _, err := pgxConn.Exec(ctx, "LISTEN "+balanceUpdateChannel); err != nil {
+			return err
+		}
+
+		for {
+			notification, err := pgxConn.WaitForNotification(ctx)
+
+			if err != nil {
+				return err
+			}
+
+			update, err := toBalanceUpdate(notification.Payload)
+
+			if err != nil {
+				r.logger.WarnContext(ctx, "Ignoring malformed balance update notification", slog.Any("error", err))
+				continue
+			}
+
+			deliver(update)
+		}
+	})
+}
+
+func toBalanceUpdate(rawPayload string) (domain.BalanceUpdate, error) {
+	var payload balanceUpdatePayload
+
+	if err := json.Unmarshal([]byte(rawPayload), &payload); err != nil {
+		return domain.BalanceUpdate{}, err
+	}
+
+	balance, err := domain.ParseMoney(payload.Currency, payload.Balance)
+
+	if err != nil {
+		return domain.BalanceUpdate{}, err
+	}
+
+	amount, err := domain.ParseMoney(payload.Currency, payload.Amount)
+
+	if err != nil {
+		return domain.BalanceUpdate{}, err
+	}
+
+	return domain.BalanceUpdate{
+		AccountNumber: payload.AccountNumber,
+		Balance:       balance,
+		Version:       payload.Version,
+		Transaction: domain.Transaction{
+			TransactionUuid: payload.TransactionUuid,
+			AccountNumber:   payload.AccountNumber,
+			Amount:          amount,
+			Timestamp:       payload.Timestamp,
+			TransactionType: payload.TransactionType,
+			Notes:           payload.Notes,
+		},
+	}, nil
+}
@@ -0,0 +1,41 @@
+package database
+
+import (
+	"github.com/google/uuid"
+	"grpcbank/src/application/domain"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestBalanceUpdatePayloadRoundTrip(t *testing.T) {
+	update := domain.BalanceUpdate{
+		AccountNumber: "7835697001",
+		Balance:       domain.NewMoney("USD", 7550),
+		Version:       42,
+		Transaction: domain.Transaction{
+			TransactionUuid: uuid.New(),
+			AccountNumber:   "7835697001",
+			Amount:          domain.NewMoney("USD", 2450),
+			Timestamp:       time.Date(2026, 10, 17, 10, 0, 0, 0, time.UTC),
+			TransactionType: domain.TransactionTypeOut,
+			Notes:           "Rent",
+		},
+	}
+
+	payload, err := toBalanceUpdatePayload(update)
+
+	if err != nil {
+		t.Fatalf("toBalanceUpdatePayload : %v", err)
+	}
+
+	got, err := toBalanceUpdate(payload)
+
+	if err != nil {
+		t.Fatalf("toBalanceUpdate(%v) : %v", payload, err)
+	}
+
+	if !reflect.DeepEqual(got, update) {
+		t.Errorf("toBalanceUpdate = %+v, want %+v", got, update)
+	}
+}
@@ -1,133 +1,645 @@
 package database
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"grpcbank/src/application/domain"
-	"log"
+	"grpcbank/src/logging"
+	"log/slog"
+	"sort"
+	"strings"
 	"time"
 )
 
-func (a *DatabaseAdapter) GetBankAccountByAccountNumber(accountNumber string) (BankAccountOrm, error) {
+func (a *DatabaseAdapter) GetBankAccountByAccountNumber(ctx context.Context,
+	accountNumber string) (domain.Account, error) {
+	ctx, span := startSpan(ctx, "GetBankAccountByAccountNumber")
+	defer span.End()
+
+	ctx, cancel := withTimeout(ctx, a.timeouts.Read)
+	defer cancel()
+
 	var bankAccountOrm BankAccountOrm
 
-	if err := a.db.First(&bankAccountOrm, "account_number = ?", accountNumber).Error; err != nil {
-		log.Printf("Can't find bank account number %v : %v\n", accountNumber, err)
-		return bankAccountOrm, err
+	if err := a.db.WithContext(ctx).First(&bankAccountOrm, "account_number = ?", accountNumber).Error; err != nil {
+		a.logger.WarnContext(ctx, "Can't find bank account", slog.String(logging.AccountNumberKey, accountNumber),
+			slog.Any("error", err))
+
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.Account{}, recordError(span, fmt.Errorf("%w : %v", domain.ErrAccountNotFound, accountNumber))
+		}
+
+		return domain.Account{}, recordError(span, err)
 	}
 
-	return bankAccountOrm, nil
+	account, err := toAccount(bankAccountOrm)
+
+	return account, recordError(span, err)
 }
 
-func (a *DatabaseAdapter) CreateExchangeRate(exchangeRate BankExchangeRateOrm) (uuid.UUID, error) {
-	if err := a.db.Create(exchangeRate).Error; err != nil {
-		return uuid.Nil, err
+func (a *DatabaseAdapter) CreateExchangeRate(ctx context.Context, exchangeRate domain.ExchangeRate) (uuid.UUID, error) {
+	ctx, span := startSpan(ctx, "CreateExchangeRate")
+	defer span.End()
+
+	ctx, cancel := withTimeout(ctx, a.timeouts.Write)
+	defer cancel()
+
+	exchangeRateOrm := toExchangeRateOrm(exchangeRate, time.Now())
+
+	if err := a.db.WithContext(ctx).Create(exchangeRateOrm).Error; err != nil {
+		return uuid.Nil, recordError(span, err)
 	}
 
-	return exchangeRate.ExchangeRateUuid, nil
+	return exchangeRateOrm.ExchangeRateUuid, nil
 }
 
-func (a *DatabaseAdapter) GetExchangeRateAtTimestamp(fromCur string, toCur string,
-	timeStamp time.Time) (BankExchangeRateOrm, error) {
+func (a *DatabaseAdapter) GetExchangeRateAtTimestamp(ctx context.Context, fromCur string, toCur string,
+	timeStamp time.Time) (domain.ExchangeRate, error) {
+	ctx, span := startSpan(ctx, "GetExchangeRateAtTimestamp")
+	defer span.End()
+
+	ctx, cancel := withTimeout(ctx, a.timeouts.Read)
+	defer cancel()
+
 	var exchangeRateOrm BankExchangeRateOrm
 
-	err := a.db.First(&exchangeRateOrm, "from_currency = ? "+" AND to_currency = ? "+
-		" AND (? BETWEEN valid_from_timestamp and valid_to_timestamp)", fromCur, toCur, timeStamp).Error
+	// the latest starting window wins should windows overlap
+	err := a.db.WithContext(ctx).Order("valid_from_timestamp DESC").First(&exchangeRateOrm,
+		"from_currency = ? "+" AND to_currency = ? "+" AND (? BETWEEN valid_from_timestamp and valid_to_timestamp)",
+		fromCur, toCur, timeStamp).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return domain.ExchangeRate{}, recordError(span, fmt.Errorf("%w : %v to %v", domain.ErrExchangeRateNotFound,
+			fromCur, toCur))
+	}
 
-	return exchangeRateOrm, err
+	if err != nil {
+		return domain.ExchangeRate{}, recordError(span, err)
+	}
+
+	exchangeRate, err := toExchangeRate(exchangeRateOrm)
+
+	return exchangeRate, recordError(span, err)
 }
 
-func (a *DatabaseAdapter) CreateTransaction(acct BankAccountOrm, bankTrx BankTransactionOrm) (uuid.UUID, error) {
-	tx := a.db.Begin()
+func (a *DatabaseAdapter) FindNextExchangeRateStart(ctx context.Context, fromCur string, toCur string,
+	after time.Time) (time.Time, bool, error) {
+	ctx, span := startSpan(ctx, "FindNextExchangeRateStart")
+	defer span.End()
+
+	ctx, cancel := withTimeout(ctx, a.timeouts.Read)
+	defer cancel()
+
+	var exchangeRateOrm BankExchangeRateOrm
 
-	if err := tx.Create(bankTrx).Error; err != nil {
-		tx.Rollback()
-		return uuid.Nil, err
+	err := a.db.WithContext(ctx).Order("valid_from_timestamp").First(&exchangeRateOrm,
+		"from_currency = ? AND to_currency = ? AND valid_from_timestamp > ?", fromCur, toCur, after).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return time.Time{}, false, nil
+	}
+
+	if err != nil {
+		return time.Time{}, false, recordError(span, err)
 	}
 
-	newAmount := bankTrx.Amount
+	return exchangeRateOrm.ValidFromTimestamp, true, nil
+}
+
+// FindTransactionByIdempotencyKey returns the transaction created with key. A key older than
+// retainedSince has expired: it is released so it can be reused and reported as not found.
+func (a *DatabaseAdapter) FindTransactionByIdempotencyKey(ctx context.Context, key string,
+	retainedSince time.Time) (domain.LedgerEntry, bool, error) {
+	ctx, span := startSpan(ctx, "FindTransactionByIdempotencyKey")
+	defer span.End()
 
-	if bankTrx.TransactionType == domain.TransactionTypeOut {
-		newAmount = -1 * bankTrx.Amount
+	ctx, cancel := withTimeout(ctx, a.timeouts.Read)
+	defer cancel()
+
+	db := a.db.WithContext(ctx)
+
+	var bankTransactionOrm BankTransactionOrm
+
+	err := db.First(&bankTransactionOrm, "idempotency_key = ?", key).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return domain.LedgerEntry{}, false, nil
 	}
 
-	newAccountBalance := acct.CurrentBalance + newAmount
+	if err != nil {
+		return domain.LedgerEntry{}, false, recordError(span, err)
+	}
 
-	if err := tx.Model(&acct).Updates(
-		map[string]interface{}{
-			"current_balance": newAccountBalance,
-			"updated_at":      time.Now(),
-		},
-	).Error; err != nil {
-		tx.Rollback()
-		return uuid.Nil, err
+	if bankTransactionOrm.CreatedAt.Before(retainedSince) {
+		if err := db.Model(&bankTransactionOrm).Update("idempotency_key", nil).Error; err != nil {
+			return domain.LedgerEntry{}, false, recordError(span, err)
+		}
+
+		return domain.LedgerEntry{}, false, nil
+	}
+
+	// amounts are stored without their currency, which is the one of the account
+	var bankAccountOrm BankAccountOrm
+
+	if err := db.Select("currency").First(&bankAccountOrm, "account_uuid = ?",
+		bankTransactionOrm.AccountUuid).Error; err != nil {
+		return domain.LedgerEntry{}, false, recordError(span, err)
+	}
+
+	entry, err := toLedgerEntry(bankTransactionOrm, bankAccountOrm.Currency)
+
+	if err != nil {
+		return domain.LedgerEntry{}, false, recordError(span, err)
+	}
+
+	return entry, true, nil
+}
+
+// ListTransactions returns up to limit transactions of acct matching filter, newest first,
+// starting after the given cursor when it is set.
+func (a *DatabaseAdapter) ListTransactions(ctx context.Context, acct domain.Account, filter domain.TransactionFilter,
+	after *domain.TransactionCursor, limit int) ([]domain.LedgerEntry, error) {
+	ctx, span := startSpan(ctx, "ListTransactions")
+	defer span.End()
+
+	ctx, cancel := withTimeout(ctx, a.timeouts.Read)
+	defer cancel()
+
+	var bankTransactionOrms []BankTransactionOrm
+
+	query := a.db.WithContext(ctx).Where("account_uuid = ?", acct.AccountUuid)
+
+	if !filter.FromTimestamp.IsZero() {
+		query = query.Where("transaction_timestamp >= ?", filter.FromTimestamp)
 	}
 
-	tx.Commit()
+	if !filter.ToTimestamp.IsZero() {
+		query = query.Where("transaction_timestamp <= ?", filter.ToTimestamp)
+	}
+
+	if filter.TransactionType != "" {
+		query = query.Where("transaction_type = ?", filter.TransactionType)
+	}
+
+	if filter.MinAmount != nil {
+		query = query.Where("amount >= ?", filter.MinAmount.String())
+	}
+
+	if filter.MaxAmount != nil {
+		query = query.Where("amount <= ?", filter.MaxAmount.String())
+	}
+
+	if filter.NotesContains != "" {
+		query = query.Where("notes ILIKE ?", "%"+escapeLike(filter.NotesContains)+"%")
+	}
+
+	if after != nil {
+		query = query.Where("(transaction_timestamp, transaction_uuid) < (?, ?)", after.Timestamp, after.TransactionUuid)
+	}
+
+	err := query.Order("transaction_timestamp DESC, transaction_uuid DESC").
+		Limit(limit).
+		Find(&bankTransactionOrms).Error
 
-	return bankTrx.TransactionUuid, nil
+	if err != nil {
+		return nil, recordError(span, err)
+	}
+
+	entries := make([]domain.LedgerEntry, 0, len(bankTransactionOrms))
+
+	for _, bankTransactionOrm := range bankTransactionOrms {
+		entry, err := toLedgerEntry(bankTransactionOrm, acct.Currency())
+
+		if err != nil {
+			return nil, recordError(span, err)
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
 }
 
-func (a *DatabaseAdapter) CreateTransfer(transfer BankTransferOrm) (uuid.UUID, error) {
-	if err := a.db.Create(transfer).Error; err != nil {
-		return uuid.Nil, err
+// CreateTransaction posts entry to its account and returns the account as it was committed.
+func (a *DatabaseAdapter) CreateTransaction(ctx context.Context, entry domain.LedgerEntry) (domain.Account, error) {
+	ctx, span := startSpan(ctx, "CreateTransaction")
+	defer span.End()
+
+	ctx, cancel := withTimeout(ctx, a.timeouts.Write)
+	defer cancel()
+
+	bankTrx := toTransactionOrm(entry)
+
+	var updatedAccount BankAccountOrm
+
+	err := a.inTransaction(ctx, func(tx *gorm.DB) error {
+		if err := lockAccounts(tx, bankTrx.AccountUuid); err != nil {
+			return err
+		}
+
+		if err := tx.Create(bankTrx).Error; err != nil {
+			if isUniqueViolation(err) {
+				return domain.ErrDuplicateIdempotencyKey
+			}
+
+			return err
+		}
+
+		if bankTrx.TransactionType == domain.TransactionTypeOut {
+			if err := debitAccount(tx, bankTrx.AccountUuid, bankTrx.Amount); err != nil {
+				return err
+			}
+		} else if err := creditAccount(tx, bankTrx.AccountUuid, bankTrx.Amount); err != nil {
+			return err
+		}
+
+		return tx.First(&updatedAccount, "account_uuid = ?", bankTrx.AccountUuid).Error
+	})
+
+	if err != nil {
+		return domain.Account{}, recordError(span, err)
+	}
+
+	account, err := toAccount(updatedAccount)
+
+	return account, recordError(span, err)
+}
+
+// CreateTransactionBatch posts every entry to its account in one database transaction, so either
+// all of them are committed or none is. It returns each account as it was right after the entry
+// at the same index was posted. On failure it returns the index of the entry that failed, or -1
+// when the failure isn't tied to a single entry.
+func (a *DatabaseAdapter) CreateTransactionBatch(ctx context.Context,
+	entries []domain.LedgerEntry) ([]domain.Account, int, error) {
+	ctx, span := startSpan(ctx, "CreateTransactionBatch")
+	defer span.End()
+
+	ctx, cancel := withTimeout(ctx, a.timeouts.Write)
+	defer cancel()
+
+	var updatedAccounts []BankAccountOrm
+	failedIndex := -1
+
+	err := a.inTransaction(ctx, func(tx *gorm.DB) error {
+		updatedAccounts = make([]BankAccountOrm, 0, len(entries))
+		failedIndex = -1
+
+		accountUuids := make([]uuid.UUID, 0, len(entries))
+
+		for _, entry := range entries {
+			accountUuids = append(accountUuids, entry.AccountUuid)
+		}
+
+		if err := lockAccounts(tx, accountUuids...); err != nil {
+			return err
+		}
+
+		for i, entry := range entries {
+			failedIndex = i
+			bankTrx := toTransactionOrm(entry)
+
+			if err := tx.Create(bankTrx).Error; err != nil {
+				if isUniqueViolation(err) {
+					return domain.ErrDuplicateIdempotencyKey
+				}
+
+				return err
+			}
+
+			if bankTrx.TransactionType == domain.TransactionTypeOut {
+				if err := debitAccount(tx, bankTrx.AccountUuid, bankTrx.Amount); err != nil {
+					return err
+				}
+			} else if err := creditAccount(tx, bankTrx.AccountUuid, bankTrx.Amount); err != nil {
+				return err
+			}
+
+			var updatedAccount BankAccountOrm
+
+			if err := tx.First(&updatedAccount, "account_uuid = ?", bankTrx.AccountUuid).Error; err != nil {
+				return err
+			}
+
+			updatedAccounts = append(updatedAccounts, updatedAccount)
+		}
+
+		failedIndex = -1
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, failedIndex, recordError(span, err)
+	}
+
+	accounts := make([]domain.Account, 0, len(updatedAccounts))
+
+	for _, updatedAccount := range updatedAccounts {
+		account, err := toAccount(updatedAccount)
+
+		if err != nil {
+			return nil, -1, recordError(span, err)
+		}
+
+		accounts = append(accounts, account)
+	}
+
+	return accounts, -1, nil
+}
+
+// FindTransferByIdempotencyKey returns the transfer created with key, releasing the key
+// like FindTransactionByIdempotencyKey once it is older than retainedSince.
+func (a *DatabaseAdapter) FindTransferByIdempotencyKey(ctx context.Context, key string,
+	retainedSince time.Time) (domain.Transfer, bool, error) {
+	ctx, span := startSpan(ctx, "FindTransferByIdempotencyKey")
+	defer span.End()
+
+	ctx, cancel := withTimeout(ctx, a.timeouts.Read)
+	defer cancel()
+
+	db := a.db.WithContext(ctx)
+
+	var bankTransferOrm BankTransferOrm
+
+	err := db.First(&bankTransferOrm, "idempotency_key = ?", key).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return domain.Transfer{}, false, nil
+	}
+
+	if err != nil {
+		return domain.Transfer{}, false, recordError(span, err)
+	}
+
+	if bankTransferOrm.CreatedAt.Before(retainedSince) {
+		if err := db.Model(&bankTransferOrm).Update("idempotency_key", nil).Error; err != nil {
+			return domain.Transfer{}, false, recordError(span, err)
+		}
+
+		return domain.Transfer{}, false, nil
+	}
+
+	transfer, err := toTransfer(bankTransferOrm)
+
+	if err != nil {
+		return domain.Transfer{}, false, recordError(span, err)
+	}
+
+	return transfer, true, nil
+}
+
+func (a *DatabaseAdapter) CreateTransfer(ctx context.Context, transfer domain.Transfer) (uuid.UUID, error) {
+	ctx, span := startSpan(ctx, "CreateTransfer")
+	defer span.End()
+
+	ctx, cancel := withTimeout(ctx, a.timeouts.Write)
+	defer cancel()
+
+	if err := a.db.WithContext(ctx).Create(toTransferOrm(transfer)).Error; err != nil {
+		if isUniqueViolation(err) {
+			return uuid.Nil, recordError(span, domain.ErrDuplicateIdempotencyKey)
+		}
+
+		return uuid.Nil, recordError(span, err)
 	}
 
 	return transfer.TransferUuid, nil
 }
 
-func (a *DatabaseAdapter) CreateTransferTransactionPair(fromAccountOrm BankAccountOrm,
-	toAccountOrm BankAccountOrm, fromTransactionOrm BankTransactionOrm,
-	toTransactionOrm BankTransactionOrm) (bool, error) {
-	tx := a.db.Begin()
+// CreateTransferTransactionPair posts both legs of a pending transfer and marks it succeeded in one
+// database transaction. Both accounts are locked in account_uuid order so concurrent transfers
+// between the same accounts can't deadlock, and the source balance is checked on the locked row.
+// It returns the source and destination accounts as they were committed.
+func (a *DatabaseAdapter) CreateTransferTransactionPair(ctx context.Context, transfer domain.Transfer,
+	fromEntry domain.LedgerEntry, toEntry domain.LedgerEntry) (domain.Account, domain.Account, error) {
+	ctx, span := startSpan(ctx, "CreateTransferTransactionPair")
+	defer span.End()
+
+	ctx, cancel := withTimeout(ctx, a.timeouts.Write)
+	defer cancel()
+
+	sourceTransactionOrm := toTransactionOrm(fromEntry)
+	destinationTransactionOrm := toTransactionOrm(toEntry)
+
+	var sourceAccountOrm, destinationAccountOrm BankAccountOrm
+
+	err := a.inTransaction(ctx, func(tx *gorm.DB) error {
+		if err := lockAccounts(tx, transfer.FromAccountUuid, transfer.ToAccountUuid); err != nil {
+			return err
+		}
+
+		// claimed first, so that of two calls posting the same transfer the one waiting for the locks
+		// finds it no longer pending
+		res := tx.Model(&BankTransferOrm{}).
+			Where("transfer_uuid = ? AND transfer_status = ?", transfer.TransferUuid, domain.TransferStatusPending).
+			Updates(map[string]interface{}{
+				"transfer_status": domain.TransferStatusSucceeded,
+				"updated_at":      time.Now(),
+			})
+
+		if res.Error != nil {
+			return res.Error
+		}
+
+		if res.RowsAffected == 0 {
+			return fmt.Errorf("%w : %v", domain.ErrTransferNotPending, transfer.TransferUuid)
+		}
+
+		if err := debitAccount(tx, transfer.FromAccountUuid, sourceTransactionOrm.Amount); err != nil {
+			return err
+		}
+
+		if err := creditAccount(tx, transfer.ToAccountUuid, destinationTransactionOrm.Amount); err != nil {
+			return err
+		}
+
+		if err := tx.Create(sourceTransactionOrm).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Create(destinationTransactionOrm).Error; err != nil {
+			return err
+		}
+
+		if err := tx.First(&sourceAccountOrm, "account_uuid = ?", transfer.FromAccountUuid).Error; err != nil {
+			return err
+		}
 
-	if err := tx.Create(fromTransactionOrm).Error; err != nil {
-		tx.Rollback()
-		return false, err
+		return tx.First(&destinationAccountOrm, "account_uuid = ?", transfer.ToAccountUuid).Error
+	})
+
+	if err != nil {
+		return domain.Account{}, domain.Account{}, recordError(span, err)
 	}
 
-	if err := tx.Create(toTransactionOrm).Error; err != nil {
-		tx.Rollback()
-		return false, err
+	sourceAccount, err := toAccount(sourceAccountOrm)
+
+	if err != nil {
+		return domain.Account{}, domain.Account{}, recordError(span, err)
 	}
 
-	fromAccountBalanceNew := fromAccountOrm.CurrentBalance - fromTransactionOrm.Amount
+	destinationAccount, err := toAccount(destinationAccountOrm)
+
+	return sourceAccount, destinationAccount, recordError(span, err)
+}
+
+// FailTransfer marks a pending transfer failed for reason.
+func (a *DatabaseAdapter) FailTransfer(ctx context.Context, transferUuid uuid.UUID, reason string) error {
+	ctx, span := startSpan(ctx, "FailTransfer")
+	defer span.End()
+
+	ctx, cancel := withTimeout(ctx, a.timeouts.Write)
+	defer cancel()
 
-	if err := tx.Model(&fromAccountOrm).Updates(
-		map[string]interface{}{
-			"current_balance": fromAccountBalanceNew,
+	res := a.db.WithContext(ctx).Model(&BankTransferOrm{}).
+		Where("transfer_uuid = ? AND transfer_status = ?", transferUuid, domain.TransferStatusPending).
+		Updates(map[string]interface{}{
+			"transfer_status": domain.TransferStatusFailed,
+			"failure_reason":  reason,
 			"updated_at":      time.Now(),
-		},
-	).Error; err != nil {
-		tx.Rollback()
-		return false, err
+		})
+
+	if res.Error != nil {
+		return recordError(span, res.Error)
 	}
 
-	toAccountBalanceNew := toAccountOrm.CurrentBalance + toTransactionOrm.Amount
+	if res.RowsAffected == 0 {
+		return recordError(span, fmt.Errorf("%w : %v", domain.ErrTransferNotPending, transferUuid))
+	}
 
-	if err := tx.Model(&toAccountOrm).Updates(
-		map[string]interface{}{
-			"current_balance": toAccountBalanceNew,
-			"updated_at":      time.Now(),
-		},
-	).Error; err != nil {
-		tx.Rollback()
-		return false, err
+	return nil
+}
+
+// IsProduction reports whether the environment setting of the database is production.
+func (a *DatabaseAdapter) IsProduction(ctx context.Context) (bool, error) {
+	ctx, span := startSpan(ctx, "IsProduction")
+	defer span.End()
+
+	ctx, cancel := withTimeout(ctx, a.timeouts.Read)
+	defer cancel()
+
+	var environments []string
+
+	if err := a.db.WithContext(ctx).Raw("SELECT value FROM bank_settings WHERE name = ?",
+		"environment").Scan(&environments).Error; err != nil {
+		return false, recordError(span, err)
 	}
 
-	tx.Commit()
+	return len(environments) > 0 && environments[0] == "production", nil
+}
+
+// SeedAccount opens acct with a zero balance and posts entries to it in one transaction, unless
+// its UUID or number is taken.
+func (a *DatabaseAdapter) SeedAccount(ctx context.Context, acct domain.Account,
+	entries []domain.LedgerEntry) (bool, error) {
+	ctx, span := startSpan(ctx, "SeedAccount")
+	defer span.End()
+
+	ctx, cancel := withTimeout(ctx, a.timeouts.Write)
+	defer cancel()
+
+	now := time.Now()
+	var created bool
+
+	err := a.inTransaction(ctx, func(tx *gorm.DB) error {
+		// reset when the transaction is retried
+		created = false
+
+		res := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&BankAccountOrm{
+			AccountUuid:    acct.AccountUuid,
+			AccountNumber:  acct.AccountNumber,
+			AccountName:    acct.AccountName,
+			OwnerSubject:   acct.OwnerSubject,
+			Currency:       acct.Currency(),
+			CurrentBalance: domain.ZeroMoney(acct.Currency()).String(),
+			CreatedAt:      now,
+			UpdatedAt:      now,
+		})
+
+		if res.Error != nil || res.RowsAffected == 0 {
+			return res.Error
+		}
 
-	return true, nil
+		for _, entry := range entries {
+			bankTrx := toTransactionOrm(entry)
+
+			if err := tx.Create(bankTrx).Error; err != nil {
+				return err
+			}
+
+			if bankTrx.TransactionType == domain.TransactionTypeOut {
+				if err := debitAccount(tx, bankTrx.AccountUuid, bankTrx.Amount); err != nil {
+					return err
+				}
+			} else if err := creditAccount(tx, bankTrx.AccountUuid, bankTrx.Amount); err != nil {
+				return err
+			}
+		}
+
+		created = true
+
+		return nil
+	})
+
+	return created, recordError(span, err)
+}
+
+func lockAccounts(tx *gorm.DB, accountUuids ...uuid.UUID) error {
+	sorted := append([]uuid.UUID(nil), accountUuids...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].String() < sorted[j].String()
+	})
+
+	for i, accountUuid := range sorted {
+		if i > 0 && accountUuid == sorted[i-1] {
+			continue
+		}
+
+		var account BankAccountOrm
+
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			First(&account, "account_uuid = ?", accountUuid).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
-func (a *DatabaseAdapter) UpdateTransferStatus(transfer BankTransferOrm, status bool) error {
-	if err := a.db.Model(&transfer).Updates(
-		map[string]interface{}{
-			"transfer_success": status,
-			"updated_at":       time.Now(),
-		},
-	).Error; err != nil {
-		return err
+func debitAccount(tx *gorm.DB, accountUuid uuid.UUID, amount string) error {
+	res := tx.Model(&BankAccountOrm{}).
+		Where("account_uuid = ? AND current_balance >= ?", accountUuid, amount).
+		Updates(map[string]interface{}{
+			"current_balance": gorm.Expr("current_balance - ?", amount),
+			"balance_version": gorm.Expr("balance_version + 1"),
+			"updated_at":      time.Now(),
+		})
+
+	if res.Error != nil {
+		return res.Error
+	}
+
+	if res.RowsAffected == 0 {
+		return domain.ErrInsufficientBalance
 	}
 
 	return nil
 }
+
+func creditAccount(tx *gorm.DB, accountUuid uuid.UUID, amount string) error {
+	return tx.Model(&BankAccountOrm{}).
+		Where("account_uuid = ?", accountUuid).
+		Updates(map[string]interface{}{
+			"current_balance": gorm.Expr("current_balance + ?", amount),
+			"balance_version": gorm.Expr("balance_version + 1"),
+			"updated_at":      time.Now(),
+		}).Error
+}
+
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
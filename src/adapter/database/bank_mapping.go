@@ -0,0 +1,215 @@
+package database
+
+import (
+	"fmt"
+	"grpcbank/src/application/domain"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// The domain entities are mapped to and from their rows here, so that GORM types stay inside the
+// adapter. Decimal strings read back are parsed strictly: a value that doesn't fit its currency
+// means the row was written by something else and is reported as an error.
+
+func toAccount(orm BankAccountOrm) (domain.Account, error) {
+	balance, err := domain.ParseMoney(orm.Currency, orm.CurrentBalance)
+
+	if err != nil {
+		return domain.Account{}, fmt.Errorf("invalid balance of account %v : %w", orm.AccountUuid, err)
+	}
+
+	return domain.Account{
+		AccountUuid:   orm.AccountUuid,
+		AccountNumber: orm.AccountNumber,
+		AccountName:   orm.AccountName,
+		OwnerSubject:  orm.OwnerSubject,
+		Balance:       balance,
+		Version:       orm.BalanceVersion,
+	}, nil
+}
+
+func toExchangeRateOrm(exchangeRate domain.ExchangeRate, now time.Time) BankExchangeRateOrm {
+	return BankExchangeRateOrm{
+		ExchangeRateUuid:   uuid.New(),
+		FromCurrency:       exchangeRate.FromCurrency,
+		ToCurrency:         exchangeRate.ToCurrency,
+		Rate:               exchangeRate.Rate.String(),
+		ValidFromTimestamp: exchangeRate.ValidFromTimestamp,
+		ValidToTimestamp:   exchangeRate.ValidToTimestamp,
+		CreatedAt:          now,
+		UpdatedAt:          now,
+	}
+}
+
+func toExchangeRate(orm BankExchangeRateOrm) (domain.ExchangeRate, error) {
+	rate, err := domain.ParseRate(orm.Rate)
+
+	if err != nil {
+		return domain.ExchangeRate{}, fmt.Errorf("invalid exchange rate %v : %w", orm.ExchangeRateUuid, err)
+	}
+
+	return domain.ExchangeRate{
+		FromCurrency:       orm.FromCurrency,
+		ToCurrency:         orm.ToCurrency,
+		Rate:               rate,
+		ValidFromTimestamp: orm.ValidFromTimestamp,
+		ValidToTimestamp:   orm.ValidToTimestamp,
+	}, nil
+}
+
+func toTransactionOrm(entry domain.LedgerEntry) BankTransactionOrm {
+	transactionOrm := BankTransactionOrm{
+		TransactionUuid:      entry.TransactionUuid,
+		AccountUuid:          entry.AccountUuid,
+		TransactionTimestamp: entry.Timestamp,
+		Amount:               entry.Amount.String(),
+		TransactionType:      entry.TransactionType,
+		Notes:                entry.Notes,
+		IdempotencyKey:       optionalString(entry.IdempotencyKey),
+		CreatedAt:            entry.Timestamp,
+		UpdatedAt:            entry.Timestamp,
+	}
+
+	if conversion := entry.Conversion; conversion != nil {
+		sourceCurrency := conversion.SourceAmount.Currency
+		sourceAmount := conversion.SourceAmount.String()
+		destinationCurrency := conversion.DestinationAmount.Currency
+		destinationAmount := conversion.DestinationAmount.String()
+		rate := conversion.Rate.String()
+
+		transactionOrm.SourceCurrency = &sourceCurrency
+		transactionOrm.SourceAmount = &sourceAmount
+		transactionOrm.DestinationCurrency = &destinationCurrency
+		transactionOrm.DestinationAmount = &destinationAmount
+		transactionOrm.ExchangeRate = &rate
+	}
+
+	return transactionOrm
+}
+
+// toLedgerEntry maps a transaction of an account in currency.
+func toLedgerEntry(orm BankTransactionOrm, currency string) (domain.LedgerEntry, error) {
+	amount, err := domain.ParseMoney(currency, orm.Amount)
+
+	if err != nil {
+		return domain.LedgerEntry{}, fmt.Errorf("invalid amount of transaction %v : %w", orm.TransactionUuid, err)
+	}
+
+	entry := domain.LedgerEntry{
+		TransactionUuid: orm.TransactionUuid,
+		AccountUuid:     orm.AccountUuid,
+		Timestamp:       orm.TransactionTimestamp,
+		Amount:          amount,
+		TransactionType: orm.TransactionType,
+		Notes:           orm.Notes,
+		IdempotencyKey:  valueOf(orm.IdempotencyKey),
+	}
+
+	if orm.SourceCurrency != nil && orm.SourceAmount != nil && orm.DestinationCurrency != nil &&
+		orm.DestinationAmount != nil && orm.ExchangeRate != nil {
+		conversion, err := toConversion(*orm.SourceCurrency, *orm.SourceAmount, *orm.DestinationCurrency,
+			*orm.DestinationAmount, *orm.ExchangeRate)
+
+		if err != nil {
+			return domain.LedgerEntry{}, fmt.Errorf("invalid conversion of transaction %v : %w",
+				orm.TransactionUuid, err)
+		}
+
+		entry.Conversion = &conversion
+	}
+
+	return entry, nil
+}
+
+func toTransferOrm(transfer domain.Transfer) BankTransferOrm {
+	return BankTransferOrm{
+		TransferUuid:        transfer.TransferUuid,
+		FromAccountUuid:     transfer.FromAccountUuid,
+		ToAccountUuid:       transfer.ToAccountUuid,
+		Currency:            transfer.Amount.Currency,
+		Amount:              transfer.Amount.String(),
+		SourceCurrency:      transfer.Conversion.SourceAmount.Currency,
+		SourceAmount:        transfer.Conversion.SourceAmount.String(),
+		DestinationCurrency: transfer.Conversion.DestinationAmount.Currency,
+		DestinationAmount:   transfer.Conversion.DestinationAmount.String(),
+		ExchangeRate:        transfer.Conversion.Rate.String(),
+		TransferTimestamp:   transfer.Timestamp,
+		TransferStatus:      transfer.Status,
+		FailureReason:       optionalString(transfer.FailureReason),
+		IdempotencyKey:      optionalString(transfer.IdempotencyKey),
+		CreatedAt:           transfer.Timestamp,
+		UpdatedAt:           transfer.Timestamp,
+	}
+}
+
+func toTransfer(orm BankTransferOrm) (domain.Transfer, error) {
+	amount, err := domain.ParseMoney(orm.Currency, orm.Amount)
+
+	if err != nil {
+		return domain.Transfer{}, fmt.Errorf("invalid amount of transfer %v : %w", orm.TransferUuid, err)
+	}
+
+	conversion, err := toConversion(orm.SourceCurrency, orm.SourceAmount, orm.DestinationCurrency,
+		orm.DestinationAmount, orm.ExchangeRate)
+
+	if err != nil {
+		return domain.Transfer{}, fmt.Errorf("invalid conversion of transfer %v : %w", orm.TransferUuid, err)
+	}
+
+	return domain.Transfer{
+		TransferUuid:    orm.TransferUuid,
+		FromAccountUuid: orm.FromAccountUuid,
+		ToAccountUuid:   orm.ToAccountUuid,
+		Amount:          amount,
+		Conversion:      conversion,
+		Timestamp:       orm.TransferTimestamp,
+		Status:          orm.TransferStatus,
+		FailureReason:   valueOf(orm.FailureReason),
+		IdempotencyKey:  valueOf(orm.IdempotencyKey),
+	}, nil
+}
+
+func toConversion(sourceCurrency string, sourceAmount string, destinationCurrency string,
+	destinationAmount string, rate string) (domain.Conversion, error) {
+	source, err := domain.ParseMoney(sourceCurrency, sourceAmount)
+
+	if err != nil {
+		return domain.Conversion{}, err
+	}
+
+	destination, err := domain.ParseMoney(destinationCurrency, destinationAmount)
+
+	if err != nil {
+		return domain.Conversion{}, err
+	}
+
+	parsedRate, err := domain.ParseRate(rate)
+
+	if err != nil {
+		return domain.Conversion{}, err
+	}
+
+	return domain.Conversion{
+		SourceAmount:      source,
+		DestinationAmount: destination,
+		Rate:              parsedRate,
+	}, nil
+}
+
+// optionalString stores an empty value as NULL, which unique indexes don't compare.
+func optionalString(s string) *string {
+	if s == "" {
+		return nil
+	}
+
+	return &s
+}
+
+func valueOf(s *string) string {
+	if s == nil {
+		return ""
+	}
+
+	return *s
+}
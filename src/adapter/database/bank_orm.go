@@ -6,12 +6,16 @@ import (
 	"github.com/google/uuid"
 )
 
+// NUMERIC columns are mapped to decimal strings so amounts and rates keep their exact value.
+
 type BankAccountOrm struct {
 	AccountUuid    uuid.UUID `gorm:"primaryKey"`
 	AccountNumber  string
 	AccountName    string
+	OwnerSubject   string
 	Currency       string
-	CurrentBalance float64
+	CurrentBalance string
+	BalanceVersion int64
 	CreatedAt      time.Time
 	UpdatedAt      time.Time
 	Transactions   []BankTransactionOrm `gorm:"foreignKey:AccountUuid"`
@@ -25,9 +29,15 @@ type BankTransactionOrm struct {
 	TransactionUuid      uuid.UUID `gorm:"primaryKey"`
 	AccountUuid          uuid.UUID
 	TransactionTimestamp time.Time
-	Amount               float64
+	Amount               string
 	TransactionType      string
 	Notes                string
+	IdempotencyKey       *string
+	SourceCurrency       *string
+	SourceAmount         *string
+	DestinationCurrency  *string
+	DestinationAmount    *string
+	ExchangeRate         *string
 	CreatedAt            time.Time
 	UpdatedAt            time.Time
 }
@@ -40,7 +50,7 @@ type BankExchangeRateOrm struct {
 	ExchangeRateUuid   uuid.UUID `gorm:"primaryKey"`
 	FromCurrency       string
 	ToCurrency         string
-	Rate               float64
+	Rate               string
 	ValidFromTimestamp time.Time
 	ValidToTimestamp   time.Time
 	CreatedAt          time.Time
@@ -52,15 +62,22 @@ func (BankExchangeRateOrm) TableName() string {
 }
 
 type BankTransferOrm struct {
-	TransferUuid      uuid.UUID `gorm:"primaryKey"`
-	FromAccountUuid   uuid.UUID
-	ToAccountUuid     uuid.UUID
-	Currency          string
-	Amount            float64
-	TransferTimestamp time.Time
-	TransferSuccess   bool
-	CreatedAt         time.Time
-	UpdatedAt         time.Time
+	TransferUuid        uuid.UUID `gorm:"primaryKey"`
+	FromAccountUuid     uuid.UUID
+	ToAccountUuid       uuid.UUID
+	Currency            string
+	Amount              string
+	SourceCurrency      string
+	SourceAmount        string
+	DestinationCurrency string
+	DestinationAmount   string
+	ExchangeRate        string
+	TransferTimestamp   time.Time
+	TransferStatus      string
+	FailureReason       *string
+	IdempotencyKey      *string
+	CreatedAt           time.Time
+	UpdatedAt           time.Time
 }
 
 func (BankTransferOrm) TableName() string {
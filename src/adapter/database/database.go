@@ -1,27 +1,110 @@
 package database
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"log/slog"
+	"time"
 
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
 
 type DatabaseAdapter struct {
-	db *gorm.DB
+	db       *gorm.DB
+	timeouts QueryTimeouts
+	logger   *slog.Logger
 }
 
-func NewDatabaseAdapter(conn *sql.DB) (*DatabaseAdapter, error) {
+// QueryTimeouts bound each adapter call on top of the deadline of its context. Read applies to
+// lookups and Write to calls creating records, including their retries. Zero disables a timeout.
+type QueryTimeouts struct {
+	Read  time.Duration
+	Write time.Duration
+}
+
+func NewDatabaseAdapter(conn *sql.DB, timeouts QueryTimeouts, logger *slog.Logger) (*DatabaseAdapter, error) {
 	db, err := gorm.Open(postgres.New(postgres.Config{
 		Conn: conn,
-	}), &gorm.Config{})
+	}), &gorm.Config{
+		Logger: gormLogger{logger: logger},
+	})
 
 	if err != nil {
 		return nil, fmt.Errorf("Can't connect database (gorm) : %v", err)
 	}
 
 	return &DatabaseAdapter{
-		db: db,
+		db:       db,
+		timeouts: timeouts,
+		logger:   logger,
 	}, nil
 }
+
+// Ping checks that the database is reachable.
+func (a *DatabaseAdapter) Ping(ctx context.Context) error {
+	sqlDB, err := a.db.DB()
+
+	if err != nil {
+		return err
+	}
+
+	return sqlDB.PingContext(ctx)
+}
+
+const maxTransactionAttempts = 5
+
+// inTransaction runs fn in a database transaction bound to ctx, retrying it when Postgres aborts
+// the transaction with a serialization failure or a detected deadlock.
+func (a *DatabaseAdapter) inTransaction(ctx context.Context, fn func(tx *gorm.DB) error) error {
+	var err error
+
+	for attempt := 1; attempt <= maxTransactionAttempts; attempt++ {
+		err = a.db.WithContext(ctx).Transaction(fn)
+
+		if !isRetryable(err) {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Duration(attempt*attempt) * 10 * time.Millisecond):
+		}
+	}
+
+	return fmt.Errorf("transaction failed after %d attempts : %w", maxTransactionAttempts, err)
+}
+
+func withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+
+	return context.WithTimeout(ctx, timeout)
+}
+
+func isRetryable(err error) bool {
+	switch sqlState(err) {
+	case "40001", "40P01":
+		return true
+	default:
+		return false
+	}
+}
+
+func isUniqueViolation(err error) bool {
+	return sqlState(err) == "23505"
+}
+
+func sqlState(err error) string {
+	var sqlErr interface{ SQLState() string }
+
+	if !errors.As(err, &sqlErr) {
+		return ""
+	}
+
+	return sqlErr.SQLState()
+}
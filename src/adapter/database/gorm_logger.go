@@ -0,0 +1,65 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+const slowQueryThreshold = 200 * time.Millisecond
+
+// gormLogger sends GORM's logs to slog. Queries are logged without their parameters, which hold
+// account numbers and names.
+type gormLogger struct {
+	logger *slog.Logger
+}
+
+func (l gormLogger) LogMode(gormlogger.LogLevel) gormlogger.Interface {
+	return l
+}
+
+func (l gormLogger) Info(ctx context.Context, msg string, args ...any) {
+	l.logger.InfoContext(ctx, fmt.Sprintf(msg, args...))
+}
+
+func (l gormLogger) Warn(ctx context.Context, msg string, args ...any) {
+	l.logger.WarnContext(ctx, fmt.Sprintf(msg, args...))
+}
+
+func (l gormLogger) Error(ctx context.Context, msg string, args ...any) {
+	l.logger.ErrorContext(ctx, fmt.Sprintf(msg, args...))
+}
+
+func (l gormLogger) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
+	elapsed := time.Since(begin)
+
+	switch {
+	case errors.Is(err, context.Canceled):
+		// the caller went away, which is no fault of the query
+		sql, rows := fc()
+		l.logger.DebugContext(ctx, "Query cancelled", slog.String("sql", sql), slog.Int64("rows", rows),
+			slog.Duration("duration", elapsed))
+	case err != nil && !errors.Is(err, gorm.ErrRecordNotFound):
+		sql, rows := fc()
+		l.logger.ErrorContext(ctx, "Query failed", slog.String("sql", sql), slog.Int64("rows", rows),
+			slog.Duration("duration", elapsed), slog.Any("error", err))
+	case elapsed > slowQueryThreshold:
+		sql, rows := fc()
+		l.logger.WarnContext(ctx, "Slow query", slog.String("sql", sql), slog.Int64("rows", rows),
+			slog.Duration("duration", elapsed))
+	case l.logger.Enabled(ctx, slog.LevelDebug):
+		sql, rows := fc()
+		l.logger.DebugContext(ctx, "Query", slog.String("sql", sql), slog.Int64("rows", rows),
+			slog.Duration("duration", elapsed))
+	}
+}
+
+// ParamsFilter drops the parameters, so that logged queries keep their placeholders.
+func (l gormLogger) ParamsFilter(ctx context.Context, sql string, params ...any) (string, []any) {
+	return sql, nil
+}
@@ -0,0 +1,126 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+// SQLiteAdapter stores the bank in a SQLite file, for single node deployments and local
+// development. Its schema is created by the migrations in src/db/sqlite_migrations.
+type SQLiteAdapter struct {
+	db       *sql.DB
+	timeouts QueryTimeouts
+	logger   *slog.Logger
+}
+
+// sqliteBusyTimeout is how long a statement waits for the lock another connection holds.
+const sqliteBusyTimeout = 5 * time.Second
+
+// OpenSQLite opens the SQLite database in the file path, creating it if needed. Foreign keys are
+// enforced, and transactions take the write lock when they begin so that concurrent transactions
+// wait for each other instead of failing when they first write.
+func OpenSQLite(path string) (*sql.DB, error) {
+	query := url.Values{}
+	query.Add("_pragma", fmt.Sprintf("busy_timeout(%d)", sqliteBusyTimeout.Milliseconds()))
+	query.Add("_pragma", "foreign_keys(1)")
+	query.Add("_pragma", "journal_mode(WAL)")
+	query.Set("_txlock", "immediate")
+
+	return sql.Open("sqlite", "file:"+path+"?"+query.Encode())
+}
+
+func NewSQLiteAdapter(conn *sql.DB, timeouts QueryTimeouts, logger *slog.Logger) *SQLiteAdapter {
+	return &SQLiteAdapter{
+		db:       conn,
+		timeouts: timeouts,
+		logger:   logger,
+	}
+}
+
+// Ping checks that the database is reachable.
+func (a *SQLiteAdapter) Ping(ctx context.Context) error {
+	return a.db.PingContext(ctx)
+}
+
+// inTransaction runs fn in a database transaction bound to ctx, retrying it when SQLite reports
+// the database as busy for longer than sqliteBusyTimeout.
+func (a *SQLiteAdapter) inTransaction(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	var err error
+
+	for attempt := 1; attempt <= maxTransactionAttempts; attempt++ {
+		err = a.runTransaction(ctx, fn)
+
+		if !isSQLiteBusy(err) {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Duration(attempt*attempt) * 10 * time.Millisecond):
+		}
+	}
+
+	return fmt.Errorf("transaction failed after %d attempts : %w", maxTransactionAttempts, err)
+}
+
+func (a *SQLiteAdapter) runTransaction(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	tx, err := a.db.BeginTx(ctx, nil)
+
+	if err != nil {
+		return err
+	}
+
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// SQLite result codes, see https://www.sqlite.org/rescode.html
+const (
+	sqliteBusy             = 5
+	sqliteBusySnapshot     = 517
+	sqliteConstraintUnique = 2067
+)
+
+func isSQLiteBusy(err error) bool {
+	code := sqliteCode(err)
+
+	return code == sqliteBusy || code == sqliteBusySnapshot
+}
+
+func isSQLiteUniqueViolation(err error) bool {
+	return sqliteCode(err) == sqliteConstraintUnique
+}
+
+func sqliteCode(err error) int {
+	var sqliteErr interface{ Code() int }
+
+	if !errors.As(err, &sqliteErr) {
+		return 0
+	}
+
+	return sqliteErr.Code()
+}
+
+// sqliteTimeLayout stores timestamps in UTC with the microseconds Postgres keeps. Its fixed width
+// lets SQLite compare and sort them as text.
+const sqliteTimeLayout = "2006-01-02T15:04:05.000000Z"
+
+func formatSQLiteTime(t time.Time) string {
+	return t.Round(time.Microsecond).UTC().Format(sqliteTimeLayout)
+}
+
+func parseSQLiteTime(s string) (time.Time, error) {
+	return time.Parse(sqliteTimeLayout, s)
+}
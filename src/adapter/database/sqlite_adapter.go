@@ -0,0 +1,726 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"grpcbank/src/application/domain"
+	"grpcbank/src/logging"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+const sqliteAccountColumns = "account_uuid, account_number, account_name, owner_subject, currency, current_balance, " +
+	"balance_version"
+
+// amounts of transactions are stored without their currency, which is the one of the account
+const sqliteTransactionColumns = "t.transaction_uuid, t.account_uuid, t.transaction_timestamp, t.amount, " +
+	"t.transaction_type, t.notes, t.idempotency_key, t.source_currency, t.source_amount, t.destination_currency, " +
+	"t.destination_amount, t.exchange_rate, t.created_at, a.currency"
+
+const sqliteTransferColumns = "transfer_uuid, from_account_uuid, to_account_uuid, currency, amount, source_currency, " +
+	"source_amount, destination_currency, destination_amount, exchange_rate, transfer_timestamp, transfer_status, " +
+	"failure_reason, idempotency_key, created_at"
+
+func (a *SQLiteAdapter) GetBankAccountByAccountNumber(ctx context.Context,
+	accountNumber string) (domain.Account, error) {
+	ctx, span := startSQLiteSpan(ctx, "GetBankAccountByAccountNumber")
+	defer span.End()
+
+	ctx, cancel := withTimeout(ctx, a.timeouts.Read)
+	defer cancel()
+
+	account, err := scanSQLiteAccount(a.db.QueryRowContext(ctx,
+		"SELECT "+sqliteAccountColumns+" FROM bank_accounts WHERE account_number = ?", accountNumber))
+
+	if err != nil {
+		a.logger.WarnContext(ctx, "Can't find bank account", slog.String(logging.AccountNumberKey, accountNumber),
+			slog.Any("error", err))
+
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.Account{}, recordError(span, fmt.Errorf("%w : %v", domain.ErrAccountNotFound, accountNumber))
+		}
+
+		return domain.Account{}, recordError(span, err)
+	}
+
+	return account, nil
+}
+
+func (a *SQLiteAdapter) CreateExchangeRate(ctx context.Context, exchangeRate domain.ExchangeRate) (uuid.UUID, error) {
+	ctx, span := startSQLiteSpan(ctx, "CreateExchangeRate")
+	defer span.End()
+
+	ctx, cancel := withTimeout(ctx, a.timeouts.Write)
+	defer cancel()
+
+	exchangeRateUuid := uuid.New()
+	now := formatSQLiteTime(time.Now())
+
+	_, err := a.db.ExecContext(ctx, "INSERT INTO bank_exchange_rates (exchange_rate_uuid, from_currency, "+
+		"to_currency, rate, valid_from_timestamp, valid_to_timestamp, created_at, updated_at) "+
+		"VALUES (?, ?, ?, ?, ?, ?, ?, ?)",
+		exchangeRateUuid, exchangeRate.FromCurrency, exchangeRate.ToCurrency, exchangeRate.Rate.String(),
+		formatSQLiteTime(exchangeRate.ValidFromTimestamp), formatSQLiteTime(exchangeRate.ValidToTimestamp), now, now)
+
+	if err != nil {
+		return uuid.Nil, recordError(span, err)
+	}
+
+	return exchangeRateUuid, nil
+}
+
+func (a *SQLiteAdapter) GetExchangeRateAtTimestamp(ctx context.Context, fromCur string, toCur string,
+	timeStamp time.Time) (domain.ExchangeRate, error) {
+	ctx, span := startSQLiteSpan(ctx, "GetExchangeRateAtTimestamp")
+	defer span.End()
+
+	ctx, cancel := withTimeout(ctx, a.timeouts.Read)
+	defer cancel()
+
+	var rate, validFrom, validTo string
+
+	// the latest starting window wins should windows overlap
+	err := a.db.QueryRowContext(ctx, "SELECT rate, valid_from_timestamp, valid_to_timestamp "+
+		"FROM bank_exchange_rates WHERE from_currency = ? AND to_currency = ? "+
+		"AND (? BETWEEN valid_from_timestamp AND valid_to_timestamp) "+
+		"ORDER BY valid_from_timestamp DESC LIMIT 1",
+		fromCur, toCur, formatSQLiteTime(timeStamp)).Scan(&rate, &validFrom, &validTo)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.ExchangeRate{}, recordError(span, fmt.Errorf("%w : %v to %v", domain.ErrExchangeRateNotFound,
+			fromCur, toCur))
+	}
+
+	if err != nil {
+		return domain.ExchangeRate{}, recordError(span, err)
+	}
+
+	exchangeRate := domain.ExchangeRate{
+		FromCurrency: fromCur,
+		ToCurrency:   toCur,
+	}
+
+	if exchangeRate.Rate, err = domain.ParseRate(rate); err != nil {
+		return domain.ExchangeRate{}, recordError(span, err)
+	}
+
+	if exchangeRate.ValidFromTimestamp, err = parseSQLiteTime(validFrom); err != nil {
+		return domain.ExchangeRate{}, recordError(span, err)
+	}
+
+	if exchangeRate.ValidToTimestamp, err = parseSQLiteTime(validTo); err != nil {
+		return domain.ExchangeRate{}, recordError(span, err)
+	}
+
+	return exchangeRate, nil
+}
+
+func (a *SQLiteAdapter) FindNextExchangeRateStart(ctx context.Context, fromCur string, toCur string,
+	after time.Time) (time.Time, bool, error) {
+	ctx, span := startSQLiteSpan(ctx, "FindNextExchangeRateStart")
+	defer span.End()
+
+	ctx, cancel := withTimeout(ctx, a.timeouts.Read)
+	defer cancel()
+
+	var validFrom string
+
+	err := a.db.QueryRowContext(ctx, "SELECT valid_from_timestamp FROM bank_exchange_rates "+
+		"WHERE from_currency = ? AND to_currency = ? AND valid_from_timestamp > ? "+
+		"ORDER BY valid_from_timestamp LIMIT 1",
+		fromCur, toCur, formatSQLiteTime(after)).Scan(&validFrom)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return time.Time{}, false, nil
+	}
+
+	if err != nil {
+		return time.Time{}, false, recordError(span, err)
+	}
+
+	next, err := parseSQLiteTime(validFrom)
+
+	if err != nil {
+		return time.Time{}, false, recordError(span, err)
+	}
+
+	return next, true, nil
+}
+
+// FindTransactionByIdempotencyKey returns the transaction created with key. A key older than
+// retainedSince has expired: it is released so it can be reused and reported as not found.
+func (a *SQLiteAdapter) FindTransactionByIdempotencyKey(ctx context.Context, key string,
+	retainedSince time.Time) (domain.LedgerEntry, bool, error) {
+	ctx, span := startSQLiteSpan(ctx, "FindTransactionByIdempotencyKey")
+	defer span.End()
+
+	ctx, cancel := withTimeout(ctx, a.timeouts.Read)
+	defer cancel()
+
+	entry, createdAt, err := scanSQLiteEntry(a.db.QueryRowContext(ctx, "SELECT "+sqliteTransactionColumns+
+		" FROM bank_transactions t JOIN bank_accounts a ON a.account_uuid = t.account_uuid "+
+		"WHERE t.idempotency_key = ?", key))
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.LedgerEntry{}, false, nil
+	}
+
+	if err != nil {
+		return domain.LedgerEntry{}, false, recordError(span, err)
+	}
+
+	if createdAt.Before(retainedSince) {
+		_, err := a.db.ExecContext(ctx, "UPDATE bank_transactions SET idempotency_key = NULL "+
+			"WHERE transaction_uuid = ?", entry.TransactionUuid)
+
+		return domain.LedgerEntry{}, false, recordError(span, err)
+	}
+
+	return entry, true, nil
+}
+
+// ListTransactions returns up to limit transactions of acct matching filter, newest first,
+// starting after the given cursor when it is set.
+func (a *SQLiteAdapter) ListTransactions(ctx context.Context, acct domain.Account, filter domain.TransactionFilter,
+	after *domain.TransactionCursor, limit int) ([]domain.LedgerEntry, error) {
+	ctx, span := startSQLiteSpan(ctx, "ListTransactions")
+	defer span.End()
+
+	ctx, cancel := withTimeout(ctx, a.timeouts.Read)
+	defer cancel()
+
+	conditions := []string{"t.account_uuid = ?"}
+	args := []any{acct.AccountUuid}
+
+	if !filter.FromTimestamp.IsZero() {
+		conditions = append(conditions, "t.transaction_timestamp >= ?")
+		args = append(args, formatSQLiteTime(filter.FromTimestamp))
+	}
+
+	if !filter.ToTimestamp.IsZero() {
+		conditions = append(conditions, "t.transaction_timestamp <= ?")
+		args = append(args, formatSQLiteTime(filter.ToTimestamp))
+	}
+
+	if filter.TransactionType != "" {
+		conditions = append(conditions, "t.transaction_type = ?")
+		args = append(args, filter.TransactionType)
+	}
+
+	if filter.MinAmount != nil {
+		conditions = append(conditions, "t.amount >= ?")
+		args = append(args, filter.MinAmount.MinorUnits)
+	}
+
+	if filter.MaxAmount != nil {
+		conditions = append(conditions, "t.amount <= ?")
+		args = append(args, filter.MaxAmount.MinorUnits)
+	}
+
+	if filter.NotesContains != "" {
+		// LIKE ignores the case of ASCII letters only, unlike ILIKE
+		conditions = append(conditions, `t.notes LIKE ? ESCAPE '\'`)
+		args = append(args, "%"+escapeLike(filter.NotesContains)+"%")
+	}
+
+	if after != nil {
+		conditions = append(conditions, "(t.transaction_timestamp, t.transaction_uuid) < (?, ?)")
+		args = append(args, formatSQLiteTime(after.Timestamp), after.TransactionUuid)
+	}
+
+	rows, err := a.db.QueryContext(ctx, "SELECT "+sqliteTransactionColumns+
+		" FROM bank_transactions t JOIN bank_accounts a ON a.account_uuid = t.account_uuid "+
+		"WHERE "+strings.Join(conditions, " AND ")+
+		" ORDER BY t.transaction_timestamp DESC, t.transaction_uuid DESC LIMIT ?", append(args, limit)...)
+
+	if err != nil {
+		return nil, recordError(span, err)
+	}
+
+	defer rows.Close()
+
+	entries := make([]domain.LedgerEntry, 0)
+
+	for rows.Next() {
+		entry, _, err := scanSQLiteEntry(rows)
+
+		if err != nil {
+			return nil, recordError(span, err)
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, recordError(span, rows.Err())
+}
+
+// CreateTransaction posts entry to its account and returns the account as it was committed.
+func (a *SQLiteAdapter) CreateTransaction(ctx context.Context, entry domain.LedgerEntry) (domain.Account, error) {
+	ctx, span := startSQLiteSpan(ctx, "CreateTransaction")
+	defer span.End()
+
+	ctx, cancel := withTimeout(ctx, a.timeouts.Write)
+	defer cancel()
+
+	var updatedAccount domain.Account
+
+	err := a.inTransaction(ctx, func(tx *sql.Tx) error {
+		var err error
+		updatedAccount, err = postSQLiteEntry(ctx, tx, entry)
+
+		return err
+	})
+
+	if err != nil {
+		return domain.Account{}, recordError(span, err)
+	}
+
+	return updatedAccount, nil
+}
+
+// CreateTransactionBatch posts every entry to its account in one database transaction, so either
+// all of them are committed or none is. It returns each account as it was right after the entry
+// at the same index was posted. On failure it returns the index of the entry that failed, or -1
+// when the failure isn't tied to a single entry.
+func (a *SQLiteAdapter) CreateTransactionBatch(ctx context.Context,
+	entries []domain.LedgerEntry) ([]domain.Account, int, error) {
+	ctx, span := startSQLiteSpan(ctx, "CreateTransactionBatch")
+	defer span.End()
+
+	ctx, cancel := withTimeout(ctx, a.timeouts.Write)
+	defer cancel()
+
+	var updatedAccounts []domain.Account
+	failedIndex := -1
+
+	err := a.inTransaction(ctx, func(tx *sql.Tx) error {
+		updatedAccounts = make([]domain.Account, 0, len(entries))
+
+		for i, entry := range entries {
+			failedIndex = i
+
+			updatedAccount, err := postSQLiteEntry(ctx, tx, entry)
+
+			if err != nil {
+				return err
+			}
+
+			updatedAccounts = append(updatedAccounts, updatedAccount)
+		}
+
+		failedIndex = -1
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, failedIndex, recordError(span, err)
+	}
+
+	return updatedAccounts, -1, nil
+}
+
+// FindTransferByIdempotencyKey returns the transfer created with key, releasing the key
+// like FindTransactionByIdempotencyKey once it is older than retainedSince.
+func (a *SQLiteAdapter) FindTransferByIdempotencyKey(ctx context.Context, key string,
+	retainedSince time.Time) (domain.Transfer, bool, error) {
+	ctx, span := startSQLiteSpan(ctx, "FindTransferByIdempotencyKey")
+	defer span.End()
+
+	ctx, cancel := withTimeout(ctx, a.timeouts.Read)
+	defer cancel()
+
+	transfer, createdAt, err := scanSQLiteTransfer(a.db.QueryRowContext(ctx,
+		"SELECT "+sqliteTransferColumns+" FROM bank_transfers WHERE idempotency_key = ?", key))
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.Transfer{}, false, nil
+	}
+
+	if err != nil {
+		return domain.Transfer{}, false, recordError(span, err)
+	}
+
+	if createdAt.Before(retainedSince) {
+		_, err := a.db.ExecContext(ctx, "UPDATE bank_transfers SET idempotency_key = NULL WHERE transfer_uuid = ?",
+			transfer.TransferUuid)
+
+		return domain.Transfer{}, false, recordError(span, err)
+	}
+
+	return transfer, true, nil
+}
+
+func (a *SQLiteAdapter) CreateTransfer(ctx context.Context, transfer domain.Transfer) (uuid.UUID, error) {
+	ctx, span := startSQLiteSpan(ctx, "CreateTransfer")
+	defer span.End()
+
+	ctx, cancel := withTimeout(ctx, a.timeouts.Write)
+	defer cancel()
+
+	timestamp := formatSQLiteTime(transfer.Timestamp)
+	conversion := transfer.Conversion
+
+	_, err := a.db.ExecContext(ctx, "INSERT INTO bank_transfers ("+sqliteTransferColumns+", updated_at) "+
+		"VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)",
+		transfer.TransferUuid, transfer.FromAccountUuid, transfer.ToAccountUuid, transfer.Amount.Currency,
+		transfer.Amount.MinorUnits, conversion.SourceAmount.Currency, conversion.SourceAmount.MinorUnits,
+		conversion.DestinationAmount.Currency, conversion.DestinationAmount.MinorUnits, conversion.Rate.String(),
+		timestamp, transfer.Status, optionalString(transfer.FailureReason), optionalString(transfer.IdempotencyKey),
+		timestamp, timestamp)
+
+	if isSQLiteUniqueViolation(err) {
+		return uuid.Nil, recordError(span, domain.ErrDuplicateIdempotencyKey)
+	}
+
+	if err != nil {
+		return uuid.Nil, recordError(span, err)
+	}
+
+	return transfer.TransferUuid, nil
+}
+
+// CreateTransferTransactionPair posts both legs of a pending transfer and marks it succeeded in one
+// database transaction, which holds the write lock of the database from its start. It returns
+// the source and destination accounts as they were committed.
+func (a *SQLiteAdapter) CreateTransferTransactionPair(ctx context.Context, transfer domain.Transfer,
+	fromEntry domain.LedgerEntry, toEntry domain.LedgerEntry) (domain.Account, domain.Account, error) {
+	ctx, span := startSQLiteSpan(ctx, "CreateTransferTransactionPair")
+	defer span.End()
+
+	ctx, cancel := withTimeout(ctx, a.timeouts.Write)
+	defer cancel()
+
+	var sourceAccount, destinationAccount domain.Account
+
+	err := a.inTransaction(ctx, func(tx *sql.Tx) error {
+		if err := updateSQLiteTransferStatus(ctx, tx, transfer.TransferUuid, domain.TransferStatusSucceeded,
+			nil); err != nil {
+			return err
+		}
+
+		if _, err := postSQLiteEntry(ctx, tx, fromEntry); err != nil {
+			return err
+		}
+
+		if _, err := postSQLiteEntry(ctx, tx, toEntry); err != nil {
+			return err
+		}
+
+		var err error
+
+		if sourceAccount, err = getSQLiteAccount(ctx, tx, transfer.FromAccountUuid); err != nil {
+			return err
+		}
+
+		destinationAccount, err = getSQLiteAccount(ctx, tx, transfer.ToAccountUuid)
+
+		return err
+	})
+
+	if err != nil {
+		return domain.Account{}, domain.Account{}, recordError(span, err)
+	}
+
+	return sourceAccount, destinationAccount, nil
+}
+
+// FailTransfer marks a pending transfer failed for reason.
+func (a *SQLiteAdapter) FailTransfer(ctx context.Context, transferUuid uuid.UUID, reason string) error {
+	ctx, span := startSQLiteSpan(ctx, "FailTransfer")
+	defer span.End()
+
+	ctx, cancel := withTimeout(ctx, a.timeouts.Write)
+	defer cancel()
+
+	err := a.inTransaction(ctx, func(tx *sql.Tx) error {
+		return updateSQLiteTransferStatus(ctx, tx, transferUuid, domain.TransferStatusFailed, &reason)
+	})
+
+	return recordError(span, err)
+}
+
+// updateSQLiteTransferStatus moves a pending transfer to status within tx, or fails with
+// domain.ErrTransferNotPending.
+func updateSQLiteTransferStatus(ctx context.Context, tx *sql.Tx, transferUuid uuid.UUID, status string,
+	reason *string) error {
+	res, err := tx.ExecContext(ctx, "UPDATE bank_transfers SET transfer_status = ?, failure_reason = ?, "+
+		"updated_at = ? WHERE transfer_uuid = ? AND transfer_status = ?", status, reason,
+		formatSQLiteTime(time.Now()), transferUuid, domain.TransferStatusPending)
+
+	if err != nil {
+		return err
+	}
+
+	rows, err := res.RowsAffected()
+
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return fmt.Errorf("%w : %v", domain.ErrTransferNotPending, transferUuid)
+	}
+
+	return nil
+}
+
+// IsProduction reports whether the environment setting of the database is production.
+func (a *SQLiteAdapter) IsProduction(ctx context.Context) (bool, error) {
+	ctx, span := startSQLiteSpan(ctx, "IsProduction")
+	defer span.End()
+
+	ctx, cancel := withTimeout(ctx, a.timeouts.Read)
+	defer cancel()
+
+	var environment string
+
+	err := a.db.QueryRowContext(ctx, "SELECT value FROM bank_settings WHERE name = ?", "environment").
+		Scan(&environment)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, recordError(span, err)
+	}
+
+	return environment == "production", nil
+}
+
+// SeedAccount opens acct with a zero balance and posts entries to it in one transaction, unless
+// its UUID or number is taken.
+func (a *SQLiteAdapter) SeedAccount(ctx context.Context, acct domain.Account,
+	entries []domain.LedgerEntry) (bool, error) {
+	ctx, span := startSQLiteSpan(ctx, "SeedAccount")
+	defer span.End()
+
+	ctx, cancel := withTimeout(ctx, a.timeouts.Write)
+	defer cancel()
+
+	var created bool
+
+	err := a.inTransaction(ctx, func(tx *sql.Tx) error {
+		// reset when the transaction is retried
+		created = false
+
+		now := formatSQLiteTime(time.Now())
+
+		res, err := tx.ExecContext(ctx, "INSERT OR IGNORE INTO bank_accounts ("+sqliteAccountColumns+
+			", created_at, updated_at) VALUES (?, ?, ?, ?, ?, 0, 0, ?, ?)", acct.AccountUuid, acct.AccountNumber,
+			acct.AccountName, acct.OwnerSubject, acct.Currency(), now, now)
+
+		if err != nil {
+			return err
+		}
+
+		rows, err := res.RowsAffected()
+
+		if err != nil || rows == 0 {
+			return err
+		}
+
+		for _, entry := range entries {
+			if _, err := postSQLiteEntry(ctx, tx, entry); err != nil {
+				return err
+			}
+		}
+
+		created = true
+
+		return nil
+	})
+
+	return created, recordError(span, err)
+}
+
+// postSQLiteEntry inserts entry and applies it to the balance of its account within tx, and
+// returns the updated account.
+func postSQLiteEntry(ctx context.Context, tx *sql.Tx, entry domain.LedgerEntry) (domain.Account, error) {
+	timestamp := formatSQLiteTime(entry.Timestamp)
+
+	var sourceCurrency, destinationCurrency, rate *string
+	var sourceAmount, destinationAmount *int64
+
+	if conversion := entry.Conversion; conversion != nil {
+		sourceCurrency = &conversion.SourceAmount.Currency
+		sourceAmount = &conversion.SourceAmount.MinorUnits
+		destinationCurrency = &conversion.DestinationAmount.Currency
+		destinationAmount = &conversion.DestinationAmount.MinorUnits
+		rateValue := conversion.Rate.String()
+		rate = &rateValue
+	}
+
+	var account domain.Account
+
+	err := tx.QueryRowContext(ctx, "SELECT 1 FROM bank_accounts WHERE account_uuid = ?", entry.AccountUuid).
+		Scan(new(int))
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return account, fmt.Errorf("%w : %v", domain.ErrAccountNotFound, entry.AccountUuid)
+	}
+
+	if err != nil {
+		return account, err
+	}
+
+	_, err = tx.ExecContext(ctx, "INSERT INTO bank_transactions (transaction_uuid, account_uuid, "+
+		"transaction_timestamp, amount, transaction_type, notes, idempotency_key, source_currency, source_amount, "+
+		"destination_currency, destination_amount, exchange_rate, created_at, updated_at) "+
+		"VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)",
+		entry.TransactionUuid, entry.AccountUuid, timestamp, entry.Amount.MinorUnits, entry.TransactionType,
+		entry.Notes, optionalString(entry.IdempotencyKey), sourceCurrency, sourceAmount, destinationCurrency,
+		destinationAmount, rate, timestamp, timestamp)
+
+	if isSQLiteUniqueViolation(err) {
+		return account, domain.ErrDuplicateIdempotencyKey
+	}
+
+	if err != nil {
+		return account, err
+	}
+
+	now := formatSQLiteTime(time.Now())
+
+	if entry.TransactionType == domain.TransactionTypeOut {
+		res, err := tx.ExecContext(ctx, "UPDATE bank_accounts SET current_balance = current_balance - ?, "+
+			"balance_version = balance_version + 1, updated_at = ? WHERE account_uuid = ? AND current_balance >= ?",
+			entry.Amount.MinorUnits, now, entry.AccountUuid, entry.Amount.MinorUnits)
+
+		if err != nil {
+			return account, err
+		}
+
+		rows, err := res.RowsAffected()
+
+		if err != nil {
+			return account, err
+		}
+
+		if rows == 0 {
+			return account, domain.ErrInsufficientBalance
+		}
+	} else if _, err := tx.ExecContext(ctx, "UPDATE bank_accounts SET current_balance = current_balance + ?, "+
+		"balance_version = balance_version + 1, updated_at = ? WHERE account_uuid = ?", entry.Amount.MinorUnits, now,
+		entry.AccountUuid); err != nil {
+		return account, err
+	}
+
+	return getSQLiteAccount(ctx, tx, entry.AccountUuid)
+}
+
+func getSQLiteAccount(ctx context.Context, tx *sql.Tx, accountUuid uuid.UUID) (domain.Account, error) {
+	return scanSQLiteAccount(tx.QueryRowContext(ctx, "SELECT "+sqliteAccountColumns+
+		" FROM bank_accounts WHERE account_uuid = ?", accountUuid))
+}
+
+func scanSQLiteAccount(row *sql.Row) (domain.Account, error) {
+	var account domain.Account
+	var currency string
+	var balance int64
+
+	if err := row.Scan(&account.AccountUuid, &account.AccountNumber, &account.AccountName, &account.OwnerSubject,
+		&currency, &balance, &account.Version); err != nil {
+		return domain.Account{}, err
+	}
+
+	account.Balance = domain.NewMoney(currency, balance)
+
+	return account, nil
+}
+
+// scanSQLiteEntry scans a row of sqliteTransactionColumns, and returns when it was created.
+func scanSQLiteEntry(row interface{ Scan(dest ...any) error }) (domain.LedgerEntry, time.Time, error) {
+	var entry domain.LedgerEntry
+	var timestamp, createdAt, currency string
+	var amount int64
+	var notes, idempotencyKey, sourceCurrency, destinationCurrency, rate sql.NullString
+	var sourceAmount, destinationAmount sql.NullInt64
+
+	if err := row.Scan(&entry.TransactionUuid, &entry.AccountUuid, &timestamp, &amount, &entry.TransactionType,
+		&notes, &idempotencyKey, &sourceCurrency, &sourceAmount, &destinationCurrency, &destinationAmount, &rate,
+		&createdAt, &currency); err != nil {
+		return domain.LedgerEntry{}, time.Time{}, err
+	}
+
+	var err error
+
+	if entry.Timestamp, err = parseSQLiteTime(timestamp); err != nil {
+		return domain.LedgerEntry{}, time.Time{}, err
+	}
+
+	created, err := parseSQLiteTime(createdAt)
+
+	if err != nil {
+		return domain.LedgerEntry{}, time.Time{}, err
+	}
+
+	entry.Amount = domain.NewMoney(currency, amount)
+	entry.Notes = notes.String
+	entry.IdempotencyKey = idempotencyKey.String
+
+	if sourceCurrency.Valid && sourceAmount.Valid && destinationCurrency.Valid && destinationAmount.Valid &&
+		rate.Valid {
+		parsedRate, err := domain.ParseRate(rate.String)
+
+		if err != nil {
+			return domain.LedgerEntry{}, time.Time{}, fmt.Errorf("invalid conversion of transaction %v : %w",
+				entry.TransactionUuid, err)
+		}
+
+		entry.Conversion = &domain.Conversion{
+			SourceAmount:      domain.NewMoney(sourceCurrency.String, sourceAmount.Int64),
+			DestinationAmount: domain.NewMoney(destinationCurrency.String, destinationAmount.Int64),
+			Rate:              parsedRate,
+		}
+	}
+
+	return entry, created, nil
+}
+
+// scanSQLiteTransfer scans a row of sqliteTransferColumns, and returns when it was created.
+func scanSQLiteTransfer(row *sql.Row) (domain.Transfer, time.Time, error) {
+	var transfer domain.Transfer
+	var currency, sourceCurrency, destinationCurrency, rate, timestamp, createdAt string
+	var amount, sourceAmount, destinationAmount int64
+	var failureReason, idempotencyKey sql.NullString
+
+	if err := row.Scan(&transfer.TransferUuid, &transfer.FromAccountUuid, &transfer.ToAccountUuid, &currency,
+		&amount, &sourceCurrency, &sourceAmount, &destinationCurrency, &destinationAmount, &rate, &timestamp,
+		&transfer.Status, &failureReason, &idempotencyKey, &createdAt); err != nil {
+		return domain.Transfer{}, time.Time{}, err
+	}
+
+	parsedRate, err := domain.ParseRate(rate)
+
+	if err != nil {
+		return domain.Transfer{}, time.Time{}, fmt.Errorf("invalid conversion of transfer %v : %w",
+			transfer.TransferUuid, err)
+	}
+
+	if transfer.Timestamp, err = parseSQLiteTime(timestamp); err != nil {
+		return domain.Transfer{}, time.Time{}, err
+	}
+
+	created, err := parseSQLiteTime(createdAt)
+
+	if err != nil {
+		return domain.Transfer{}, time.Time{}, err
+	}
+
+	transfer.Amount = domain.NewMoney(currency, amount)
+	transfer.Conversion = domain.Conversion{
+		SourceAmount:      domain.NewMoney(sourceCurrency, sourceAmount),
+		DestinationAmount: domain.NewMoney(destinationCurrency, destinationAmount),
+		Rate:              parsedRate,
+	}
+	transfer.FailureReason = failureReason.String
+	transfer.IdempotencyKey = idempotencyKey.String
+
+	return transfer, created, nil
+}
@@ -0,0 +1,87 @@
+package database
+
+import (
+	"grpcbank/src/application/domain"
+	dbmigration "grpcbank/src/db"
+	"grpcbank/src/port"
+	"grpcbank/src/port/porttest"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSQLiteAdapter(t *testing.T) {
+	porttest.TestBankDatabasePort(t, func(t *testing.T, accounts []domain.Account) port.BankDatabasePort {
+		path := filepath.Join(t.TempDir(), "bank.db")
+		migrateSQLite(t, path)
+
+		sqlDB, err := OpenSQLite(path)
+
+		if err != nil {
+			t.Fatalf("Can't open database : %v", err)
+		}
+
+		t.Cleanup(func() { sqlDB.Close() })
+
+		for _, acct := range accounts {
+			now := formatSQLiteTime(time.Now())
+
+			if _, err := sqlDB.Exec("INSERT INTO bank_accounts ("+sqliteAccountColumns+", created_at, updated_at) "+
+				"VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)", acct.AccountUuid, acct.AccountNumber, acct.AccountName,
+				acct.OwnerSubject, acct.Currency(), acct.Balance.MinorUnits, acct.Version, now, now); err != nil {
+				t.Fatalf("Can't create account : %v", err)
+			}
+		}
+
+		return NewSQLiteAdapter(sqlDB, QueryTimeouts{Read: 5 * time.Second, Write: 10 * time.Second},
+			slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelWarn})))
+	})
+}
+
+func TestSQLiteAdapterSeed(t *testing.T) {
+	porttest.TestBankSeedPort(t, func(t *testing.T, production bool) porttest.SeedDatabase {
+		path := filepath.Join(t.TempDir(), "bank.db")
+		migrateSQLite(t, path)
+
+		sqlDB, err := OpenSQLite(path)
+
+		if err != nil {
+			t.Fatalf("Can't open database : %v", err)
+		}
+
+		t.Cleanup(func() { sqlDB.Close() })
+
+		if production {
+			if _, err := sqlDB.Exec("INSERT INTO bank_settings (name, value) VALUES (?, ?)", "environment",
+				"production"); err != nil {
+				t.Fatalf("Can't flag database : %v", err)
+			}
+		}
+
+		return NewSQLiteAdapter(sqlDB, QueryTimeouts{Read: 5 * time.Second, Write: 10 * time.Second},
+			slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelWarn})))
+	})
+}
+
+func migrateSQLite(t *testing.T, path string) {
+	conn, err := OpenSQLite(path)
+
+	if err != nil {
+		t.Fatalf("Can't open database : %v", err)
+	}
+
+	migrator, err := dbmigration.NewSQLiteMigrator(conn)
+
+	if err != nil {
+		conn.Close()
+		t.Fatalf("Can't prepare migrations : %v", err)
+	}
+
+	defer migrator.Close()
+
+	if err := migrator.Up(); err != nil {
+		t.Fatalf("Can't migrate database : %v", err)
+	}
+}
@@ -0,0 +1,98 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"grpcbank/src/application/domain"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+var tracer = otel.Tracer("grpcbank/src/adapter/database")
+
+// expectedErrors are outcomes of a query rather than failures, their spans keep an unset status.
+var expectedErrors = []error{
+	context.Canceled,
+	gorm.ErrRecordNotFound,
+	domain.ErrAccountNotFound,
+	domain.ErrInsufficientBalance,
+	domain.ErrDuplicateIdempotencyKey,
+	domain.ErrExchangeRateNotFound,
+	domain.ErrTransferNotPending,
+}
+
+// namedErrors are named in spans along with expectedErrors.
+var namedErrors = []error{
+	context.DeadlineExceeded,
+	sql.ErrConnDone,
+	sql.ErrTxDone,
+}
+
+// startSpan starts the client span of a DatabaseAdapter method, named after operation.
+func startSpan(ctx context.Context, operation string) (context.Context, trace.Span) {
+	return tracer.Start(ctx, "DatabaseAdapter."+operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(semconv.DBSystemPostgreSQL, semconv.DBOperationName(operation)),
+	)
+}
+
+// startSQLiteSpan starts the client span of a SQLiteAdapter method, named after operation.
+func startSQLiteSpan(ctx context.Context, operation string) (context.Context, trace.Span) {
+	return tracer.Start(ctx, "SQLiteAdapter."+operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(semconv.DBSystemSqlite, semconv.DBOperationName(operation)),
+	)
+}
+
+// recordError adds the class of err to span and returns err, so that it can wrap the error a
+// method returns. The text of err is left out, it may hold the account numbers of the query.
+func recordError(span trace.Span, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	class := errorClass(err)
+	span.AddEvent(semconv.ExceptionEventName, trace.WithAttributes(semconv.ExceptionType(class)))
+
+	for _, expected := range expectedErrors {
+		if errors.Is(err, expected) {
+			return err
+		}
+	}
+
+	span.SetStatus(codes.Error, class)
+
+	return err
+}
+
+// errorClass names err after the first sentinel it wraps, the SQLSTATE or SQLite result code of the
+// driver error it wraps, or else the type of the innermost error.
+func errorClass(err error) string {
+	for _, sentinels := range [][]error{expectedErrors, namedErrors} {
+		for _, sentinel := range sentinels {
+			if errors.Is(err, sentinel) {
+				return sentinel.Error()
+			}
+		}
+	}
+
+	if state := sqlState(err); state != "" {
+		return "SQLSTATE " + state
+	}
+
+	if code := sqliteCode(err); code != 0 {
+		return fmt.Sprintf("SQLite result code %d", code)
+	}
+
+	for errors.Unwrap(err) != nil {
+		err = errors.Unwrap(err)
+	}
+
+	return fmt.Sprintf("%T", err)
+}
@@ -0,0 +1,72 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"grpcbank/src/application/domain"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+type sqlStateError struct{}
+
+func (sqlStateError) Error() string    { return "duplicate key (account_number)=(7835697001)" }
+func (sqlStateError) SQLState() string { return "23505" }
+
+func TestRecordErrorLeavesOutTheErrorText(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		class  string
+		failed bool
+	}{
+		{"sentinel", fmt.Errorf("can't find account number 7835697001 : %w", domain.ErrAccountNotFound),
+			domain.ErrAccountNotFound.Error(), false},
+		{"driver error", fmt.Errorf("can't open account number 7835697001 : %w", sqlStateError{}),
+			"SQLSTATE 23505", true},
+		{"other error", fmt.Errorf("can't read account number 7835697001 : %w", errors.New("broken pipe")),
+			"*errors.errorString", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := tracetest.NewSpanRecorder()
+			provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+			_, span := provider.Tracer("test").Start(context.Background(), "query")
+
+			if err := recordError(span, tt.err); err != tt.err {
+				t.Errorf("recordError returned %v, want %v", err, tt.err)
+			}
+
+			span.End()
+			ended := recorder.Ended()[0]
+
+			for _, event := range ended.Events() {
+				for _, attr := range event.Attributes {
+					if strings.Contains(attr.Value.Emit(), "7835697001") {
+						t.Errorf("event attribute %v = %q holds the account number", attr.Key, attr.Value.Emit())
+					}
+
+					if attr.Key == semconv.ExceptionTypeKey && attr.Value.AsString() != tt.class {
+						t.Errorf("exception type = %q, want %q", attr.Value.AsString(), tt.class)
+					}
+				}
+			}
+
+			if len(ended.Events()) != 1 {
+				t.Errorf("got %d events, want an exception", len(ended.Events()))
+			}
+
+			if got := ended.Status(); (got.Code == codes.Error) != tt.failed ||
+				strings.Contains(got.Description, "7835697001") {
+				t.Errorf("status = %+v, want failed %v without the account number", got, tt.failed)
+			}
+		})
+	}
+}
@@ -0,0 +1,284 @@
+package grpc
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"grpcbank/src/application/domain"
+	"math/big"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// authenticatedMethodPrefix selects the RPCs requiring a caller identity, health checks and
+// reflection stay open.
+const authenticatedMethodPrefix = "/bank.BankService/"
+
+const tokenLeeway = 30 * time.Second
+
+// Authenticator validates HS256 and RS256 JWT bearer tokens against the keys of a JWKS file and
+// puts the token subject in the context as the domain.Principal. Callers without a token are
+// identified by the subject of their verified TLS client certificate.
+type Authenticator struct {
+	keys   []verificationKey
+	parser *jwt.Parser
+}
+
+type verificationKey struct {
+	id  string
+	alg string
+	key any
+}
+
+type jsonWebKeySet struct {
+	Keys []jsonWebKey `json:"keys"`
+}
+
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	Use string `json:"use"`
+	K   string `json:"k"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+// NewAuthenticator loads the keys of jwksFile. Tokens must be issued by issuer and for audience,
+// unless they are empty. Without jwksFile only client certificates are accepted.
+func NewAuthenticator(jwksFile, issuer, audience string) (*Authenticator, error) {
+	if jwksFile == "" {
+		return &Authenticator{}, nil
+	}
+
+	content, err := os.ReadFile(jwksFile)
+
+	if err != nil {
+		return nil, fmt.Errorf("can't read JWKS file : %w", err)
+	}
+
+	var keySet jsonWebKeySet
+
+	if err := json.Unmarshal(content, &keySet); err != nil {
+		return nil, fmt.Errorf("can't parse JWKS file %v : %w", jwksFile, err)
+	}
+
+	var keys []verificationKey
+
+	for i, jwk := range keySet.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+
+		key, err := toVerificationKey(jwk)
+
+		if err != nil {
+			return nil, fmt.Errorf("invalid key %d in JWKS file %v : %w", i, jwksFile, err)
+		}
+
+		keys = append(keys, key)
+	}
+
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("no signing key found in JWKS file %v", jwksFile)
+	}
+
+	opts := []jwt.ParserOption{
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg(), jwt.SigningMethodRS256.Alg()}),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(tokenLeeway),
+	}
+
+	if issuer != "" {
+		opts = append(opts, jwt.WithIssuer(issuer))
+	}
+
+	if audience != "" {
+		opts = append(opts, jwt.WithAudience(audience))
+	}
+
+	return &Authenticator{
+		keys:   keys,
+		parser: jwt.NewParser(opts...),
+	}, nil
+}
+
+func toVerificationKey(jwk jsonWebKey) (verificationKey, error) {
+	switch jwk.Kty {
+	case "oct":
+		if jwk.Alg != "" && jwk.Alg != jwt.SigningMethodHS256.Alg() {
+			return verificationKey{}, fmt.Errorf("unsupported algorithm %v for an oct key", jwk.Alg)
+		}
+
+		secret, err := base64.RawURLEncoding.DecodeString(jwk.K)
+
+		if err != nil || len(secret) == 0 {
+			return verificationKey{}, errors.New("oct key needs a base64url encoded k")
+		}
+
+		return verificationKey{id: jwk.Kid, alg: jwt.SigningMethodHS256.Alg(), key: secret}, nil
+	case "RSA":
+		if jwk.Alg != "" && jwk.Alg != jwt.SigningMethodRS256.Alg() {
+			return verificationKey{}, fmt.Errorf("unsupported algorithm %v for an RSA key", jwk.Alg)
+		}
+
+		n, nErr := base64.RawURLEncoding.DecodeString(jwk.N)
+		e, eErr := base64.RawURLEncoding.DecodeString(jwk.E)
+
+		if nErr != nil || eErr != nil || len(n) == 0 || len(e) == 0 || len(e) > 4 {
+			return verificationKey{}, errors.New("RSA key needs a base64url encoded n and e")
+		}
+
+		publicKey := &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+
+		return verificationKey{id: jwk.Kid, alg: jwt.SigningMethodRS256.Alg(), key: publicKey}, nil
+	default:
+		return verificationKey{}, fmt.Errorf("unsupported key type %q", jwk.Kty)
+	}
+}
+
+// keyFor picks the key matching the kid and algorithm of token. A token without kid is accepted
+// only when a single key uses its algorithm.
+func (a *Authenticator) keyFor(token *jwt.Token) (any, error) {
+	kid, _ := token.Header["kid"].(string)
+	var found []verificationKey
+
+	for _, key := range a.keys {
+		if key.alg == token.Method.Alg() && (kid == "" || key.id == kid) {
+			found = append(found, key)
+		}
+	}
+
+	if len(found) != 1 {
+		return nil, fmt.Errorf("no unique %v key for kid %q", token.Method.Alg(), kid)
+	}
+
+	return found[0].key, nil
+}
+
+func (a *Authenticator) authenticate(ctx context.Context) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	authorization := md.Get("authorization")
+
+	if len(authorization) == 0 {
+		if principal, ok := clientCertificatePrincipal(ctx); ok {
+			return domain.ContextWithPrincipal(ctx, principal), nil
+		}
+
+		return nil, status.Error(codes.Unauthenticated, "missing bearer token or identifiable client certificate")
+	}
+
+	if a.parser == nil {
+		return nil, status.Error(codes.Unauthenticated, "bearer tokens aren't accepted, use a client certificate")
+	}
+
+	scheme, rawToken, ok := strings.Cut(authorization[0], " ")
+
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return nil, status.Error(codes.Unauthenticated, "authorization must be a bearer token")
+	}
+
+	token, err := a.parser.Parse(strings.TrimSpace(rawToken), a.keyFor)
+
+	if err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, "invalid token : %v", err)
+	}
+
+	subject, err := token.Claims.GetSubject()
+
+	if err != nil || subject == "" {
+		return nil, status.Error(codes.Unauthenticated, "token has no subject")
+	}
+
+	return domain.ContextWithPrincipal(ctx, domain.Principal{Subject: subject}), nil
+}
+
+// clientCertificatePrincipal maps the verified client certificate of the connection to a principal
+// named after the certificate's common name, or its first URI, DNS or email alternative name when it
+// has none. A certificate naming none of them identifies nobody.
+func clientCertificatePrincipal(ctx context.Context) (domain.Principal, bool) {
+	p, ok := peer.FromContext(ctx)
+
+	if !ok {
+		return domain.Principal{}, false
+	}
+
+	tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo)
+
+	if !ok || len(tlsInfo.State.VerifiedChains) == 0 || len(tlsInfo.State.VerifiedChains[0]) == 0 {
+		return domain.Principal{}, false
+	}
+
+	cert := tlsInfo.State.VerifiedChains[0][0]
+	names := []string{strings.TrimSpace(cert.Subject.CommonName)}
+
+	for _, uri := range cert.URIs {
+		names = append(names, uri.String())
+	}
+
+	names = append(names, cert.DNSNames...)
+	names = append(names, cert.EmailAddresses...)
+
+	for _, name := range names {
+		if name != "" {
+			return domain.Principal{Subject: name}, true
+		}
+	}
+
+	return domain.Principal{}, false
+}
+
+func (a *Authenticator) UnaryServerInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler) (any, error) {
+	if !strings.HasPrefix(info.FullMethod, authenticatedMethodPrefix) {
+		return handler(ctx, req)
+	}
+
+	ctx, err := a.authenticate(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return handler(ctx, req)
+}
+
+func (a *Authenticator) StreamServerInterceptor(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo,
+	handler grpc.StreamHandler) error {
+	if !strings.HasPrefix(info.FullMethod, authenticatedMethodPrefix) {
+		return handler(srv, stream)
+	}
+
+	ctx, err := a.authenticate(stream.Context())
+
+	if err != nil {
+		return err
+	}
+
+	return handler(srv, &contextServerStream{ServerStream: stream, ctx: ctx})
+}
+
+// contextServerStream replaces the context of a stream, so handlers see what interceptors added.
+type contextServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextServerStream) Context() context.Context {
+	return s.ctx
+}
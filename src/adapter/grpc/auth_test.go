@@ -0,0 +1,416 @@
+package grpc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"grpcbank/src/application/domain"
+	"math/big"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	testIssuer   = "https://issuer.example"
+	testAudience = "bank"
+)
+
+var (
+	testHMACSecret     = []byte("first shared secret of the bank")
+	testNextHMACSecret = []byte("second shared secret of the bank")
+)
+
+// newTestAuthenticator trusts an RSA key with kid "rsa" and two HMAC secrets with kids "hmac" and
+// "hmac-next", and returns the RSA private key.
+func newTestAuthenticator(t *testing.T) (*Authenticator, *rsa.PrivateKey) {
+	t.Helper()
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	encode := base64.RawURLEncoding.EncodeToString
+	keySet := jsonWebKeySet{Keys: []jsonWebKey{
+		{
+			Kty: "RSA",
+			Kid: "rsa",
+			Alg: "RS256",
+			Use: "sig",
+			N:   encode(privateKey.N.Bytes()),
+			E:   encode(big.NewInt(int64(privateKey.E)).Bytes()),
+		},
+		{Kty: "oct", Kid: "hmac", K: encode(testHMACSecret)},
+		{Kty: "oct", Kid: "hmac-next", K: encode(testNextHMACSecret)},
+	}}
+
+	content, err := json.Marshal(keySet)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	jwksFile := filepath.Join(t.TempDir(), "jwks.json")
+
+	if err := os.WriteFile(jwksFile, content, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	authenticator, err := NewAuthenticator(jwksFile, testIssuer, testAudience)
+
+	if err != nil {
+		t.Fatalf("NewAuthenticator : %v", err)
+	}
+
+	return authenticator, privateKey
+}
+
+func validClaims() jwt.MapClaims {
+	now := time.Now()
+
+	return jwt.MapClaims{
+		"sub": "kate",
+		"iss": testIssuer,
+		"aud": testAudience,
+		"iat": now.Unix(),
+		"exp": now.Add(time.Hour).Unix(),
+	}
+}
+
+func signToken(t *testing.T, method jwt.SigningMethod, kid string, claims jwt.MapClaims, key any) string {
+	t.Helper()
+
+	token := jwt.NewWithClaims(method, claims)
+
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+
+	signed, err := token.SignedString(key)
+
+	if err != nil {
+		t.Fatalf("SignedString : %v", err)
+	}
+
+	return signed
+}
+
+func bearerContext(token string) context.Context {
+	return metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer "+token))
+}
+
+func TestAuthenticatorAcceptsTokens(t *testing.T) {
+	authenticator, privateKey := newTestAuthenticator(t)
+
+	tests := []struct {
+		name  string
+		token func(t *testing.T) string
+	}{
+		{
+			name: "RS256",
+			token: func(t *testing.T) string {
+				return signToken(t, jwt.SigningMethodRS256, "rsa", validClaims(), privateKey)
+			},
+		},
+		{
+			name: "RS256 without kid and a single RSA key",
+			token: func(t *testing.T) string {
+				return signToken(t, jwt.SigningMethodRS256, "", validClaims(), privateKey)
+			},
+		},
+		{
+			name: "HS256",
+			token: func(t *testing.T) string {
+				return signToken(t, jwt.SigningMethodHS256, "hmac-next", validClaims(), testNextHMACSecret)
+			},
+		},
+		{
+			name: "expired within the leeway",
+			token: func(t *testing.T) string {
+				claims := validClaims()
+				claims["exp"] = time.Now().Add(-tokenLeeway / 2).Unix()
+
+				return signToken(t, jwt.SigningMethodRS256, "rsa", claims, privateKey)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, err := authenticator.authenticate(bearerContext(tt.token(t)))
+
+			if err != nil {
+				t.Fatalf("authenticate : %v", err)
+			}
+
+			principal, ok := domain.PrincipalFromContext(ctx)
+
+			if !ok || principal.Subject != "kate" {
+				t.Errorf("principal = %+v, %v, want subject kate", principal, ok)
+			}
+		})
+	}
+}
+
+func TestAuthenticatorRejectsTokens(t *testing.T) {
+	authenticator, privateKey := newTestAuthenticator(t)
+	publicKeyDER, err := x509.MarshalPKIXPublicKey(&privateKey.PublicKey)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	publicKeyPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicKeyDER})
+
+	withClaim := func(name string, value any) jwt.MapClaims {
+		claims := validClaims()
+
+		if value == nil {
+			delete(claims, name)
+		} else {
+			claims[name] = value
+		}
+
+		return claims
+	}
+
+	tests := []struct {
+		name  string
+		token func(t *testing.T) string
+	}{
+		{
+			name: "HS256 signed with the RSA public key",
+			token: func(t *testing.T) string {
+				return signToken(t, jwt.SigningMethodHS256, "rsa", validClaims(), publicKeyPEM)
+			},
+		},
+		{
+			name: "HS256 signed with the RSA public key without kid",
+			token: func(t *testing.T) string {
+				return signToken(t, jwt.SigningMethodHS256, "", validClaims(), publicKeyPEM)
+			},
+		},
+		{
+			name: "HS256 signed with the RSA modulus",
+			token: func(t *testing.T) string {
+				return signToken(t, jwt.SigningMethodHS256, "rsa", validClaims(), privateKey.N.Bytes())
+			},
+		},
+		{
+			name: "unsigned",
+			token: func(t *testing.T) string {
+				return signToken(t, jwt.SigningMethodNone, "", validClaims(), jwt.UnsafeAllowNoneSignatureType)
+			},
+		},
+		{
+			name: "unsupported algorithm",
+			token: func(t *testing.T) string {
+				return signToken(t, jwt.SigningMethodHS512, "hmac", validClaims(), testHMACSecret)
+			},
+		},
+		{
+			name: "unknown kid",
+			token: func(t *testing.T) string {
+				return signToken(t, jwt.SigningMethodRS256, "retired", validClaims(), privateKey)
+			},
+		},
+		{
+			name: "missing kid with several HMAC keys",
+			token: func(t *testing.T) string {
+				return signToken(t, jwt.SigningMethodHS256, "", validClaims(), testHMACSecret)
+			},
+		},
+		{
+			name: "kid of another key",
+			token: func(t *testing.T) string {
+				return signToken(t, jwt.SigningMethodHS256, "hmac", validClaims(), testNextHMACSecret)
+			},
+		},
+		{
+			name: "expired",
+			token: func(t *testing.T) string {
+				claims := withClaim("exp", time.Now().Add(-time.Hour).Unix())
+				return signToken(t, jwt.SigningMethodRS256, "rsa", claims, privateKey)
+			},
+		},
+		{
+			name: "without expiry",
+			token: func(t *testing.T) string {
+				return signToken(t, jwt.SigningMethodRS256, "rsa", withClaim("exp", nil), privateKey)
+			},
+		},
+		{
+			name: "not yet valid",
+			token: func(t *testing.T) string {
+				claims := withClaim("nbf", time.Now().Add(time.Hour).Unix())
+				return signToken(t, jwt.SigningMethodRS256, "rsa", claims, privateKey)
+			},
+		},
+		{
+			name: "wrong issuer",
+			token: func(t *testing.T) string {
+				claims := withClaim("iss", "https://attacker.example")
+				return signToken(t, jwt.SigningMethodRS256, "rsa", claims, privateKey)
+			},
+		},
+		{
+			name: "missing issuer",
+			token: func(t *testing.T) string {
+				return signToken(t, jwt.SigningMethodRS256, "rsa", withClaim("iss", nil), privateKey)
+			},
+		},
+		{
+			name: "wrong audience",
+			token: func(t *testing.T) string {
+				claims := withClaim("aud", []string{"brokerage"})
+				return signToken(t, jwt.SigningMethodRS256, "rsa", claims, privateKey)
+			},
+		},
+		{
+			name: "missing subject",
+			token: func(t *testing.T) string {
+				return signToken(t, jwt.SigningMethodRS256, "rsa", withClaim("sub", nil), privateKey)
+			},
+		},
+		{
+			name: "empty subject",
+			token: func(t *testing.T) string {
+				return signToken(t, jwt.SigningMethodRS256, "rsa", withClaim("sub", ""), privateKey)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, err := authenticator.authenticate(bearerContext(tt.token(t)))
+
+			if status.Code(err) != codes.Unauthenticated {
+				principal, _ := domain.PrincipalFromContext(ctx)
+				t.Fatalf("authenticate = %+v, %v, want %v", principal, err, codes.Unauthenticated)
+			}
+		})
+	}
+}
+
+func TestAuthenticatorRejectsMalformedAuthorization(t *testing.T) {
+	authenticator, _ := newTestAuthenticator(t)
+
+	for _, authorization := range []string{"", "Basic a2F0ZTpzZWNyZXQ=", "Bearer", "Bearer not.a.token"} {
+		ctx := context.Background()
+
+		if authorization != "" {
+			ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", authorization))
+		}
+
+		if _, err := authenticator.authenticate(ctx); status.Code(err) != codes.Unauthenticated {
+			t.Errorf("authenticate(%q) = %v, want %v", authorization, err, codes.Unauthenticated)
+		}
+	}
+}
+
+func certificateContext(cert *x509.Certificate) context.Context {
+	var state tls.ConnectionState
+
+	if cert != nil {
+		state.VerifiedChains = [][]*x509.Certificate{{cert}}
+	}
+
+	return peer.NewContext(context.Background(), &peer.Peer{AuthInfo: credentials.TLSInfo{State: state}})
+}
+
+func TestClientCertificatePrincipal(t *testing.T) {
+	spiffeID, err := url.Parse("spiffe://bank.example/teller")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		ctx    context.Context
+		want   string
+		wantOk bool
+	}{
+		{
+			name: "common name",
+			ctx: certificateContext(&x509.Certificate{
+				Subject:  pkix.Name{CommonName: "teller-1", Organization: []string{"Bank"}},
+				DNSNames: []string{"teller.bank.example"},
+			}),
+			want:   "teller-1",
+			wantOk: true,
+		},
+		{
+			name: "URI alternative name",
+			ctx: certificateContext(&x509.Certificate{
+				URIs:     []*url.URL{spiffeID},
+				DNSNames: []string{"teller.bank.example"},
+			}),
+			want:   "spiffe://bank.example/teller",
+			wantOk: true,
+		},
+		{
+			name:   "DNS alternative name",
+			ctx:    certificateContext(&x509.Certificate{DNSNames: []string{"teller.bank.example"}}),
+			want:   "teller.bank.example",
+			wantOk: true,
+		},
+		{
+			name:   "email alternative name",
+			ctx:    certificateContext(&x509.Certificate{EmailAddresses: []string{"teller@bank.example"}}),
+			want:   "teller@bank.example",
+			wantOk: true,
+		},
+		{
+			name: "neither common name nor alternative name",
+			ctx: certificateContext(&x509.Certificate{
+				Subject: pkix.Name{CommonName: " ", Organization: []string{"Bank"}},
+			}),
+		},
+		{
+			name: "no verified chain",
+			ctx:  certificateContext(nil),
+		},
+		{
+			name: "no peer",
+			ctx:  context.Background(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			principal, ok := clientCertificatePrincipal(tt.ctx)
+
+			if ok != tt.wantOk || principal.Subject != tt.want {
+				t.Errorf("clientCertificatePrincipal = %+v, %v, want subject %q, %v", principal, ok, tt.want,
+					tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestAuthenticatorRejectsUnidentifiableCertificate(t *testing.T) {
+	authenticator, _ := newTestAuthenticator(t)
+	ctx := certificateContext(&x509.Certificate{Subject: pkix.Name{Organization: []string{"Bank"}}})
+
+	if _, err := authenticator.authenticate(ctx); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("authenticate = %v, want %v", err, codes.Unauthenticated)
+	}
+}
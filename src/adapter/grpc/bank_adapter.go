@@ -2,22 +2,33 @@ package grpc
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/google/uuid"
 	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"grpcbank/generated_proto/bank"
+	"grpcbank/src/application"
 	"grpcbank/src/application/domain"
 	"io"
-	"log"
+	"log/slog"
+	"strings"
 	"time"
 )
 
 func (a *GrpcAdapter) GetCurrentBalance(ctx context.Context,
 	req *bank.CurrentBalanceRequest) (*bank.CurrentBalanceResponse, error) {
 	now := time.Now()
-	currentBalance, err := a.bankService.FindCurrentBalance(req.AccountNumber)
+	currentBalance, err := a.bankService.FindCurrentBalance(ctx, req.AccountNumber)
+
+	if errors.Is(err, domain.ErrPermissionDenied) {
+		return nil, permissionDeniedError(err)
+	}
+
+	if err != nil && !isBusinessError(err) {
+		return nil, a.unavailableError(ctx, err)
+	}
 
 	if err != nil {
 		return nil, status.Errorf(
@@ -27,7 +38,7 @@ func (a *GrpcAdapter) GetCurrentBalance(ctx context.Context,
 	}
 
 	return &bank.CurrentBalanceResponse{
-		Amount:      currentBalance,
+		Amount:      toProtoMoney(currentBalance),
 		CurrentDate: now.Format("2006-01-02 15:04:05"),
 	}, nil
 }
@@ -36,44 +47,71 @@ func (a *GrpcAdapter) FetchExchangeRates(req *bank.ExchangeRateRequest,
 	stream bank.BankService_FetchExchangeRatesServer) error {
 
 	context := stream.Context()
+	pairs := toCurrencyPairs(req)
+
+	rates, stop, err := a.bankService.WatchExchangeRates(context, pairs)
+
+	if errors.Is(err, domain.ErrPermissionDenied) {
+		return permissionDeniedError(err)
+	}
+
+	if err == nil && len(pairs) == 0 {
+		stop()
+	}
+
+	// only a missing rate is the fault of the client, storage failures are worth retrying
+	if err != nil && !errors.Is(err, domain.ErrExchangeRateNotFound) {
+		return a.unavailableError(context, err)
+	}
+
+	if err != nil || len(pairs) == 0 {
+		s := status.New(codes.InvalidArgument,
+			"Currency not valid. Please use valid currency for both from and to")
+		s, _ = s.WithDetails(&errdetails.ErrorInfo{
+			Domain: errorDomain,
+			Reason: "INVALID_CURRENCY",
+			Metadata: map[string]string{
+				"from_currency":  req.FromCurrency,
+				"to_currency":    req.ToCurrency,
+				"currency_pairs": formatCurrencyPairs(pairs),
+			},
+		})
+
+		return s.Err()
+	}
+
+	defer stop()
 
 	for {
 		select {
 		case <-context.Done():
-			log.Println("Client cancelled stream")
+			a.logger.DebugContext(context, "Client cancelled stream")
 			return nil
-		default:
-			now := time.Now().Truncate(time.Second)
-			rate, err := a.bankService.FindExchangeRate(req.FromCurrency, req.ToCurrency, now)
-
-			if err != nil {
-				s := status.New(codes.InvalidArgument,
-					"Currency not valid. Please use valid currency for both from and to")
-				s, _ = s.WithDetails(&errdetails.ErrorInfo{
-					Domain: "my-bank-website.com",
-					Reason: "INVALID_CURRENCY",
-					Metadata: map[string]string{
-						"from_currency": req.FromCurrency,
-						"to_currency":   req.ToCurrency,
-					},
-				})
-
-				return s.Err()
+		case <-a.draining:
+			return errShuttingDown
+		case rate, ok := <-rates:
+			if !ok {
+				return nil
 			}
 
-			stream.Send(
+			err := stream.Send(
 				&bank.ExchangeRateResponse{
-					FromCurrency: req.FromCurrency,
-					ToCurrency:   req.ToCurrency,
-					Rate:         rate,
-					Timestamp:    now.Format(time.RFC3339),
+					FromCurrency:       rate.FromCurrency,
+					ToCurrency:         rate.ToCurrency,
+					Rate:               rate.Rate.String(),
+					Timestamp:          time.Now().Format(time.RFC3339),
+					ValidFromTimestamp: rate.ValidFromTimestamp.Format(time.RFC3339Nano),
+					ValidToTimestamp:   rate.ValidToTimestamp.Format(time.RFC3339Nano),
 				},
 			)
 
-			log.Printf("Exchange rate sent to client, %v to %v : %v\n", req.FromCurrency,
-				req.ToCurrency, rate)
+			if err != nil {
+				return streamError(err)
+			}
 
-			time.Sleep(3 * time.Second)
+			a.logger.DebugContext(context, "Exchange rate sent to client",
+				slog.String("from_currency", rate.FromCurrency), slog.String("to_currency", rate.ToCurrency),
+				slog.String("rate", rate.Rate.String()))
 		}
 	}
 }
@@ -81,9 +119,6 @@ func (a *GrpcAdapter) FetchExchangeRates(req *bank.ExchangeRateRequest,
 func (a *GrpcAdapter) SummarizeTransactions(stream bank.BankService_SummarizeTransactionsServer) error {
 	trxSummary := domain.TransactionSummary{
 		SummaryOnDate: time.Now(),
-		SumIn:         0,
-		SumOut:        0,
-		SumTotal:      0,
 	}
 	acct := ""
 
@@ -93,132 +128,654 @@ func (a *GrpcAdapter) SummarizeTransactions(stream bank.BankService_SummarizeTra
 		if err == io.EOF {
 			res := bank.TransactionSummary{
 				AccountNumber:   acct,
-				SumAmountIn:     trxSummary.SumIn,
-				SumAmountOut:    trxSummary.SumOut,
-				SumTotal:        trxSummary.SumTotal,
+				SumAmountIn:     toProtoMoney(trxSummary.SumIn),
+				SumAmountOut:    toProtoMoney(trxSummary.SumOut),
+				SumTotal:        toProtoMoney(trxSummary.SumTotal),
 				TransactionDate: trxSummary.SummaryOnDate.Format("2006-01-02 15:04:05"),
 			}
 
-			return stream.SendAndClose(&res)
+			if err := stream.SendAndClose(&res); err != nil {
+				a.logger.WarnContext(stream.Context(), "Can't send response to client", slog.Any("error", err))
+				return streamError(err)
+			}
+
+			return nil
 		}
 
 		if err != nil {
-			log.Fatalln("Error while reading from client :", err)
+			a.logger.WarnContext(stream.Context(), "Can't read from client", slog.Any("error", err))
+			return streamError(err)
 		}
 
 		acct = req.AccountNumber
-		timeStamp, err := toTime(req.Timestamp)
+		bankTrx, err := toDomainTransaction(req)
 
-		if err != nil {
-			log.Fatalf("Error while parsing timestamp %v : %v", req.Timestamp, err)
+		if errors.Is(err, errInvalidTimestamp) {
+			return badRequestError(codes.InvalidArgument, err.Error(),
+				"timestamp", "Timestamp must be in DD-MM-YYYY HH:MM:SS format")
 		}
 
-		trxType := domain.TransactionTypeUnknown
+		if err != nil {
+			return badRequestError(codes.InvalidArgument, err.Error(), "amount", "Invalid amount")
+		}
 
-		if req.Type == bank.TransactionType_TRANSACTION_TYPE_IN {
-			trxType = domain.TransactionTypeIn
-		} else if req.Type == bank.TransactionType_TRANSACTION_TYPE_OUT {
-			trxType = domain.TransactionTypeOut
+		// checked before posting, a transaction that can't be summed up is not posted either
+		if err := trxSummary.Accepts(bankTrx.Amount); err != nil {
+			return badRequestError(codes.InvalidArgument, err.Error(), "amount.currency_code",
+				"Transactions of one summary must all be in the same currency")
 		}
 
-		bankTrx := domain.Transaction{
-			Amount:          req.Amount,
-			Timestamp:       timeStamp,
-			TransactionType: trxType,
+		_, err = a.bankService.CreateTransaction(stream.Context(), req.AccountNumber, bankTrx)
+
+		switch {
+		case err == nil:
+		case errors.Is(err, domain.ErrPermissionDenied):
+			return permissionDeniedError(err)
+		case errors.Is(err, domain.ErrIdempotencyKeyReused):
+			return badRequestError(codes.AlreadyExists, err.Error(), "idempotency_key",
+				"Idempotency key was already used for a different transaction")
+		case errors.Is(err, domain.ErrAccountNotFound):
+			return badRequestError(codes.InvalidArgument, err.Error(), "account_number",
+				"Invalid account number")
+		case errors.Is(err, domain.ErrInsufficientBalance):
+			return badRequestError(codes.InvalidArgument, err.Error(), "amount",
+				fmt.Sprintf("Requested amount %v exceed available balance", bankTrx.Amount))
+		case errors.Is(err, domain.ErrInvalidTransactionType):
+			return badRequestError(codes.InvalidArgument, err.Error(), "type", "Invalid transaction type")
+		case isBusinessError(err):
+			return badRequestError(codes.InvalidArgument, err.Error(), "amount", "Invalid amount")
+		default:
+			return a.unavailableError(stream.Context(), err)
 		}
 
-		accountUuid, err := a.bankService.CreateTransaction(req.AccountNumber, bankTrx)
+		err = a.bankService.CalculateTransactionSummary(stream.Context(), &trxSummary, bankTrx)
 
-		if err != nil && accountUuid == uuid.Nil {
-			s := status.New(codes.InvalidArgument, err.Error())
-			s, _ = s.WithDetails(&errdetails.BadRequest{
-				FieldViolations: []*errdetails.BadRequest_FieldViolation{
-					{
-						Field:       "account_number",
-						Description: "Invalid account number",
-					},
-				},
-			})
+		if err != nil {
+			return badRequestError(codes.InvalidArgument, err.Error(), "amount", "Invalid amount")
+		}
+	}
+}
 
-			return s.Err()
-		} else if err != nil && accountUuid != uuid.Nil {
-			s := status.New(codes.InvalidArgument, err.Error())
-			s, _ = s.WithDetails(&errdetails.BadRequest{
-				FieldViolations: []*errdetails.BadRequest_FieldViolation{
-					{
-						Field:       "amount",
-						Description: fmt.Sprintf("Requested amount %v exceed available balance", req.Amount),
-					},
-				},
-			})
+func (a *GrpcAdapter) ProcessTransactions(stream bank.BankService_ProcessTransactionsServer) error {
+	atomic := false
+	var bankTrxs []domain.Transaction
+	var parseErrs []error
+	var idempotencyKeys []string
+
+	for index := 0; ; index++ {
+		req, err := stream.Recv()
 
-			return s.Err()
+		if err == io.EOF {
+			break
 		}
 
 		if err != nil {
-			log.Println("Error while creating transaction :", err)
+			a.logger.WarnContext(stream.Context(), "Can't read from client", slog.Any("error", err))
+			return streamError(err)
 		}
 
-		err = a.bankService.CalculateTransactionSummary(&trxSummary, bankTrx)
+		if index == 0 {
+			atomic = req.Atomic
+		}
 
-		if err != nil {
-			return err
+		if req.Transaction == nil {
+			return badRequestError(codes.InvalidArgument, "transaction is required", "transaction",
+				"Every message must carry a transaction")
 		}
+
+		bankTrx, parseErr := toDomainTransaction(req.Transaction)
+		bankTrx.AccountNumber = req.Transaction.AccountNumber
+
+		if !atomic {
+			result := domain.TransactionResult{
+				Status: domain.TransactionResultFailed,
+				Err:    parseErr,
+			}
+
+			if parseErr == nil {
+				result.TransactionUuid, result.Err = a.bankService.CreateTransaction(stream.Context(),
+					bankTrx.AccountNumber, bankTrx)
+
+				if result.Err == nil {
+					result.Status = domain.TransactionResultSuccess
+				}
+			}
+
+			res := a.toProtoTransactionResult(stream.Context(), index, req.Transaction.IdempotencyKey, result)
+
+			if err := stream.Send(res); err != nil {
+				a.logger.WarnContext(stream.Context(), "Can't send response to client", slog.Any("error", err))
+				return streamError(err)
+			}
+
+			continue
+		}
+
+		if len(bankTrxs) == application.MaxTransactionBatchSize {
+			return status.Errorf(codes.ResourceExhausted,
+				"an atomic stream accepts at most %d transactions", application.MaxTransactionBatchSize)
+		}
+
+		bankTrxs = append(bankTrxs, bankTrx)
+		parseErrs = append(parseErrs, parseErr)
+		idempotencyKeys = append(idempotencyKeys, req.Transaction.IdempotencyKey)
+	}
+
+	if !atomic || len(bankTrxs) == 0 {
+		return nil
+	}
+
+	results, err := a.applyAtomically(stream.Context(), bankTrxs, parseErrs)
+
+	if errors.Is(err, domain.ErrPermissionDenied) {
+		return permissionDeniedError(err)
+	}
+
+	if err != nil {
+		return a.unavailableError(stream.Context(), err)
 	}
+
+	for index, result := range results {
+		res := a.toProtoTransactionResult(stream.Context(), index, idempotencyKeys[index], result)
+
+		if err := stream.Send(res); err != nil {
+			a.logger.WarnContext(stream.Context(), "Can't send response to client", slog.Any("error", err))
+			return streamError(err)
+		}
+	}
+
+	return nil
 }
 
+// applyAtomically reports every unparseable transaction as failed and the rest as not applied,
+// or hands the whole batch to the service when all of them could be parsed.
+func (a *GrpcAdapter) applyAtomically(ctx context.Context, bankTrxs []domain.Transaction,
+	parseErrs []error) ([]domain.TransactionResult, error) {
+	results := make([]domain.TransactionResult, len(bankTrxs))
+	parseFailed := false
+
+	for index, parseErr := range parseErrs {
+		results[index].Status = domain.TransactionResultNotApplied
+
+		if parseErr != nil {
+			parseFailed = true
+			results[index] = domain.TransactionResult{
+				Status: domain.TransactionResultFailed,
+				Err:    parseErr,
+			}
+		}
+	}
+
+	if parseFailed {
+		return results, nil
+	}
+
+	return a.bankService.CreateTransactionsAtomically(ctx, bankTrxs)
+}
+
+// TransferMultiple stops taking transfers once the server drains, so that it can stop without
+// cancelling them. The transfer in progress still completes and is answered.
 func (a *GrpcAdapter) TransferMultiple(stream bank.BankService_TransferMultipleServer) error {
 	context := stream.Context()
+	requests, recvErr := receiveTransfers(stream)
 
 	for {
 		select {
 		case <-context.Done():
-			log.Println("Client cancelled stream")
+			a.logger.DebugContext(context, "Client cancelled stream")
 			return nil
-		default:
-			req, err := stream.Recv()
-
+		case <-a.draining:
+			return errShuttingDown
+		case err := <-recvErr:
 			if err == io.EOF {
 				return nil
 			}
 
-			if err != nil {
-				log.Fatalln("Error while reading from client :", err)
+			a.logger.WarnContext(stream.Context(), "Can't read from client", slog.Any("error", err))
+			return streamError(err)
+		case req := <-requests:
+			// a request received as the server started draining is not started either
+			select {
+			case <-a.draining:
+				return errShuttingDown
+			default:
 			}
 
-			tt := domain.TransferTransaction{
-				FromAccountNumber: req.FromAccountNumber,
-				ToAccountNumber:   req.ToAccountNumber,
-				Currency:          req.Currency,
-				Amount:            req.Amount,
-			}
+			var transfer domain.Transfer
+			amount, err := toDomainMoney(req.Amount)
+
+			if err == nil {
+				tt := domain.TransferTransaction{
+					FromAccountNumber: req.FromAccountNumber,
+					ToAccountNumber:   req.ToAccountNumber,
+					Amount:            amount,
+					IdempotencyKey:    req.IdempotencyKey,
+				}
+
+				transfer, err = a.bankService.Transfer(context, tt)
+
+				if errors.Is(err, domain.ErrPermissionDenied) {
+					return permissionDeniedError(err)
+				}
 
-			_, transferSuccess, err := a.bankService.Transfer(tt)
+				if err != nil && !isBusinessError(err) {
+					return a.unavailableError(stream.Context(), err)
+				}
+			}
 
 			res := bank.TransferResponse{
 				FromAccountNumber: req.FromAccountNumber,
 				ToAccountNumber:   req.ToAccountNumber,
-				Currency:          req.Currency,
 				Amount:            req.Amount,
 				Timestamp:         time.Now().Format(time.RFC3339),
 			}
 
-			if transferSuccess {
+			if transfer.TransferUuid != uuid.Nil {
+				res.TransferUuid = transfer.TransferUuid.String()
+				res.SourceAmount = toProtoMoney(transfer.Conversion.SourceAmount)
+				res.DestinationAmount = toProtoMoney(transfer.Conversion.DestinationAmount)
+				res.ExchangeRate = transfer.Conversion.Rate.String()
+			}
+
+			if transfer.Status == domain.TransferStatusSucceeded {
 				res.Status = bank.TransferStatus_TRANSFER_STATUS_SUCCESS
 			} else {
 				res.Status = bank.TransferStatus_TRANSFER_STATUS_FAILED
 			}
 
+			if err != nil {
+				res.ErrorReason = errorReason(err)
+				res.ErrorMessage = err.Error()
+			}
+
 			err = stream.Send(&res)
 
 			if err != nil {
-				log.Fatalln("Error while sending response to client :", err)
+				a.logger.WarnContext(stream.Context(), "Can't send response to client", slog.Any("error", err))
+				return streamError(err)
 			}
 		}
 	}
 }
 
+// receiveTransfers reads the requests of stream until it fails, so that they can be waited for
+// along with the server draining. The error ending the stream, io.EOF included, goes to recvErr.
+func receiveTransfers(stream bank.BankService_TransferMultipleServer) (<-chan *bank.TransferRequest,
+	<-chan error) {
+	requests := make(chan *bank.TransferRequest)
+	recvErr := make(chan error, 1)
+
+	go func() {
+		for {
+			req, err := stream.Recv()
+
+			if err != nil {
+				recvErr <- err
+				return
+			}
+
+			select {
+			case requests <- req:
+			case <-stream.Context().Done():
+				return
+			}
+		}
+	}()
+
+	return requests, recvErr
+}
+
+func (a *GrpcAdapter) ListTransactions(ctx context.Context,
+	req *bank.ListTransactionsRequest) (*bank.ListTransactionsResponse, error) {
+	filter, violations := toTransactionFilter(req)
+
+	if len(violations) > 0 {
+		s := status.New(codes.InvalidArgument, "Invalid transaction filter")
+		s, _ = s.WithDetails(&errdetails.BadRequest{
+			FieldViolations: violations,
+		})
+
+		return nil, s.Err()
+	}
+
+	transactions, nextPageToken, err := a.bankService.ListTransactions(ctx, req.AccountNumber, filter,
+		int(req.PageSize), req.PageToken)
+
+	if errors.Is(err, domain.ErrPermissionDenied) {
+		return nil, permissionDeniedError(err)
+	}
+
+	if errors.Is(err, domain.ErrInvalidPageToken) {
+		return nil, badRequestError(codes.InvalidArgument, err.Error(), "page_token", "Invalid page token")
+	}
+
+	if errors.Is(err, domain.ErrCurrencyMismatch) {
+		s := status.New(codes.InvalidArgument, err.Error())
+		s, _ = s.WithDetails(&errdetails.BadRequest{
+			FieldViolations: amountFilterViolations(req),
+		})
+
+		return nil, s.Err()
+	}
+
+	if err != nil && !isBusinessError(err) {
+		return nil, a.unavailableError(ctx, err)
+	}
+
+	if err != nil {
+		return nil, status.Errorf(
+			codes.FailedPrecondition,
+			"can't list transactions of account %v", req.AccountNumber,
+		)
+	}
+
+	res := &bank.ListTransactionsResponse{
+		Transactions:  make([]*bank.TransactionRecord, 0, len(transactions)),
+		NextPageToken: nextPageToken,
+	}
+
+	for _, trx := range transactions {
+		res.Transactions = append(res.Transactions, toProtoTransactionRecord(trx))
+	}
+
+	return res, nil
+}
+
+func (a *GrpcAdapter) WatchBalance(req *bank.WatchBalanceRequest,
+	stream bank.BankService_WatchBalanceServer) error {
+	context := stream.Context()
+
+	updates, unsubscribe, err := a.bankService.WatchBalance(context, req.AccountNumber)
+
+	if errors.Is(err, domain.ErrPermissionDenied) {
+		return permissionDeniedError(err)
+	}
+
+	if err != nil && !isBusinessError(err) {
+		return a.unavailableError(stream.Context(), err)
+	}
+
+	if err != nil {
+		return status.Errorf(
+			codes.FailedPrecondition,
+			"account %v not found", req.AccountNumber,
+		)
+	}
+
+	defer unsubscribe()
+
+	for {
+		select {
+		case <-context.Done():
+			a.logger.DebugContext(context, "Client cancelled stream")
+			return nil
+		case <-a.draining:
+			return errShuttingDown
+		case update, ok := <-updates:
+			if !ok {
+				return nil
+			}
+
+			err := stream.Send(toProtoBalanceUpdate(update))
+
+			if err != nil {
+				return streamError(err)
+			}
+		}
+	}
+}
+
+// toProtoBalanceUpdate leaves the transaction unset on the update carrying the balance at
+// subscription time.
+func toProtoBalanceUpdate(update domain.BalanceUpdate) *bank.BalanceUpdate {
+	if update.Transaction.TransactionUuid == uuid.Nil {
+		return &bank.BalanceUpdate{
+			AccountNumber: update.AccountNumber,
+			Balance:       toProtoMoney(update.Balance),
+			Timestamp:     time.Now().Format(time.RFC3339),
+			Version:       update.Version,
+		}
+	}
+
+	return &bank.BalanceUpdate{
+		AccountNumber: update.AccountNumber,
+		Balance:       toProtoMoney(update.Balance),
+		Transaction:   toProtoTransactionRecord(update.Transaction),
+		Timestamp:     update.Transaction.Timestamp.Format(time.RFC3339),
+		Version:       update.Version,
+	}
+}
+
+// toCurrencyPairs collects the top level pair and the additional pairs of req, without duplicates.
+func toCurrencyPairs(req *bank.ExchangeRateRequest) []domain.CurrencyPair {
+	var pairs []domain.CurrencyPair
+	seen := make(map[domain.CurrencyPair]bool)
+
+	add := func(fromCurrency string, toCurrency string) {
+		pair := domain.CurrencyPair{FromCurrency: fromCurrency, ToCurrency: toCurrency}
+
+		if (fromCurrency == "" && toCurrency == "") || seen[pair] {
+			return
+		}
+
+		seen[pair] = true
+		pairs = append(pairs, pair)
+	}
+
+	add(req.FromCurrency, req.ToCurrency)
+
+	for _, pair := range req.Pairs {
+		add(pair.FromCurrency, pair.ToCurrency)
+	}
+
+	return pairs
+}
+
+func formatCurrencyPairs(pairs []domain.CurrencyPair) string {
+	formatted := make([]string, 0, len(pairs))
+
+	for _, pair := range pairs {
+		formatted = append(formatted, pair.FromCurrency+"/"+pair.ToCurrency)
+	}
+
+	return strings.Join(formatted, ",")
+}
+
+// toDomainTransaction converts a streamed transaction, failing with errInvalidTimestamp or
+// domain.ErrInvalidAmount when a field can't be parsed.
+func toDomainTransaction(req *bank.Transaction) (domain.Transaction, error) {
+	timeStamp, err := toTime(req.Timestamp)
+
+	if err != nil {
+		return domain.Transaction{}, fmt.Errorf("%w : can't parse timestamp %q", errInvalidTimestamp, req.Timestamp)
+	}
+
+	amount, err := toDomainMoney(req.Amount)
+
+	if err != nil {
+		return domain.Transaction{}, err
+	}
+
+	return domain.Transaction{
+		Amount:          amount,
+		Timestamp:       timeStamp,
+		TransactionType: toDomainTransactionType(req.Type),
+		IdempotencyKey:  req.IdempotencyKey,
+	}, nil
+}
+
+func (a *GrpcAdapter) toProtoTransactionResult(ctx context.Context, index int, idempotencyKey string,
+	result domain.TransactionResult) *bank.TransactionResult {
+	res := &bank.TransactionResult{
+		Index:          int32(index),
+		IdempotencyKey: idempotencyKey,
+	}
+
+	if result.TransactionUuid != uuid.Nil && result.Status == domain.TransactionResultSuccess {
+		res.TransactionUuid = result.TransactionUuid.String()
+	}
+
+	switch result.Status {
+	case domain.TransactionResultSuccess:
+		res.Status = bank.TransactionResultStatus_TRANSACTION_RESULT_STATUS_SUCCESS
+	case domain.TransactionResultNotApplied:
+		res.Status = bank.TransactionResultStatus_TRANSACTION_RESULT_STATUS_NOT_APPLIED
+	default:
+		res.Status = bank.TransactionResultStatus_TRANSACTION_RESULT_STATUS_FAILED
+	}
+
+	if result.Err != nil {
+		res.ErrorReason = errorReason(result.Err)
+		res.ErrorMessage = result.Err.Error()
+
+		if !isBusinessError(result.Err) {
+			a.logger.ErrorContext(ctx, "Storage failure", slog.Any("error", result.Err))
+			res.ErrorMessage = "Bank storage is temporarily unavailable, please retry"
+		}
+	}
+
+	return res
+}
+
+func toTransactionFilter(req *bank.ListTransactionsRequest) (domain.TransactionFilter,
+	[]*errdetails.BadRequest_FieldViolation) {
+	var filter domain.TransactionFilter
+	var violations []*errdetails.BadRequest_FieldViolation
+
+	if req.FromTimestamp != "" {
+		from, err := time.Parse(time.RFC3339, req.FromTimestamp)
+
+		if err != nil {
+			violations = append(violations, &errdetails.BadRequest_FieldViolation{
+				Field:       "from_timestamp",
+				Description: "Timestamp must be in RFC 3339 format",
+			})
+		}
+
+		filter.FromTimestamp = from
+	}
+
+	if req.ToTimestamp != "" {
+		to, err := time.Parse(time.RFC3339, req.ToTimestamp)
+
+		if err != nil {
+			violations = append(violations, &errdetails.BadRequest_FieldViolation{
+				Field:       "to_timestamp",
+				Description: "Timestamp must be in RFC 3339 format",
+			})
+		}
+
+		filter.ToTimestamp = to
+	}
+
+	if req.Type != bank.TransactionType_TRANSACTION_TYPE_UNSPECIFIED {
+		filter.TransactionType = toDomainTransactionType(req.Type)
+	}
+
+	if req.MinAmount != nil {
+		minAmount, err := toDomainMoney(req.MinAmount)
+
+		if err != nil {
+			violations = append(violations, &errdetails.BadRequest_FieldViolation{
+				Field:       "min_amount",
+				Description: "Invalid amount",
+			})
+		}
+
+		filter.MinAmount = &minAmount
+	}
+
+	if req.MaxAmount != nil {
+		maxAmount, err := toDomainMoney(req.MaxAmount)
+
+		if err != nil {
+			violations = append(violations, &errdetails.BadRequest_FieldViolation{
+				Field:       "max_amount",
+				Description: "Invalid amount",
+			})
+		}
+
+		filter.MaxAmount = &maxAmount
+	}
+
+	filter.NotesContains = req.NotesContains
+
+	return filter, violations
+}
+
+func toProtoTransactionRecord(trx domain.Transaction) *bank.TransactionRecord {
+	return &bank.TransactionRecord{
+		TransactionUuid: trx.TransactionUuid.String(),
+		AccountNumber:   trx.AccountNumber,
+		Type:            toProtoTransactionType(trx.TransactionType),
+		Amount:          toProtoMoney(trx.Amount),
+		Timestamp:       trx.Timestamp.Format(time.RFC3339),
+		Notes:           trx.Notes,
+	}
+}
+
+func toDomainTransactionType(trxType bank.TransactionType) string {
+	switch trxType {
+	case bank.TransactionType_TRANSACTION_TYPE_IN:
+		return domain.TransactionTypeIn
+	case bank.TransactionType_TRANSACTION_TYPE_OUT:
+		return domain.TransactionTypeOut
+	default:
+		return domain.TransactionTypeUnknown
+	}
+}
+
+func toProtoTransactionType(trxType string) bank.TransactionType {
+	switch trxType {
+	case domain.TransactionTypeIn:
+		return bank.TransactionType_TRANSACTION_TYPE_IN
+	case domain.TransactionTypeOut:
+		return bank.TransactionType_TRANSACTION_TYPE_OUT
+	default:
+		return bank.TransactionType_TRANSACTION_TYPE_UNSPECIFIED
+	}
+}
+
 func toTime(timestampStr string) (time.Time, error) {
 	layout := "02-01-2006 15:04:05"
 	return time.Parse(layout, timestampStr)
 }
+
+// amountFilterViolations flags the amount bounds of req, which must be in the account currency.
+func amountFilterViolations(req *bank.ListTransactionsRequest) []*errdetails.BadRequest_FieldViolation {
+	var violations []*errdetails.BadRequest_FieldViolation
+
+	if req.MinAmount != nil {
+		violations = append(violations, &errdetails.BadRequest_FieldViolation{
+			Field:       "min_amount.currency_code",
+			Description: "Must be the account currency",
+		})
+	}
+
+	if req.MaxAmount != nil {
+		violations = append(violations, &errdetails.BadRequest_FieldViolation{
+			Field:       "max_amount.currency_code",
+			Description: "Must be the account currency",
+		})
+	}
+
+	return violations
+}
+
+func toDomainMoney(m *bank.Money) (domain.Money, error) {
+	if m == nil || m.CurrencyCode == "" {
+		return domain.Money{}, fmt.Errorf("%w : amount and currency_code are required", domain.ErrInvalidAmount)
+	}
+
+	return domain.MoneyFromUnitsNanos(m.CurrencyCode, m.Units, m.Nanos)
+}
+
+func toProtoMoney(m domain.Money) *bank.Money {
+	units, nanos := m.UnitsNanos()
+
+	return &bank.Money{
+		CurrencyCode: m.Currency,
+		Units:        units,
+		Nanos:        nanos,
+	}
+}
@@ -0,0 +1,952 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"grpcbank/generated_proto/bank"
+	"grpcbank/src/adapter/memory"
+	"grpcbank/src/application"
+	"grpcbank/src/application/domain"
+	"grpcbank/src/port"
+	"io"
+	"log/slog"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+var discardLogger = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+// newTestBank opens a USD account holding 100.00, an empty IDR account and an empty USD account
+// in memory.
+func newTestBank(t *testing.T) (*application.BankService, *memory.MemoryAdapter) {
+	t.Helper()
+
+	db := memory.NewMemoryAdapter()
+	fixtures := []domain.AccountFixture{
+		{
+			AccountUuid:    uuid.New(),
+			AccountNumber:  "7835697001",
+			AccountName:    "Kate",
+			InitialDeposit: domain.NewMoney("USD", 10000),
+		},
+		{
+			AccountUuid:    uuid.New(),
+			AccountNumber:  "7835697002",
+			AccountName:    "Rupert",
+			InitialDeposit: domain.ZeroMoney("IDR"),
+		},
+		{
+			AccountUuid:    uuid.New(),
+			AccountNumber:  "7835697003",
+			AccountName:    "Alice",
+			InitialDeposit: domain.ZeroMoney("USD"),
+		},
+	}
+
+	if _, err := application.SeedAccounts(context.Background(), db, fixtures); err != nil {
+		t.Fatalf("SeedAccounts : %v", err)
+	}
+
+	service := application.NewBankService(db, application.NewBalanceBroker(discardLogger),
+		application.NewExchangeRateBroker(discardLogger), time.Hour, discardLogger)
+
+	return service, db
+}
+
+// testServer serves a bank service over an in-memory listener until the test ends.
+type testServer struct {
+	adapter  *GrpcAdapter
+	listener *bufconn.Listener
+}
+
+func newTestServer(t *testing.T, bankService port.BankServicePort) *testServer {
+	t.Helper()
+
+	server := &testServer{
+		adapter:  NewGrpcAdapter(bankService, ServerConfig{Logger: discardLogger}),
+		listener: bufconn.Listen(1 << 20),
+	}
+
+	go server.adapter.server.Serve(server.listener)
+	t.Cleanup(server.adapter.Stop)
+
+	return server
+}
+
+// dial opens a connection of its own to the server, closed when the test ends.
+func (s *testServer) dial(t *testing.T) (bank.BankServiceClient, *grpc.ClientConn) {
+	t.Helper()
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return s.listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+
+	if err != nil {
+		t.Fatalf("NewClient : %v", err)
+	}
+
+	t.Cleanup(func() { conn.Close() })
+
+	return bank.NewBankServiceClient(conn), conn
+}
+
+// newTestClient serves bankService and connects to it.
+func newTestClient(t *testing.T, bankService port.BankServicePort) (*GrpcAdapter, bank.BankServiceClient) {
+	t.Helper()
+
+	server := newTestServer(t, bankService)
+	client, _ := server.dial(t)
+
+	return server.adapter, client
+}
+
+func TestTransferMultipleReportsReasonsAndConversions(t *testing.T) {
+	service, db := newTestBank(t)
+	_, client := newTestClient(t, service)
+	ctx := context.Background()
+
+	stream, err := client.TransferMultiple(ctx)
+
+	if err != nil {
+		t.Fatalf("TransferMultiple : %v", err)
+	}
+
+	transfer := &bank.TransferRequest{
+		FromAccountNumber: "7835697001",
+		ToAccountNumber:   "7835697002",
+		Amount:            &bank.Money{CurrencyCode: "USD", Units: 10},
+	}
+
+	if err := stream.Send(transfer); err != nil {
+		t.Fatalf("Send : %v", err)
+	}
+
+	res, err := stream.Recv()
+
+	if err != nil {
+		t.Fatalf("Recv : %v", err)
+	}
+
+	if res.Status != bank.TransferStatus_TRANSFER_STATUS_FAILED || res.ErrorReason != "EXCHANGE_RATE_NOT_FOUND" ||
+		res.ErrorMessage == "" {
+		t.Errorf("transfer without a rate = %v %q %q, want FAILED EXCHANGE_RATE_NOT_FOUND with a message",
+			res.Status, res.ErrorReason, res.ErrorMessage)
+	}
+
+	_, err = db.CreateExchangeRate(ctx, domain.ExchangeRate{
+		FromCurrency:       "USD",
+		ToCurrency:         "IDR",
+		Rate:               domain.NewRate(1550025, 2),
+		ValidFromTimestamp: time.Now().Add(-time.Hour),
+		ValidToTimestamp:   time.Now().Add(time.Hour),
+	})
+
+	if err != nil {
+		t.Fatalf("CreateExchangeRate : %v", err)
+	}
+
+	if err := stream.Send(transfer); err != nil {
+		t.Fatalf("Send : %v", err)
+	}
+
+	res, err = stream.Recv()
+
+	if err != nil {
+		t.Fatalf("Recv : %v", err)
+	}
+
+	if res.Status != bank.TransferStatus_TRANSFER_STATUS_SUCCESS || res.ErrorReason != "" {
+		t.Fatalf("transfer with a rate = %v %q, want SUCCESS", res.Status, res.ErrorReason)
+	}
+
+	if res.SourceAmount.GetCurrencyCode() != "USD" || res.SourceAmount.GetUnits() != 10 ||
+		res.DestinationAmount.GetCurrencyCode() != "IDR" || res.DestinationAmount.GetUnits() != 155002 ||
+		res.DestinationAmount.GetNanos() != 500000000 || res.ExchangeRate != "15500.2500000000" {
+		t.Errorf("conversion = %v -> %v at %v, want 10 USD -> 155002.50 IDR at 15500.2500000000",
+			res.SourceAmount, res.DestinationAmount, res.ExchangeRate)
+	}
+
+	transfer.Amount = &bank.Money{CurrencyCode: "USD", Units: 1000}
+
+	if err := stream.Send(transfer); err != nil {
+		t.Fatalf("Send : %v", err)
+	}
+
+	res, err = stream.Recv()
+
+	if err != nil {
+		t.Fatalf("Recv : %v", err)
+	}
+
+	if res.Status != bank.TransferStatus_TRANSFER_STATUS_FAILED || res.ErrorReason != "INSUFFICIENT_BALANCE" ||
+		res.TransferUuid == "" || res.DestinationAmount.GetUnits() != 15500250 {
+		t.Errorf("overdrawing transfer = %v %q %q %v, want FAILED INSUFFICIENT_BALANCE with its conversion",
+			res.Status, res.ErrorReason, res.TransferUuid, res.DestinationAmount)
+	}
+
+	stream.CloseSend()
+
+	if _, err := stream.Recv(); err != io.EOF {
+		t.Errorf("Recv after CloseSend = %v, want EOF", err)
+	}
+}
+
+func TestListTransactionsRejectsAmountFiltersInAnotherCurrency(t *testing.T) {
+	service, _ := newTestBank(t)
+	_, client := newTestClient(t, service)
+
+	_, err := client.ListTransactions(context.Background(), &bank.ListTransactionsRequest{
+		AccountNumber: "7835697001",
+		MinAmount:     &bank.Money{CurrencyCode: "JPY", Units: 300},
+	})
+
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("ListTransactions with a JPY bound on a USD account = %v, want InvalidArgument", err)
+	}
+
+	if fields := fieldViolations(err); len(fields) != 1 || fields[0] != "min_amount.currency_code" {
+		t.Errorf("field violations = %v, want min_amount.currency_code", fields)
+	}
+}
+
+func TestSummaryRejectsAnotherCurrencyBeforePosting(t *testing.T) {
+	service, db := newTestBank(t)
+	_, client := newTestClient(t, service)
+	ctx := context.Background()
+
+	stream, err := client.SummarizeTransactions(ctx)
+
+	if err != nil {
+		t.Fatalf("SummarizeTransactions : %v", err)
+	}
+
+	if err := stream.Send(deposit("17-10-2026 10:00:00")); err != nil {
+		t.Fatalf("Send : %v", err)
+	}
+
+	if err := stream.Send(&bank.Transaction{
+		AccountNumber: "7835697002",
+		Type:          bank.TransactionType_TRANSACTION_TYPE_IN,
+		Amount:        &bank.Money{CurrencyCode: "IDR", Units: 1},
+		Timestamp:     "17-10-2026 10:01:00",
+	}); err != nil {
+		t.Fatalf("Send : %v", err)
+	}
+
+	_, err = stream.CloseAndRecv()
+
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("stream ended with %v, want InvalidArgument", err)
+	}
+
+	if fields := fieldViolations(err); len(fields) != 1 || fields[0] != "amount.currency_code" {
+		t.Errorf("field violations = %v, want amount.currency_code", fields)
+	}
+
+	rupiahs, err := db.GetBankAccountByAccountNumber(ctx, "7835697002")
+
+	if err != nil {
+		t.Fatalf("GetBankAccountByAccountNumber : %v", err)
+	}
+
+	if !rupiahs.Balance.IsZero() {
+		t.Errorf("balance of the IDR account = %v, want the rejected transaction not posted", rupiahs.Balance)
+	}
+
+	expectAccountBalance(t, db, "7835697001", 101)
+}
+
+// fakeStream hands its requests to a streaming handler then fails Recv with recvErr, and fails
+// every Send with sendErr when it is set.
+type fakeStream[Req any, Res any] struct {
+	grpc.ServerStream
+	requests []*Req
+	recvErr  error
+	sendErr  error
+}
+
+func (s *fakeStream[Req, Res]) Context() context.Context {
+	return context.Background()
+}
+
+func (s *fakeStream[Req, Res]) Recv() (*Req, error) {
+	if len(s.requests) == 0 {
+		return nil, s.recvErr
+	}
+
+	req := s.requests[0]
+	s.requests = s.requests[1:]
+
+	return req, nil
+}
+
+func (s *fakeStream[Req, Res]) Send(*Res) error {
+	return s.sendErr
+}
+
+func (s *fakeStream[Req, Res]) SendAndClose(*Res) error {
+	return s.sendErr
+}
+
+// failingBankService fails the transactions it is handed like a storage that went away.
+type failingBankService struct {
+	port.BankServicePort
+}
+
+func (failingBankService) CreateTransaction(context.Context, string, domain.Transaction) (uuid.UUID, error) {
+	return uuid.Nil, errors.New("connection refused")
+}
+
+func deposit(timestamp string) *bank.Transaction {
+	return &bank.Transaction{
+		AccountNumber: "7835697001",
+		Type:          bank.TransactionType_TRANSACTION_TYPE_IN,
+		Amount:        &bank.Money{CurrencyCode: "USD", Units: 1},
+		Timestamp:     timestamp,
+	}
+}
+
+func TestStreamsEndOnReceiveAndSendFailures(t *testing.T) {
+	service, _ := newTestBank(t)
+	adapter := NewGrpcAdapter(service, ServerConfig{Logger: discardLogger})
+	reset := errors.New("connection reset by peer")
+	transfer := &bank.TransferRequest{
+		FromAccountNumber: "7835697001",
+		ToAccountNumber:   "7835697001",
+		Amount:            &bank.Money{CurrencyCode: "USD", Units: 1},
+	}
+
+	type summaryStream = fakeStream[bank.Transaction, bank.TransactionSummary]
+	type processStream = fakeStream[bank.ProcessTransactionsRequest, bank.TransactionResult]
+	type transferStream = fakeStream[bank.TransferRequest, bank.TransferResponse]
+
+	tests := []struct {
+		name   string
+		handle func() error
+		code   codes.Code
+	}{
+		{"SummarizeTransactions receive", func() error {
+			return adapter.SummarizeTransactions(&summaryStream{recvErr: reset})
+		}, codes.Unavailable},
+		{"SummarizeTransactions cancelled", func() error {
+			return adapter.SummarizeTransactions(&summaryStream{recvErr: context.Canceled})
+		}, codes.Canceled},
+		{"SummarizeTransactions send", func() error {
+			return adapter.SummarizeTransactions(&summaryStream{recvErr: io.EOF, sendErr: reset})
+		}, codes.Unavailable},
+		{"ProcessTransactions receive", func() error {
+			return adapter.ProcessTransactions(&processStream{recvErr: reset})
+		}, codes.Unavailable},
+		{"ProcessTransactions send", func() error {
+			return adapter.ProcessTransactions(&processStream{
+				requests: []*bank.ProcessTransactionsRequest{{Transaction: deposit("17-10-2026 10:00:00")}},
+				recvErr:  io.EOF,
+				sendErr:  reset,
+			})
+		}, codes.Unavailable},
+		{"TransferMultiple receive", func() error {
+			return adapter.TransferMultiple(&transferStream{recvErr: reset})
+		}, codes.Unavailable},
+		{"TransferMultiple send", func() error {
+			return adapter.TransferMultiple(&transferStream{
+				requests: []*bank.TransferRequest{transfer},
+				recvErr:  io.EOF,
+				sendErr:  reset,
+			})
+		}, codes.Unavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.handle()
+
+			if _, ok := status.FromError(err); !ok || status.Code(err) != tt.code {
+				t.Errorf("handler returned %v, want a %v status", err, tt.code)
+			}
+		})
+	}
+}
+
+func TestMalformedTimestampEndsOnlyItsStream(t *testing.T) {
+	service, _ := newTestBank(t)
+	_, client := newTestClient(t, service)
+	ctx := context.Background()
+
+	healthy, err := client.SummarizeTransactions(ctx)
+
+	if err != nil {
+		t.Fatalf("SummarizeTransactions : %v", err)
+	}
+
+	malformed, err := client.SummarizeTransactions(ctx)
+
+	if err != nil {
+		t.Fatalf("SummarizeTransactions : %v", err)
+	}
+
+	if err := healthy.Send(deposit("17-10-2026 10:00:00")); err != nil {
+		t.Fatalf("Send : %v", err)
+	}
+
+	if err := malformed.Send(deposit("2026-10-17T10:00:00Z")); err != nil {
+		t.Fatalf("Send : %v", err)
+	}
+
+	_, err = malformed.CloseAndRecv()
+
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("malformed stream ended with %v, want InvalidArgument", err)
+	}
+
+	if fields := fieldViolations(err); len(fields) != 1 || fields[0] != "timestamp" {
+		t.Errorf("field violations = %v, want timestamp", fields)
+	}
+
+	if err := healthy.Send(deposit("17-10-2026 10:01:00")); err != nil {
+		t.Fatalf("Send after the malformed stream ended : %v", err)
+	}
+
+	summary, err := healthy.CloseAndRecv()
+
+	if err != nil {
+		t.Fatalf("healthy stream ended with %v", err)
+	}
+
+	if summary.SumAmountIn.GetUnits() != 2 {
+		t.Errorf("summary of the healthy stream = %v, want 2 USD in", summary.SumAmountIn)
+	}
+}
+
+func TestBrokenConnectionEndsOnlyItsStreams(t *testing.T) {
+	service, _ := newTestBank(t)
+	server := newTestServer(t, service)
+	client, _ := server.dial(t)
+	brokenClient, brokenConn := server.dial(t)
+	ctx := context.Background()
+
+	healthy, err := client.SummarizeTransactions(ctx)
+
+	if err != nil {
+		t.Fatalf("SummarizeTransactions : %v", err)
+	}
+
+	if err := healthy.Send(deposit("17-10-2026 10:00:00")); err != nil {
+		t.Fatalf("Send : %v", err)
+	}
+
+	broken, err := brokenClient.TransferMultiple(ctx)
+
+	if err != nil {
+		t.Fatalf("TransferMultiple : %v", err)
+	}
+
+	if err := broken.Send(&bank.TransferRequest{
+		FromAccountNumber: "7835697001",
+		ToAccountNumber:   "7835697001",
+		Amount:            &bank.Money{CurrencyCode: "USD", Units: 1},
+	}); err != nil {
+		t.Fatalf("Send : %v", err)
+	}
+
+	// the server is left waiting on a receive, or on a send, that can no longer succeed
+	brokenConn.Close()
+
+	if err := healthy.Send(deposit("17-10-2026 10:01:00")); err != nil {
+		t.Fatalf("Send after the other connection broke : %v", err)
+	}
+
+	summary, err := healthy.CloseAndRecv()
+
+	if err != nil || summary.SumAmountIn.GetUnits() != 2 {
+		t.Fatalf("healthy stream ended with %v, %v, want 2 USD in", summary, err)
+	}
+
+	if _, err := client.GetCurrentBalance(ctx, &bank.CurrentBalanceRequest{AccountNumber: "7835697001"}); err != nil {
+		t.Errorf("GetCurrentBalance after the other connection broke : %v", err)
+	}
+}
+
+func TestStorageFailureEndsTheStreamAsUnavailable(t *testing.T) {
+	service, _ := newTestBank(t)
+	_, client := newTestClient(t, failingBankService{BankServicePort: service})
+
+	stream, err := client.SummarizeTransactions(context.Background())
+
+	if err != nil {
+		t.Fatalf("SummarizeTransactions : %v", err)
+	}
+
+	if err := stream.Send(deposit("17-10-2026 10:00:00")); err != nil {
+		t.Fatalf("Send : %v", err)
+	}
+
+	_, err = stream.CloseAndRecv()
+
+	if status.Code(err) != codes.Unavailable {
+		t.Fatalf("stream ended with %v, want Unavailable", err)
+	}
+
+	var reasons []string
+
+	for _, detail := range status.Convert(err).Details() {
+		if info, ok := detail.(*errdetails.ErrorInfo); ok {
+			reasons = append(reasons, info.Domain+"/"+info.Reason)
+		}
+	}
+
+	if len(reasons) != 1 || reasons[0] != errorDomain+"/STORAGE_UNAVAILABLE" {
+		t.Errorf("error info = %v, want %v/STORAGE_UNAVAILABLE", reasons, errorDomain)
+	}
+
+	if strings.Contains(status.Convert(err).Message(), "connection refused") {
+		t.Errorf("status message %q leaks the storage error", status.Convert(err).Message())
+	}
+}
+
+// fieldViolations lists the fields of the BadRequest details of err.
+func fieldViolations(err error) []string {
+	var fields []string
+
+	for _, detail := range status.Convert(err).Details() {
+		if badRequest, ok := detail.(*errdetails.BadRequest); ok {
+			for _, violation := range badRequest.FieldViolations {
+				fields = append(fields, violation.Field)
+			}
+		}
+	}
+
+	return fields
+}
+
+// heldBankService holds every transfer until release is closed or the transfer is cancelled, like
+// a storage waiting on a lock. It reports their start to started and their outcome to done.
+type heldBankService struct {
+	port.BankServicePort
+	started chan struct{}
+	release chan struct{}
+	done    chan error
+}
+
+func newHeldBankService(next port.BankServicePort) *heldBankService {
+	return &heldBankService{
+		BankServicePort: next,
+		started:         make(chan struct{}, 1),
+		release:         make(chan struct{}),
+		done:            make(chan error, 1),
+	}
+}
+
+func (s *heldBankService) Transfer(ctx context.Context, transferTrx domain.TransferTransaction) (domain.Transfer,
+	error) {
+	s.started <- struct{}{}
+
+	select {
+	case <-s.release:
+	case <-ctx.Done():
+	}
+
+	transfer, err := s.BankServicePort.Transfer(ctx, transferTrx)
+	s.done <- err
+
+	return transfer, err
+}
+
+// startHeldTransfer sends a transfer of 10 USD to the server and waits for the service to hold it.
+func startHeldTransfer(t *testing.T, server *testServer,
+	held *heldBankService) bank.BankService_TransferMultipleClient {
+	t.Helper()
+
+	client, _ := server.dial(t)
+	stream, err := client.TransferMultiple(context.Background())
+
+	if err != nil {
+		t.Fatalf("TransferMultiple : %v", err)
+	}
+
+	if err := stream.Send(&bank.TransferRequest{
+		FromAccountNumber: "7835697001",
+		ToAccountNumber:   "7835697003",
+		Amount:            &bank.Money{CurrencyCode: "USD", Units: 10},
+	}); err != nil {
+		t.Fatalf("Send : %v", err)
+	}
+
+	<-held.started
+
+	return stream
+}
+
+func expectAccountBalance(t *testing.T, db port.BankDatabasePort, accountNumber string, units int64) {
+	t.Helper()
+
+	acct, err := db.GetBankAccountByAccountNumber(context.Background(), accountNumber)
+
+	if err != nil {
+		t.Fatalf("GetBankAccountByAccountNumber : %v", err)
+	}
+
+	if want := domain.NewMoney("USD", units*100); acct.Balance != want {
+		t.Errorf("balance of %v = %v, want %v", accountNumber, acct.Balance, want)
+	}
+}
+
+func TestShutdownCompletesTheTransferInProgress(t *testing.T) {
+	service, db := newTestBank(t)
+	held := newHeldBankService(service)
+	server := newTestServer(t, held)
+	stream := startHeldTransfer(t, server, held)
+
+	stopped := make(chan struct{})
+
+	go func() {
+		server.adapter.Shutdown(10 * time.Second)
+		close(stopped)
+	}()
+
+	<-server.adapter.draining
+
+	// sent after the drain started, it must not be started
+	if err := stream.Send(&bank.TransferRequest{
+		FromAccountNumber: "7835697001",
+		ToAccountNumber:   "7835697003",
+		Amount:            &bank.Money{CurrencyCode: "USD", Units: 20},
+	}); err != nil {
+		t.Fatalf("Send : %v", err)
+	}
+
+	close(held.release)
+
+	res, err := stream.Recv()
+
+	if err != nil || res.Status != bank.TransferStatus_TRANSFER_STATUS_SUCCESS {
+		t.Fatalf("transfer in progress = %v, %v, want SUCCESS", res, err)
+	}
+
+	if _, err := stream.Recv(); status.Code(err) != codes.Unavailable {
+		t.Errorf("stream ended with %v, want Unavailable", err)
+	}
+
+	<-stopped
+
+	select {
+	case <-held.started:
+		t.Errorf("a transfer was started after the server started draining")
+	default:
+	}
+
+	expectAccountBalance(t, db, "7835697001", 90)
+	expectAccountBalance(t, db, "7835697003", 10)
+}
+
+func TestShutdownTimeoutLeavesTheTransferInProgressUncommitted(t *testing.T) {
+	service, db := newTestBank(t)
+	held := newHeldBankService(service)
+	server := newTestServer(t, held)
+	startHeldTransfer(t, server, held)
+
+	// the transfer is still held when the timeout cancels it
+	server.adapter.Shutdown(50 * time.Millisecond)
+
+	if err := <-held.done; err == nil {
+		t.Errorf("transfer cancelled by the shutdown succeeded")
+	}
+
+	expectAccountBalance(t, db, "7835697001", 100)
+	expectAccountBalance(t, db, "7835697003", 0)
+}
+
+// recvBalance receives the next update of stream.
+func recvBalance(t *testing.T, stream bank.BankService_WatchBalanceClient) *bank.BalanceUpdate {
+	t.Helper()
+
+	update, err := stream.Recv()
+
+	if err != nil {
+		t.Fatalf("Recv : %v", err)
+	}
+
+	return update
+}
+
+func TestWatchBalanceSendsTheCurrentBalanceThenEveryChange(t *testing.T) {
+	service, db := newTestBank(t)
+	_, client := newTestClient(t, service)
+
+	acct, err := db.GetBankAccountByAccountNumber(context.Background(), "7835697001")
+
+	if err != nil {
+		t.Fatalf("GetBankAccountByAccountNumber : %v", err)
+	}
+
+	stream, err := client.WatchBalance(context.Background(), &bank.WatchBalanceRequest{AccountNumber: "7835697001"})
+
+	if err != nil {
+		t.Fatalf("WatchBalance : %v", err)
+	}
+
+	first := recvBalance(t, stream)
+
+	if first.Balance.Units != 100 || first.Transaction != nil || first.Version != acct.Version {
+		t.Fatalf("first update = %v, want 100 USD at version %d without a transaction", first, acct.Version)
+	}
+
+	tests := []struct {
+		withdrawn int64
+		balance   int64
+	}{
+		{25, 75},
+		{10, 65},
+	}
+
+	for i, tt := range tests {
+		_, err := service.CreateTransaction(context.Background(), "7835697001", domain.Transaction{
+			Amount:          domain.NewMoney("USD", tt.withdrawn*100),
+			TransactionType: domain.TransactionTypeOut,
+		})
+
+		if err != nil {
+			t.Fatalf("CreateTransaction : %v", err)
+		}
+
+		update := recvBalance(t, stream)
+		wantVersion := acct.Version + int64(i) + 1
+
+		if update.Balance.Units != tt.balance || update.Transaction.GetAmount().GetUnits() != tt.withdrawn ||
+			update.Version != wantVersion {
+			t.Errorf("update = %v, want %d USD after withdrawing %d at version %d", update, tt.balance,
+				tt.withdrawn, wantVersion)
+		}
+	}
+}
+
+// deniedBankService denies every watch like a policy refusing the caller.
+type deniedBankService struct {
+	port.BankServicePort
+}
+
+func (deniedBankService) WatchBalance(context.Context, string) (<-chan domain.BalanceUpdate, func(), error) {
+	return nil, nil, fmt.Errorf("%w : account is not owned by the caller", domain.ErrPermissionDenied)
+}
+
+func TestWatchBalanceRejects(t *testing.T) {
+	service, _ := newTestBank(t)
+
+	tests := []struct {
+		name          string
+		service       port.BankServicePort
+		accountNumber string
+		want          codes.Code
+	}{
+		{"unknown account", service, "7835697999", codes.FailedPrecondition},
+		{"denied caller", deniedBankService{BankServicePort: service}, "7835697001", codes.PermissionDenied},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, client := newTestClient(t, tt.service)
+
+			stream, err := client.WatchBalance(context.Background(),
+				&bank.WatchBalanceRequest{AccountNumber: tt.accountNumber})
+
+			if err != nil {
+				t.Fatalf("WatchBalance : %v", err)
+			}
+
+			if _, err := stream.Recv(); status.Code(err) != tt.want {
+				t.Errorf("stream ended with %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func trx(accountNumber string, trxType bank.TransactionType, units int64, idempotencyKey string) *bank.Transaction {
+	return &bank.Transaction{
+		AccountNumber:  accountNumber,
+		Type:           trxType,
+		Amount:         &bank.Money{CurrencyCode: "USD", Units: units},
+		Timestamp:      "17-10-2026 10:00:00",
+		IdempotencyKey: idempotencyKey,
+	}
+}
+
+// processTransactions sends trxs on one ProcessTransactions stream and collects the results.
+func processTransactions(t *testing.T, client bank.BankServiceClient, atomic bool,
+	trxs ...*bank.Transaction) []*bank.TransactionResult {
+	t.Helper()
+
+	stream, err := client.ProcessTransactions(context.Background())
+
+	if err != nil {
+		t.Fatalf("ProcessTransactions : %v", err)
+	}
+
+	for _, trx := range trxs {
+		if err := stream.Send(&bank.ProcessTransactionsRequest{Transaction: trx, Atomic: atomic}); err != nil {
+			t.Fatalf("Send : %v", err)
+		}
+	}
+
+	if err := stream.CloseSend(); err != nil {
+		t.Fatalf("CloseSend : %v", err)
+	}
+
+	var results []*bank.TransactionResult
+
+	for {
+		res, err := stream.Recv()
+
+		if err == io.EOF {
+			return results
+		}
+
+		if err != nil {
+			t.Fatalf("Recv : %v", err)
+		}
+
+		results = append(results, res)
+	}
+}
+
+type wantResult struct {
+	status bank.TransactionResultStatus
+	reason string
+}
+
+func expectResults(t *testing.T, got []*bank.TransactionResult, trxs []*bank.Transaction, want []wantResult) {
+	t.Helper()
+
+	if len(got) != len(want) {
+		t.Fatalf("got %d results, want %d : %v", len(got), len(want), got)
+	}
+
+	for i, res := range got {
+		if res.Index != int32(i) || res.Status != want[i].status || res.ErrorReason != want[i].reason ||
+			res.IdempotencyKey != trxs[i].IdempotencyKey {
+			t.Errorf("result %d = %v, want %v %v %q", i, res, want[i].status, want[i].reason, trxs[i].IdempotencyKey)
+		}
+
+		if applied := res.TransactionUuid != ""; applied != (want[i].status == succeeded) {
+			t.Errorf("result %d has transaction uuid %q, want one only on success", i, res.TransactionUuid)
+		}
+	}
+}
+
+const (
+	succeeded  = bank.TransactionResultStatus_TRANSACTION_RESULT_STATUS_SUCCESS
+	failed     = bank.TransactionResultStatus_TRANSACTION_RESULT_STATUS_FAILED
+	notApplied = bank.TransactionResultStatus_TRANSACTION_RESULT_STATUS_NOT_APPLIED
+	trxIn      = bank.TransactionType_TRANSACTION_TYPE_IN
+	trxOut     = bank.TransactionType_TRANSACTION_TYPE_OUT
+)
+
+func TestProcessTransactionsAtomically(t *testing.T) {
+	tests := []struct {
+		name     string
+		trxs     []*bank.Transaction
+		want     []wantResult
+		balances map[string]int64
+	}{
+		{
+			name: "every transaction applies",
+			trxs: []*bank.Transaction{
+				trx("7835697001", trxOut, 30, "a"),
+				trx("7835697003", trxIn, 30, "b"),
+				trx("7835697003", trxOut, 10, ""),
+			},
+			want:     []wantResult{{succeeded, ""}, {succeeded, ""}, {succeeded, ""}},
+			balances: map[string]int64{"7835697001": 70, "7835697003": 20},
+		},
+		{
+			name: "one failure rolls back every other transaction",
+			trxs: []*bank.Transaction{
+				trx("7835697001", trxOut, 30, "a"),
+				trx("7835697003", trxIn, 30, "b"),
+				trx("7835697003", trxOut, 50, "c"),
+			},
+			want:     []wantResult{{notApplied, ""}, {notApplied, ""}, {failed, "INSUFFICIENT_BALANCE"}},
+			balances: map[string]int64{"7835697001": 100, "7835697003": 0},
+		},
+		{
+			name: "unknown account",
+			trxs: []*bank.Transaction{
+				trx("7835697001", trxOut, 30, ""),
+				trx("7835697999", trxIn, 30, ""),
+			},
+			want:     []wantResult{{notApplied, ""}, {failed, "ACCOUNT_NOT_FOUND"}},
+			balances: map[string]int64{"7835697001": 100},
+		},
+		{
+			name: "unparseable transaction",
+			trxs: []*bank.Transaction{
+				trx("7835697001", trxOut, 30, ""),
+				{AccountNumber: "7835697003", Type: trxIn, Amount: &bank.Money{CurrencyCode: "USD", Units: 30},
+					Timestamp: "yesterday"},
+			},
+			want:     []wantResult{{notApplied, ""}, {failed, "INVALID_TIMESTAMP"}},
+			balances: map[string]int64{"7835697001": 100, "7835697003": 0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, db := newTestBank(t)
+			_, client := newTestClient(t, service)
+
+			results := processTransactions(t, client, true, tt.trxs...)
+
+			expectResults(t, results, tt.trxs, tt.want)
+
+			for accountNumber, units := range tt.balances {
+				expectAccountBalance(t, db, accountNumber, units)
+			}
+		})
+	}
+}
+
+func TestProcessTransactionsReportsEachTransaction(t *testing.T) {
+	service, db := newTestBank(t)
+	_, client := newTestClient(t, service)
+
+	trxs := []*bank.Transaction{
+		trx("7835697001", trxOut, 30, "a"),
+		trx("7835697003", trxOut, 50, "b"),
+		trx("7835697003", trxIn, 30, "c"),
+		trx("7835697999", trxIn, 30, ""),
+		{AccountNumber: "7835697003", Type: trxIn, Amount: &bank.Money{CurrencyCode: "USD", Units: 5},
+			Timestamp: "yesterday"},
+		// replays the first transaction
+		trx("7835697001", trxOut, 30, "a"),
+	}
+
+	results := processTransactions(t, client, false, trxs...)
+
+	expectResults(t, results, trxs, []wantResult{
+		{succeeded, ""},
+		{failed, "INSUFFICIENT_BALANCE"},
+		{succeeded, ""},
+		{failed, "ACCOUNT_NOT_FOUND"},
+		{failed, "INVALID_TIMESTAMP"},
+		{succeeded, ""},
+	})
+
+	if len(results) == 6 && results[5].TransactionUuid != results[0].TransactionUuid {
+		t.Errorf("replay uuid = %v, want %v", results[5].TransactionUuid, results[0].TransactionUuid)
+	}
+
+	expectAccountBalance(t, db, "7835697001", 70)
+	expectAccountBalance(t, db, "7835697003", 30)
+}
@@ -0,0 +1,135 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"grpcbank/src/application/domain"
+	"log/slog"
+)
+
+const errorDomain = "my-bank-website.com"
+
+// businessErrors are outcomes a client can act on, every other service error is treated as a
+// storage failure worth retrying.
+var businessErrors = []error{
+	domain.ErrAccountNotFound,
+	domain.ErrTransferSourceAccountNotFound,
+	domain.ErrTransferDestinationAccountNotFound,
+	domain.ErrInsufficientBalance,
+	domain.ErrInvalidAmount,
+	domain.ErrCurrencyMismatch,
+	domain.ErrExchangeRateNotFound,
+	domain.ErrIdempotencyKeyReused,
+	domain.ErrInvalidPageToken,
+	domain.ErrInvalidTransactionType,
+	domain.ErrDuplicateIdempotencyKey,
+	domain.ErrPermissionDenied,
+	errInvalidTimestamp,
+}
+
+var errInvalidTimestamp = errors.New("invalid timestamp")
+
+// errShuttingDown ends watch and transfer streams while the server drains, so clients reconnect
+// elsewhere.
+var errShuttingDown = status.Error(codes.Unavailable, "server is shutting down, please reconnect")
+
+// errorReasons names business errors in per-item results, in the same order as they are matched.
+var errorReasons = []struct {
+	err    error
+	reason string
+}{
+	{domain.ErrPermissionDenied, "PERMISSION_DENIED"},
+	{domain.ErrAccountNotFound, "ACCOUNT_NOT_FOUND"},
+	{domain.ErrTransferSourceAccountNotFound, "SOURCE_ACCOUNT_NOT_FOUND"},
+	{domain.ErrTransferDestinationAccountNotFound, "DESTINATION_ACCOUNT_NOT_FOUND"},
+	{domain.ErrExchangeRateNotFound, "EXCHANGE_RATE_NOT_FOUND"},
+	{domain.ErrInsufficientBalance, "INSUFFICIENT_BALANCE"},
+	{domain.ErrInvalidAmount, "INVALID_AMOUNT"},
+	{domain.ErrCurrencyMismatch, "CURRENCY_MISMATCH"},
+	{domain.ErrInvalidTransactionType, "INVALID_TRANSACTION_TYPE"},
+	{domain.ErrIdempotencyKeyReused, "IDEMPOTENCY_KEY_REUSED"},
+	{domain.ErrDuplicateIdempotencyKey, "IDEMPOTENCY_KEY_REUSED"},
+	{errInvalidTimestamp, "INVALID_TIMESTAMP"},
+}
+
+func errorReason(err error) string {
+	for _, errorReason := range errorReasons {
+		if errors.Is(err, errorReason.err) {
+			return errorReason.reason
+		}
+	}
+
+	return "STORAGE_UNAVAILABLE"
+}
+
+func isBusinessError(err error) bool {
+	for _, businessErr := range businessErrors {
+		if errors.Is(err, businessErr) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func badRequestError(code codes.Code, msg string, field string, description string) error {
+	s := status.New(code, msg)
+	s, _ = s.WithDetails(&errdetails.BadRequest{
+		FieldViolations: []*errdetails.BadRequest_FieldViolation{
+			{
+				Field:       field,
+				Description: description,
+			},
+		},
+	})
+
+	return s.Err()
+}
+
+func permissionDeniedError(err error) error {
+	s := status.New(codes.PermissionDenied, err.Error())
+	s, _ = s.WithDetails(&errdetails.ErrorInfo{
+		Domain: errorDomain,
+		Reason: "PERMISSION_DENIED",
+	})
+
+	return s.Err()
+}
+
+// unavailableError logs err, which is withheld from the caller. When the call itself was cancelled
+// or ran out of time, that is reported instead, since it is why the storage gave up.
+func (a *GrpcAdapter) unavailableError(ctx context.Context, err error) error {
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return status.FromContextError(ctxErr).Err()
+	}
+
+	a.logger.ErrorContext(ctx, "Storage failure", slog.Any("error", err))
+
+	s := status.New(codes.Unavailable, "Bank storage is temporarily unavailable, please retry")
+	s, _ = s.WithDetails(&errdetails.ErrorInfo{
+		Domain: errorDomain,
+		Reason: "STORAGE_UNAVAILABLE",
+	})
+
+	return s.Err()
+}
+
+// streamError converts an error from Recv or Send into the status ending the stream.
+func streamError(err error) error {
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	if errors.Is(err, context.Canceled) {
+		return status.Error(codes.Canceled, err.Error())
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	}
+
+	return status.Error(codes.Unavailable, err.Error())
+}
@@ -0,0 +1,59 @@
+package grpc
+
+import (
+	"context"
+	"grpcbank/generated_proto/bank"
+	"log/slog"
+	"time"
+
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// watchHealth pings the storage every healthCheckInterval and reports the bank service, and
+// the server as a whole, as SERVING only while the ping succeeds. It stops once the server drains.
+func (a *GrpcAdapter) watchHealth() {
+	ticker := time.NewTicker(a.healthCheckInterval)
+	defer ticker.Stop()
+
+	serving := false
+
+	for {
+		err := a.pingStorage()
+
+		if err != nil && serving {
+			a.logger.Error("Storage health check failed, reporting NOT_SERVING", slog.Any("error", err))
+		}
+
+		if err == nil && !serving {
+			a.logger.Info("Storage is reachable, reporting SERVING")
+		}
+
+		serving = err == nil
+		a.setServingStatus(serving)
+
+		select {
+		case <-a.draining:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (a *GrpcAdapter) pingStorage() error {
+	ctx, cancel := context.WithTimeout(context.Background(), a.healthCheckInterval)
+	defer cancel()
+
+	return a.bankService.Ping(ctx)
+}
+
+func (a *GrpcAdapter) setServingStatus(serving bool) {
+	status := healthpb.HealthCheckResponse_NOT_SERVING
+
+	if serving {
+		status = healthpb.HealthCheckResponse_SERVING
+	}
+
+	// updates made after the server started draining are ignored by the health server
+	a.health.SetServingStatus("", status)
+	a.health.SetServingStatus(bank.BankService_ServiceDesc.ServiceName, status)
+}
@@ -0,0 +1,120 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"grpcbank/generated_proto/bank"
+	"grpcbank/src/port"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+// pingedBankService answers pings with the storage error set last.
+type pingedBankService struct {
+	port.BankServicePort
+	mu  sync.Mutex
+	err error
+}
+
+func (s *pingedBankService) Ping(context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.err
+}
+
+func (s *pingedBankService) fail(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.err = err
+}
+
+// startHealthWatch checks the storage of a new adapter every few milliseconds, until it drains.
+func startHealthWatch(t *testing.T) (*GrpcAdapter, *pingedBankService, <-chan struct{}) {
+	t.Helper()
+
+	service := &pingedBankService{}
+	adapter := NewGrpcAdapter(service, ServerConfig{Logger: discardLogger, HealthCheckInterval: 5 * time.Millisecond})
+	stopped := make(chan struct{})
+
+	go func() {
+		adapter.watchHealth()
+		close(stopped)
+	}()
+
+	t.Cleanup(adapter.Stop)
+
+	return adapter, service, stopped
+}
+
+func servingStatus(t *testing.T, adapter *GrpcAdapter, service string) healthpb.HealthCheckResponse_ServingStatus {
+	t.Helper()
+
+	res, err := adapter.health.Check(context.Background(), &healthpb.HealthCheckRequest{Service: service})
+
+	// not reported before the first check
+	if status.Code(err) == codes.NotFound {
+		return healthpb.HealthCheckResponse_SERVICE_UNKNOWN
+	}
+
+	if err != nil {
+		t.Fatalf("Check(%q) : %v", service, err)
+	}
+
+	return res.Status
+}
+
+// expectServingStatus waits a few seconds for the server and the bank service to report want.
+func expectServingStatus(t *testing.T, adapter *GrpcAdapter, want healthpb.HealthCheckResponse_ServingStatus) {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+
+	for _, service := range []string{"", bank.BankService_ServiceDesc.ServiceName} {
+		for servingStatus(t, adapter, service) != want {
+			if time.Now().After(deadline) {
+				t.Fatalf("status of %q = %v, want %v", service, servingStatus(t, adapter, service), want)
+			}
+
+			time.Sleep(time.Millisecond)
+		}
+	}
+}
+
+func TestHealthFollowsStorage(t *testing.T) {
+	adapter, service, _ := startHealthWatch(t)
+
+	expectServingStatus(t, adapter, healthpb.HealthCheckResponse_SERVING)
+
+	service.fail(errors.New("connection refused"))
+	expectServingStatus(t, adapter, healthpb.HealthCheckResponse_NOT_SERVING)
+
+	service.fail(nil)
+	expectServingStatus(t, adapter, healthpb.HealthCheckResponse_SERVING)
+}
+
+func TestHealthReportsNotServingOnDrain(t *testing.T) {
+	adapter, _, stopped := startHealthWatch(t)
+
+	expectServingStatus(t, adapter, healthpb.HealthCheckResponse_SERVING)
+
+	adapter.Shutdown(time.Second)
+
+	expectServingStatus(t, adapter, healthpb.HealthCheckResponse_NOT_SERVING)
+
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("health checks still running after the drain")
+	}
+
+	// the storage is still reachable, yet the draining server must not report SERVING again
+	adapter.setServingStatus(true)
+	expectServingStatus(t, adapter, healthpb.HealthCheckResponse_NOT_SERVING)
+}
@@ -0,0 +1,97 @@
+package grpc
+
+import (
+	"context"
+	"grpcbank/src/logging"
+	"log/slog"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// requestIDHeader carries the request ID in both directions. Callers may set it to correlate their
+// own logs, otherwise the server assigns one.
+const requestIDHeader = "x-request-id"
+
+// validRequestID keeps caller supplied IDs short and free of characters that would garble the logs.
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+// requestLogger puts the request ID in the context of every RPC, returns it in the response
+// headers and logs the outcome of the RPC.
+type requestLogger struct {
+	logger *slog.Logger
+}
+
+// withRequestID puts the request ID sent by the caller, or a new one, in ctx.
+func withRequestID(ctx context.Context) (context.Context, string) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	id := uuid.NewString()
+
+	if ids := md.Get(requestIDHeader); len(ids) > 0 && validRequestID.MatchString(ids[0]) {
+		id = ids[0]
+	}
+
+	return logging.ContextWithRequestID(ctx, id), id
+}
+
+func (l requestLogger) finish(ctx context.Context, fullMethod string, started time.Time, err error) {
+	code := status.Code(err)
+	attrs := []slog.Attr{
+		slog.String("method", fullMethod),
+		slog.String("code", code.String()),
+		slog.Duration("duration", time.Since(started)),
+	}
+
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		attrs = append(attrs, slog.String("peer", p.Addr.String()))
+	}
+
+	level := slog.LevelInfo
+
+	switch code {
+	case codes.OK, codes.Canceled:
+		// health probes would drown the bank RPCs
+		if !strings.HasPrefix(fullMethod, authenticatedMethodPrefix) {
+			level = slog.LevelDebug
+		}
+	case codes.Internal, codes.Unknown, codes.Unavailable, codes.DataLoss:
+		level = slog.LevelError
+		attrs = append(attrs, slog.Any("error", err))
+	default:
+		level = slog.LevelWarn
+		attrs = append(attrs, slog.Any("error", err))
+	}
+
+	l.logger.LogAttrs(ctx, level, "RPC finished", attrs...)
+}
+
+func (l requestLogger) UnaryServerInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler) (any, error) {
+	started := time.Now()
+	ctx, id := withRequestID(ctx)
+	grpc.SetHeader(ctx, metadata.Pairs(requestIDHeader, id))
+
+	res, err := handler(ctx, req)
+	l.finish(ctx, info.FullMethod, started, err)
+
+	return res, err
+}
+
+func (l requestLogger) StreamServerInterceptor(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo,
+	handler grpc.StreamHandler) error {
+	started := time.Now()
+	ctx, id := withRequestID(stream.Context())
+	stream.SetHeader(metadata.Pairs(requestIDHeader, id))
+
+	err := handler(srv, &contextServerStream{ServerStream: stream, ctx: ctx})
+	l.finish(ctx, info.FullMethod, started, err)
+
+	return err
+}
@@ -0,0 +1,134 @@
+package grpc
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// ServerMetrics counts RPCs and their messages per method, with the metric names commonly used for
+// gRPC servers so that existing dashboards apply.
+type ServerMetrics struct {
+	started  *prometheus.CounterVec
+	handled  *prometheus.CounterVec
+	handling *prometheus.HistogramVec
+	received *prometheus.CounterVec
+	sent     *prometheus.CounterVec
+}
+
+func NewServerMetrics(registerer prometheus.Registerer) (*ServerMetrics, error) {
+	labels := []string{"grpc_type", "grpc_service", "grpc_method"}
+
+	m := &ServerMetrics{
+		started: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "grpc_server_started_total",
+			Help: "RPCs started on the server.",
+		}, labels),
+		handled: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "grpc_server_handled_total",
+			Help: "RPCs completed on the server, by status code.",
+		}, append(labels, "grpc_code")),
+		handling: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "grpc_server_handling_seconds",
+			Help:    "Time taken by the server to complete RPCs.",
+			Buckets: prometheus.DefBuckets,
+		}, labels),
+		received: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "grpc_server_msg_received_total",
+			Help: "Stream messages received from clients.",
+		}, labels),
+		sent: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "grpc_server_msg_sent_total",
+			Help: "Stream messages sent to clients.",
+		}, labels),
+	}
+
+	for _, collector := range []prometheus.Collector{m.started, m.handled, m.handling, m.received, m.sent} {
+		if err := registerer.Register(collector); err != nil {
+			return nil, err
+		}
+	}
+
+	return m, nil
+}
+
+// methodLabels splits "/package.Service/Method" into the label values of an RPC.
+func methodLabels(rpcType string, fullMethod string) []string {
+	service, method, _ := strings.Cut(strings.TrimPrefix(fullMethod, "/"), "/")
+	return []string{rpcType, service, method}
+}
+
+func streamType(info *grpc.StreamServerInfo) string {
+	switch {
+	case info.IsClientStream && info.IsServerStream:
+		return "bidi_stream"
+	case info.IsClientStream:
+		return "client_stream"
+	default:
+		return "server_stream"
+	}
+}
+
+func (m *ServerMetrics) finish(labels []string, started time.Time, err error) {
+	m.handled.WithLabelValues(append(labels, status.Code(err).String())...).Inc()
+	m.handling.WithLabelValues(labels...).Observe(time.Since(started).Seconds())
+}
+
+func (m *ServerMetrics) UnaryServerInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler) (any, error) {
+	labels := methodLabels("unary", info.FullMethod)
+	started := time.Now()
+	m.started.WithLabelValues(labels...).Inc()
+
+	res, err := handler(ctx, req)
+	m.finish(labels, started, err)
+
+	return res, err
+}
+
+func (m *ServerMetrics) StreamServerInterceptor(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo,
+	handler grpc.StreamHandler) error {
+	labels := methodLabels(streamType(info), info.FullMethod)
+	started := time.Now()
+	m.started.WithLabelValues(labels...).Inc()
+
+	err := handler(srv, &countingServerStream{
+		ServerStream: stream,
+		received:     m.received.WithLabelValues(labels...),
+		sent:         m.sent.WithLabelValues(labels...),
+	})
+	m.finish(labels, started, err)
+
+	return err
+}
+
+// countingServerStream counts the messages a stream receives and sends.
+type countingServerStream struct {
+	grpc.ServerStream
+	received prometheus.Counter
+	sent     prometheus.Counter
+}
+
+func (s *countingServerStream) RecvMsg(m any) error {
+	err := s.ServerStream.RecvMsg(m)
+
+	if err == nil {
+		s.received.Inc()
+	}
+
+	return err
+}
+
+func (s *countingServerStream) SendMsg(m any) error {
+	err := s.ServerStream.SendMsg(m)
+
+	if err == nil {
+		s.sent.Inc()
+	}
+
+	return err
+}
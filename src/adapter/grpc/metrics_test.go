@@ -0,0 +1,157 @@
+package grpc
+
+import (
+	"context"
+	"grpcbank/generated_proto/bank"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// newMeasuredClient serves the test bank with metrics recorded in a registry of its own.
+func newMeasuredClient(t *testing.T) (bank.BankServiceClient, *prometheus.Registry) {
+	t.Helper()
+
+	registry := prometheus.NewRegistry()
+	metrics, err := NewServerMetrics(registry)
+
+	if err != nil {
+		t.Fatalf("NewServerMetrics : %v", err)
+	}
+
+	service, _ := newTestBank(t)
+	server := &testServer{
+		adapter:  NewGrpcAdapter(service, ServerConfig{Logger: discardLogger, Metrics: metrics}),
+		listener: bufconn.Listen(1 << 20),
+	}
+
+	go server.adapter.server.Serve(server.listener)
+	t.Cleanup(server.adapter.Stop)
+
+	client, _ := server.dial(t)
+
+	return client, registry
+}
+
+// metricValue returns the value of the counter, or the sample count of the histogram, name labelled
+// with labels, or 0 when there is no such series.
+func metricValue(t *testing.T, registry *prometheus.Registry, name string, labels ...string) float64 {
+	t.Helper()
+
+	families, err := registry.Gather()
+
+	if err != nil {
+		t.Fatalf("Gather : %v", err)
+	}
+
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+
+		for _, metric := range family.GetMetric() {
+			got := make([]string, 0, 2*len(metric.GetLabel()))
+
+			for _, label := range metric.GetLabel() {
+				got = append(got, label.GetName(), label.GetValue())
+			}
+
+			if !sameLabels(got, labels) {
+				continue
+			}
+
+			if metric.GetHistogram() != nil {
+				return float64(metric.GetHistogram().GetSampleCount())
+			}
+
+			return metric.GetCounter().GetValue()
+		}
+	}
+
+	return 0
+}
+
+// sameLabels reports whether the name value pairs of got and want are the same, in any order.
+func sameLabels(got []string, want []string) bool {
+	if len(got) != len(want) {
+		return false
+	}
+
+	for i := 0; i < len(want); i += 2 {
+		found := false
+
+		for j := 0; j < len(got); j += 2 {
+			if got[j] == want[i] && got[j+1] == want[i+1] {
+				found = true
+			}
+		}
+
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
+func expectMetric(t *testing.T, registry *prometheus.Registry, name string, want float64, labels ...string) {
+	t.Helper()
+
+	if got := metricValue(t, registry, name, labels...); got != want {
+		t.Errorf("%v%v = %v, want %v", name, labels, got, want)
+	}
+}
+
+func TestServerMetricsRecordUnaryRPCs(t *testing.T) {
+	client, registry := newMeasuredClient(t)
+
+	for _, accountNumber := range []string{"7835697001", "7835697003", "7835697999"} {
+		// the unknown account fails, which is counted all the same
+		_, _ = client.GetCurrentBalance(context.Background(), &bank.CurrentBalanceRequest{AccountNumber: accountNumber})
+	}
+
+	rpc := []string{"grpc_type", "unary", "grpc_service", "bank.BankService", "grpc_method", "GetCurrentBalance"}
+
+	expectMetric(t, registry, "grpc_server_started_total", 3, rpc...)
+	expectMetric(t, registry, "grpc_server_handled_total", 2, append(rpc, "grpc_code", "OK")...)
+	expectMetric(t, registry, "grpc_server_handled_total", 1, append(rpc, "grpc_code", "FailedPrecondition")...)
+	expectMetric(t, registry, "grpc_server_handling_seconds", 3, rpc...)
+}
+
+func TestServerMetricsRecordStreamMessages(t *testing.T) {
+	client, registry := newMeasuredClient(t)
+
+	stream, err := client.ProcessTransactions(context.Background())
+
+	if err != nil {
+		t.Fatalf("ProcessTransactions : %v", err)
+	}
+
+	for _, timestamp := range []string{"17-10-2026 10:00:00", "17-10-2026 10:01:00", "17-10-2026 10:02:00"} {
+		if err := stream.Send(&bank.ProcessTransactionsRequest{Transaction: deposit(timestamp)}); err != nil {
+			t.Fatalf("Send : %v", err)
+		}
+
+		if _, err := stream.Recv(); err != nil {
+			t.Fatalf("Recv : %v", err)
+		}
+	}
+
+	if err := stream.CloseSend(); err != nil {
+		t.Fatalf("CloseSend : %v", err)
+	}
+
+	if _, err := stream.Recv(); err == nil {
+		t.Fatalf("Recv after the last result succeeded, want the end of the stream")
+	}
+
+	rpc := []string{"grpc_type", "bidi_stream", "grpc_service", "bank.BankService", "grpc_method",
+		"ProcessTransactions"}
+
+	expectMetric(t, registry, "grpc_server_started_total", 1, rpc...)
+	expectMetric(t, registry, "grpc_server_handled_total", 1, append(rpc, "grpc_code", "OK")...)
+	expectMetric(t, registry, "grpc_server_handling_seconds", 1, rpc...)
+	expectMetric(t, registry, "grpc_server_msg_received_total", 3, rpc...)
+	expectMetric(t, registry, "grpc_server_msg_sent_total", 3, rpc...)
+}
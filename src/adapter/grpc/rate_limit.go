@@ -0,0 +1,282 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"grpcbank/generated_proto/bank"
+	"grpcbank/src/application/domain"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// RateLimit is a token bucket refilled with PerSecond tokens up to Burst. A zero PerSecond
+// disables the limit.
+type RateLimit struct {
+	PerSecond float64
+	Burst     int
+}
+
+// MethodRateLimits limits how often an RPC is called and, for streams, how many messages are
+// received, separately for each principal, peer address and source account.
+type MethodRateLimits struct {
+	Calls    RateLimit
+	Messages RateLimit
+}
+
+const (
+	// idle buckets are full again long before this, so forgetting them loses nothing
+	rateLimitIdleTimeout  = 10 * time.Minute
+	rateLimitSweepPeriod  = time.Minute
+	rateLimitKindCalls    = "calls"
+	rateLimitKindMessages = "messages"
+)
+
+// RateLimiter keeps one token bucket per method, kind of limit and caller key.
+type RateLimiter struct {
+	defaults MethodRateLimits
+	methods  map[string]MethodRateLimits
+
+	mu        sync.Mutex
+	buckets   map[string]*rateBucket
+	lastSweep time.Time
+}
+
+type rateBucket struct {
+	limiter  *rate.Limiter
+	lastUsed time.Time
+}
+
+// NewRateLimiter applies defaults to every bank RPC, except the ones listed in methods by their
+// name, e.g. TransferMultiple.
+func NewRateLimiter(defaults MethodRateLimits, methods map[string]MethodRateLimits) (*RateLimiter, error) {
+	known := make(map[string]bool)
+
+	for _, method := range bank.BankService_ServiceDesc.Methods {
+		known[method.MethodName] = true
+	}
+
+	for _, stream := range bank.BankService_ServiceDesc.Streams {
+		known[stream.StreamName] = true
+	}
+
+	for method := range methods {
+		if !known[method] {
+			return nil, fmt.Errorf("rate limit set for unknown method %q", method)
+		}
+	}
+
+	return &RateLimiter{
+		defaults: defaults,
+		methods:  methods,
+		buckets:  make(map[string]*rateBucket),
+	}, nil
+}
+
+func (l *RateLimiter) limitsFor(fullMethod string) MethodRateLimits {
+	if limits, ok := l.methods[strings.TrimPrefix(fullMethod, authenticatedMethodPrefix)]; ok {
+		return limits
+	}
+
+	return l.defaults
+}
+
+// allow takes a token from the bucket of every key, or none of them when one is exhausted. It then
+// returns the ResourceExhausted status telling the caller how long to wait.
+func (l *RateLimiter) allow(fullMethod string, kind string, limit RateLimit, keys []string) error {
+	if limit.PerSecond <= 0 {
+		return nil
+	}
+
+	now := time.Now()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.sweep(now)
+
+	reservations := make([]*rate.Reservation, 0, len(keys))
+
+	for _, key := range keys {
+		reservation := l.bucket(fullMethod+"|"+kind+"|"+key, limit, now).ReserveN(now, 1)
+
+		if !reservation.OK() || reservation.DelayFrom(now) > 0 {
+			delay := reservation.DelayFrom(now)
+			reservation.CancelAt(now)
+
+			for _, taken := range reservations {
+				taken.CancelAt(now)
+			}
+
+			return rateLimitedError(fullMethod, kind, key, delay)
+		}
+
+		reservations = append(reservations, reservation)
+	}
+
+	return nil
+}
+
+func (l *RateLimiter) bucket(key string, limit RateLimit, now time.Time) *rate.Limiter {
+	bucket, ok := l.buckets[key]
+
+	if !ok {
+		bucket = &rateBucket{
+			limiter: rate.NewLimiter(rate.Limit(limit.PerSecond), max(limit.Burst, 1)),
+		}
+		l.buckets[key] = bucket
+	}
+
+	bucket.lastUsed = now
+
+	return bucket.limiter
+}
+
+func (l *RateLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < rateLimitSweepPeriod {
+		return
+	}
+
+	l.lastSweep = now
+
+	for key, bucket := range l.buckets {
+		if now.Sub(bucket.lastUsed) > rateLimitIdleTimeout {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+func rateLimitedError(fullMethod string, kind string, key string, delay time.Duration) error {
+	dimension, _, _ := strings.Cut(key, ":")
+
+	s := status.New(codes.ResourceExhausted,
+		fmt.Sprintf("too many %v, retry in %v", kind, delay.Round(time.Millisecond)))
+	s, _ = s.WithDetails(
+		&errdetails.RetryInfo{
+			RetryDelay: durationpb.New(delay),
+		},
+		&errdetails.ErrorInfo{
+			Domain: errorDomain,
+			Reason: "RATE_LIMITED",
+			Metadata: map[string]string{
+				"method": fullMethod,
+				"limit":  kind,
+				"key":    dimension,
+			},
+		},
+	)
+
+	return s.Err()
+}
+
+// callerKeys identifies the caller by principal, when authenticated, and by peer address.
+func callerKeys(ctx context.Context) []string {
+	var keys []string
+
+	if principal, ok := domain.PrincipalFromContext(ctx); ok {
+		keys = append(keys, "principal:"+principal.Subject)
+	}
+
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		host, _, err := net.SplitHostPort(p.Addr.String())
+
+		if err != nil {
+			host = p.Addr.String()
+		}
+
+		keys = append(keys, "peer:"+host)
+	}
+
+	return keys
+}
+
+// withSourceAccount adds the account a request draws on to keys. The account is named by the
+// client before the service checks it may use it, so its bucket belongs to the caller as well:
+// naming the account of someone else only spends tokens of the caller.
+func withSourceAccount(keys []string, req any) []string {
+	account := ""
+
+	switch m := req.(type) {
+	case interface{ GetFromAccountNumber() string }:
+		account = m.GetFromAccountNumber()
+	case interface{ GetAccountNumber() string }:
+		account = m.GetAccountNumber()
+	case interface{ GetTransaction() *bank.Transaction }:
+		account = m.GetTransaction().GetAccountNumber()
+	}
+
+	if account == "" || len(keys) == 0 {
+		return keys
+	}
+
+	// the principal when authenticated, the peer address otherwise
+	caller := keys[0]
+
+	return append(keys[:len(keys):len(keys)], "account:"+account+"|"+caller)
+}
+
+func (l *RateLimiter) UnaryServerInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler) (any, error) {
+	if !strings.HasPrefix(info.FullMethod, authenticatedMethodPrefix) {
+		return handler(ctx, req)
+	}
+
+	limits := l.limitsFor(info.FullMethod)
+
+	err := l.allow(info.FullMethod, rateLimitKindCalls, limits.Calls, withSourceAccount(callerKeys(ctx), req))
+
+	if err != nil {
+		return nil, err
+	}
+
+	return handler(ctx, req)
+}
+
+func (l *RateLimiter) StreamServerInterceptor(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo,
+	handler grpc.StreamHandler) error {
+	if !strings.HasPrefix(info.FullMethod, authenticatedMethodPrefix) {
+		return handler(srv, stream)
+	}
+
+	limits := l.limitsFor(info.FullMethod)
+	keys := callerKeys(stream.Context())
+
+	if err := l.allow(info.FullMethod, rateLimitKindCalls, limits.Calls, keys); err != nil {
+		return err
+	}
+
+	return handler(srv, &rateLimitedServerStream{
+		ServerStream: stream,
+		limiter:      l,
+		fullMethod:   info.FullMethod,
+		limit:        limits.Messages,
+		keys:         keys,
+	})
+}
+
+// rateLimitedServerStream takes a message token for every message received from the client. A
+// message over the limit ends the stream with ResourceExhausted.
+type rateLimitedServerStream struct {
+	grpc.ServerStream
+	limiter    *RateLimiter
+	fullMethod string
+	limit      RateLimit
+	keys       []string
+}
+
+func (s *rateLimitedServerStream) RecvMsg(m any) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+
+	return s.limiter.allow(s.fullMethod, rateLimitKindMessages, s.limit, withSourceAccount(s.keys, m))
+}
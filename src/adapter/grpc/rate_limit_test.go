@@ -0,0 +1,43 @@
+package grpc
+
+import (
+	"context"
+	"grpcbank/generated_proto/bank"
+	"grpcbank/src/application/domain"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRateLimiterAccountBucketsBelongToTheCaller(t *testing.T) {
+	limiter, err := NewRateLimiter(MethodRateLimits{Calls: RateLimit{PerSecond: 0.001, Burst: 1}}, nil)
+
+	if err != nil {
+		t.Fatalf("NewRateLimiter : %v", err)
+	}
+
+	info := &grpc.UnaryServerInfo{FullMethod: authenticatedMethodPrefix + "GetCurrentBalance"}
+	handler := func(ctx context.Context, req any) (any, error) { return nil, nil }
+	req := &bank.CurrentBalanceRequest{AccountNumber: "7835697001"}
+
+	call := func(subject string) error {
+		ctx := domain.ContextWithPrincipal(context.Background(), domain.Principal{Subject: subject})
+		_, err := limiter.UnaryServerInterceptor(ctx, req, info, handler)
+
+		return err
+	}
+
+	if err := call("mallory"); err != nil {
+		t.Fatalf("first call of mallory : %v", err)
+	}
+
+	if err := call("mallory"); status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("second call of mallory : got %v, want ResourceExhausted", err)
+	}
+
+	if err := call("kate"); err != nil {
+		t.Fatalf("the owner is limited by calls of mallory naming the account : %v", err)
+	}
+}
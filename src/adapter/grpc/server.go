@@ -1,51 +1,175 @@
 package grpc
 
 import (
-	"fmt"
+	"crypto/tls"
 	"grpcbank/generated_proto/bank"
 	"grpcbank/src/port"
-	"log"
+	"log/slog"
 	"net"
+	"os"
+	"sync"
+	"time"
 
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc/filters"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
 )
 
 type GrpcAdapter struct {
-	bankService port.BankServicePort
-	grpcPort    int
-	server      *grpc.Server
+	bankService         port.BankServicePort
+	listenAddress       string
+	reflection          bool
+	healthCheckInterval time.Duration
+	server              *grpc.Server
+	health              *health.Server
+	logger              *slog.Logger
+	draining            chan struct{}
+	drainOnce           sync.Once
 	bank.BankServiceServer
 }
 
-func NewGrpcAdapter(bankService port.BankServicePort, grpcPort int) *GrpcAdapter {
-	return &GrpcAdapter{
-		bankService: bankService,
-		grpcPort:    grpcPort,
+// ServerConfig holds the settings of the gRPC server.
+type ServerConfig struct {
+	ListenAddress string
+	// TLSConfig enables TLS when set.
+	TLSConfig *tls.Config
+	// Reflection registers the server reflection service, for tools such as grpcurl.
+	Reflection bool
+	// HealthCheckInterval is how often the storage is pinged to update the health status.
+	HealthCheckInterval time.Duration
+	// Authenticator requires a bearer token or a client certificate on every bank RPC when set.
+	Authenticator *Authenticator
+	// RateLimiter limits bank RPCs and streamed messages when set.
+	RateLimiter *RateLimiter
+	// Logger receives a record for every RPC, tagged with its request ID.
+	Logger *slog.Logger
+	// Metrics counts RPCs, their latency and their stream messages when set.
+	Metrics *ServerMetrics
+	// Tracing starts a span for every RPC but health checks, continuing the trace of the caller.
+	// The spans go to the global tracer provider.
+	Tracing bool
+}
+
+func NewGrpcAdapter(bankService port.BankServicePort, cfg ServerConfig) *GrpcAdapter {
+	var opts []grpc.ServerOption
+
+	if cfg.TLSConfig != nil {
+		opts = append(opts, grpc.Creds(credentials.NewTLS(cfg.TLSConfig)))
+	}
+
+	// stats handlers run before any interceptor, so the logs of a call carry its trace
+	if cfg.Tracing {
+		opts = append(opts, grpc.StatsHandler(otelgrpc.NewServerHandler(
+			otelgrpc.WithFilter(filters.Not(filters.HealthCheck())),
+		)))
+	}
+
+	// the request ID is assigned first, so that rejected calls are logged with it too
+	requests := requestLogger{logger: cfg.Logger}
+	opts = append(opts,
+		grpc.ChainUnaryInterceptor(requests.UnaryServerInterceptor),
+		grpc.ChainStreamInterceptor(requests.StreamServerInterceptor),
+	)
+
+	// measured before authentication and rate limiting, so that rejected calls are counted
+	if cfg.Metrics != nil {
+		opts = append(opts,
+			grpc.ChainUnaryInterceptor(cfg.Metrics.UnaryServerInterceptor),
+			grpc.ChainStreamInterceptor(cfg.Metrics.StreamServerInterceptor),
+		)
+	}
+
+	// authentication runs before rate limiting, so that rate limits can be keyed by principal
+	if cfg.Authenticator != nil {
+		opts = append(opts,
+			grpc.ChainUnaryInterceptor(cfg.Authenticator.UnaryServerInterceptor),
+			grpc.ChainStreamInterceptor(cfg.Authenticator.StreamServerInterceptor),
+		)
+	}
+
+	if cfg.RateLimiter != nil {
+		opts = append(opts,
+			grpc.ChainUnaryInterceptor(cfg.RateLimiter.UnaryServerInterceptor),
+			grpc.ChainStreamInterceptor(cfg.RateLimiter.StreamServerInterceptor),
+		)
+	}
+
+	a := &GrpcAdapter{
+		bankService:         bankService,
+		listenAddress:       cfg.ListenAddress,
+		reflection:          cfg.Reflection,
+		healthCheckInterval: cfg.HealthCheckInterval,
+		logger:              cfg.Logger,
+		server:              grpc.NewServer(opts...),
+		health:              health.NewServer(),
+		draining:            make(chan struct{}),
 	}
+
+	bank.RegisterBankServiceServer(a.server, a)
+	healthpb.RegisterHealthServer(a.server, a.health)
+
+	return a
 }
 
+// Run serves until Stop or Shutdown is called.
 func (a *GrpcAdapter) Run() {
 	var err error
 
-	listen, err := net.Listen("tcp", fmt.Sprintf(":%d", a.grpcPort))
+	listen, err := net.Listen("tcp", a.listenAddress)
 
 	if err != nil {
-		log.Fatalf("Failed to listen on port %d : %v\n", a.grpcPort, err)
+		a.logger.Error("Failed to listen", slog.String("address", a.listenAddress), slog.Any("error", err))
+		os.Exit(1)
 	}
 
-	log.Printf("Server listening on port %d\n", a.grpcPort)
-
-	grpcServer := grpc.NewServer()
+	a.logger.Info("Server listening", slog.String("address", a.listenAddress))
 
-	a.server = grpcServer
+	if a.reflection {
+		reflection.Register(a.server)
+	}
 
-	bank.RegisterBankServiceServer(grpcServer, a)
+	go a.watchHealth()
 
-	if err = grpcServer.Serve(listen); err != nil {
-		log.Fatalf("Failed to serve gRPC on port %d : %v\n", a.grpcPort, err)
+	if err = a.server.Serve(listen); err != nil && err != grpc.ErrServerStopped {
+		a.logger.Error("Failed to serve gRPC", slog.String("address", a.listenAddress), slog.Any("error", err))
+		os.Exit(1)
 	}
 }
 
 func (a *GrpcAdapter) Stop() {
+	a.drain()
 	a.server.Stop()
 }
+
+// Shutdown reports NOT_SERVING to health checks, ends the watch streams and lets the other
+// in-flight RPCs finish. RPCs still running after timeout are cancelled.
+func (a *GrpcAdapter) Shutdown(timeout time.Duration) {
+	a.drain()
+
+	stopped := make(chan struct{})
+
+	go func() {
+		a.server.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+		a.logger.Info("Server stopped gracefully")
+	case <-time.After(timeout):
+		a.logger.Warn("Server didn't stop in time, cancelling remaining RPCs", slog.Duration("timeout", timeout))
+		a.server.Stop()
+		<-stopped
+	}
+}
+
+func (a *GrpcAdapter) drain() {
+	a.drainOnce.Do(func() {
+		a.health.Shutdown()
+		close(a.draining)
+	})
+}
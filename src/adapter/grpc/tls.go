@@ -0,0 +1,164 @@
+package grpc
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+)
+
+// CertificateReloader serves the server key pair and client CA bundle from files, reloading them
+// when they change on disk so certificates can be rotated without a restart.
+type CertificateReloader struct {
+	certFile          string
+	keyFile           string
+	clientCAFile      string
+	requireClientCert bool
+	logger            *slog.Logger
+
+	mu       sync.RWMutex
+	config   *tls.Config
+	contents [][]byte
+}
+
+// NewCertificateReloader loads the key pair. When clientCAFile is set, client certificates are
+// verified against its CAs, and must be presented when requireClientCert is true.
+func NewCertificateReloader(certFile, keyFile, clientCAFile string, requireClientCert bool,
+	logger *slog.Logger) (*CertificateReloader, error) {
+	r := &CertificateReloader{
+		certFile:          certFile,
+		keyFile:           keyFile,
+		clientCAFile:      clientCAFile,
+		requireClientCert: requireClientCert,
+		logger:            logger,
+	}
+
+	if _, err := r.reload(); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// TLSConfig returns a configuration always serving the latest loaded files.
+func (r *CertificateReloader) TLSConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			r.mu.RLock()
+			defer r.mu.RUnlock()
+
+			return r.config, nil
+		},
+	}
+}
+
+// Watch checks the files every interval until ctx is done. A file that can't be loaded is
+// reported and the previous certificates stay in use.
+func (r *CertificateReloader) Watch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		reloaded, err := r.reload()
+
+		if err != nil {
+			r.logger.ErrorContext(ctx, "Can't reload TLS certificates, keeping the previous ones",
+				slog.Any("error", err))
+			continue
+		}
+
+		if reloaded {
+			r.logger.InfoContext(ctx, "TLS certificates reloaded")
+		}
+	}
+}
+
+// reload loads the files when their content differs from the loaded one.
+func (r *CertificateReloader) reload() (bool, error) {
+	files := []string{r.certFile, r.keyFile}
+
+	if r.clientCAFile != "" {
+		files = append(files, r.clientCAFile)
+	}
+
+	contents := make([][]byte, len(files))
+
+	for i, file := range files {
+		content, err := os.ReadFile(file)
+
+		if err != nil {
+			return false, fmt.Errorf("can't read %v : %w", file, err)
+		}
+
+		contents[i] = content
+	}
+
+	if r.unchanged(contents) {
+		return false, nil
+	}
+
+	cert, err := tls.X509KeyPair(contents[0], contents[1])
+
+	if err != nil {
+		return false, fmt.Errorf("can't load server key pair : %w", err)
+	}
+
+	// returned in place of the config of the server, so it has to offer HTTP/2 through ALPN itself
+	config := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+		NextProtos:   []string{"h2"},
+	}
+
+	if r.clientCAFile != "" {
+		clientCAs := x509.NewCertPool()
+
+		if !clientCAs.AppendCertsFromPEM(contents[2]) {
+			return false, fmt.Errorf("no certificate found in client CA file %v", r.clientCAFile)
+		}
+
+		config.ClientCAs = clientCAs
+		config.ClientAuth = tls.VerifyClientCertIfGiven
+
+		if r.requireClientCert {
+			config.ClientAuth = tls.RequireAndVerifyClientCert
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.config = config
+	r.contents = contents
+
+	return true, nil
+}
+
+func (r *CertificateReloader) unchanged(contents [][]byte) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if len(r.contents) != len(contents) {
+		return false
+	}
+
+	for i := range contents {
+		if !bytes.Equal(r.contents[i], contents[i]) {
+			return false
+		}
+	}
+
+	return true
+}
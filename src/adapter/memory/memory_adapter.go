@@ -0,0 +1,549 @@
+package memory
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"grpcbank/src/application/domain"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MemoryAdapter keeps the bank in process memory, for tests and demos. Every call holds one lock,
+// so calls are serialized and a call posting several entries commits all of them or none.
+// Nothing survives a restart.
+type MemoryAdapter struct {
+	mu              sync.Mutex
+	accounts        map[uuid.UUID]domain.Account
+	accountNumbers  map[string]uuid.UUID
+	exchangeRates   []domain.ExchangeRate
+	transactions    []domain.LedgerEntry
+	transactionKeys map[string]int
+	transfers       map[uuid.UUID]domain.Transfer
+	transferKeys    map[string]uuid.UUID
+}
+
+func NewMemoryAdapter() *MemoryAdapter {
+	return &MemoryAdapter{
+		accounts:        map[uuid.UUID]domain.Account{},
+		accountNumbers:  map[string]uuid.UUID{},
+		transactionKeys: map[string]int{},
+		transfers:       map[uuid.UUID]domain.Transfer{},
+		transferKeys:    map[string]uuid.UUID{},
+	}
+}
+
+// CreateAccount opens acct with its balance. The bank service never opens accounts, so this isn't
+// part of port.BankDatabasePort.
+func (a *MemoryAdapter) CreateAccount(ctx context.Context, acct domain.Account) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if _, found := a.accounts[acct.AccountUuid]; found {
+		return fmt.Errorf("account %v already exists", acct.AccountUuid)
+	}
+
+	if _, found := a.accountNumbers[acct.AccountNumber]; found {
+		return fmt.Errorf("account number %v already exists", acct.AccountNumber)
+	}
+
+	a.accounts[acct.AccountUuid] = acct
+	a.accountNumbers[acct.AccountNumber] = acct.AccountUuid
+
+	return nil
+}
+
+func (a *MemoryAdapter) Ping(ctx context.Context) error {
+	return ctx.Err()
+}
+
+func (a *MemoryAdapter) GetBankAccountByAccountNumber(ctx context.Context,
+	accountNumber string) (domain.Account, error) {
+	if err := ctx.Err(); err != nil {
+		return domain.Account{}, err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	accountUuid, found := a.accountNumbers[accountNumber]
+
+	if !found {
+		return domain.Account{}, fmt.Errorf("%w : %v", domain.ErrAccountNotFound, accountNumber)
+	}
+
+	return a.accounts[accountUuid], nil
+}
+
+func (a *MemoryAdapter) CreateExchangeRate(ctx context.Context, exchangeRate domain.ExchangeRate) (uuid.UUID, error) {
+	if err := ctx.Err(); err != nil {
+		return uuid.Nil, err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.exchangeRates = append(a.exchangeRates, exchangeRate)
+
+	return uuid.New(), nil
+}
+
+func (a *MemoryAdapter) GetExchangeRateAtTimestamp(ctx context.Context, fromCur string, toCur string,
+	timeStamp time.Time) (domain.ExchangeRate, error) {
+	if err := ctx.Err(); err != nil {
+		return domain.ExchangeRate{}, err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	var effective *domain.ExchangeRate
+
+	for i, exchangeRate := range a.exchangeRates {
+		if exchangeRate.FromCurrency != fromCur || exchangeRate.ToCurrency != toCur ||
+			timeStamp.Before(exchangeRate.ValidFromTimestamp) || timeStamp.After(exchangeRate.ValidToTimestamp) {
+			continue
+		}
+
+		// the latest starting window wins should windows overlap
+		if effective == nil || exchangeRate.ValidFromTimestamp.After(effective.ValidFromTimestamp) {
+			effective = &a.exchangeRates[i]
+		}
+	}
+
+	if effective == nil {
+		return domain.ExchangeRate{}, fmt.Errorf("%w : %v to %v", domain.ErrExchangeRateNotFound, fromCur, toCur)
+	}
+
+	return *effective, nil
+}
+
+func (a *MemoryAdapter) FindNextExchangeRateStart(ctx context.Context, fromCur string, toCur string,
+	after time.Time) (time.Time, bool, error) {
+	if err := ctx.Err(); err != nil {
+		return time.Time{}, false, err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	var next time.Time
+
+	for _, exchangeRate := range a.exchangeRates {
+		if exchangeRate.FromCurrency != fromCur || exchangeRate.ToCurrency != toCur ||
+			!exchangeRate.ValidFromTimestamp.After(after) {
+			continue
+		}
+
+		if next.IsZero() || exchangeRate.ValidFromTimestamp.Before(next) {
+			next = exchangeRate.ValidFromTimestamp
+		}
+	}
+
+	return next, !next.IsZero(), nil
+}
+
+// FindTransactionByIdempotencyKey returns the transaction created with key. A key older than
+// retainedSince has expired: it is released so it can be reused and reported as not found.
+func (a *MemoryAdapter) FindTransactionByIdempotencyKey(ctx context.Context, key string,
+	retainedSince time.Time) (domain.LedgerEntry, bool, error) {
+	if err := ctx.Err(); err != nil {
+		return domain.LedgerEntry{}, false, err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	i, found := a.transactionKeys[key]
+
+	if !found {
+		return domain.LedgerEntry{}, false, nil
+	}
+
+	if a.transactions[i].Timestamp.Before(retainedSince) {
+		a.transactions[i].IdempotencyKey = ""
+		delete(a.transactionKeys, key)
+
+		return domain.LedgerEntry{}, false, nil
+	}
+
+	return a.transactions[i], true, nil
+}
+
+// ListTransactions returns up to limit transactions of acct matching filter, newest first,
+// starting after the given cursor when it is set.
+func (a *MemoryAdapter) ListTransactions(ctx context.Context, acct domain.Account, filter domain.TransactionFilter,
+	after *domain.TransactionCursor, limit int) ([]domain.LedgerEntry, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	entries := make([]domain.LedgerEntry, 0)
+
+	for _, entry := range a.transactions {
+		if entry.AccountUuid != acct.AccountUuid {
+			continue
+		}
+
+		matches, err := matchesFilter(entry, filter)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if matches && (after == nil || isBefore(entry, after.Timestamp, after.TransactionUuid)) {
+			entries = append(entries, entry)
+		}
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return isBefore(entries[j], entries[i].Timestamp, entries[i].TransactionUuid)
+	})
+
+	if len(entries) > limit {
+		entries = entries[:limit]
+	}
+
+	return entries, nil
+}
+
+// CreateTransaction posts entry to its account and returns the account as it was committed.
+func (a *MemoryAdapter) CreateTransaction(ctx context.Context, entry domain.LedgerEntry) (domain.Account, error) {
+	if err := ctx.Err(); err != nil {
+		return domain.Account{}, err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	tx := a.begin()
+	account, err := tx.post(entry)
+
+	if err != nil {
+		return domain.Account{}, err
+	}
+
+	tx.commit()
+
+	return account, nil
+}
+
+// CreateTransactionBatch posts every entry to its account, so either all of them are committed or
+// none is. It returns each account as it was right after the entry at the same index was posted.
+// On failure it returns the index of the entry that failed, or -1 when the failure isn't tied to
+// a single entry.
+func (a *MemoryAdapter) CreateTransactionBatch(ctx context.Context,
+	entries []domain.LedgerEntry) ([]domain.Account, int, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, -1, err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	tx := a.begin()
+	accounts := make([]domain.Account, 0, len(entries))
+
+	for i, entry := range entries {
+		account, err := tx.post(entry)
+
+		if err != nil {
+			return nil, i, err
+		}
+
+		accounts = append(accounts, account)
+	}
+
+	tx.commit()
+
+	return accounts, -1, nil
+}
+
+// FindTransferByIdempotencyKey returns the transfer created with key, releasing the key
+// like FindTransactionByIdempotencyKey once it is older than retainedSince.
+func (a *MemoryAdapter) FindTransferByIdempotencyKey(ctx context.Context, key string,
+	retainedSince time.Time) (domain.Transfer, bool, error) {
+	if err := ctx.Err(); err != nil {
+		return domain.Transfer{}, false, err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	transferUuid, found := a.transferKeys[key]
+
+	if !found {
+		return domain.Transfer{}, false, nil
+	}
+
+	transfer := a.transfers[transferUuid]
+
+	if transfer.Timestamp.Before(retainedSince) {
+		transfer.IdempotencyKey = ""
+		a.transfers[transferUuid] = transfer
+		delete(a.transferKeys, key)
+
+		return domain.Transfer{}, false, nil
+	}
+
+	return transfer, true, nil
+}
+
+func (a *MemoryAdapter) CreateTransfer(ctx context.Context, transfer domain.Transfer) (uuid.UUID, error) {
+	if err := ctx.Err(); err != nil {
+		return uuid.Nil, err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if _, found := a.transfers[transfer.TransferUuid]; found {
+		return uuid.Nil, fmt.Errorf("transfer %v already exists", transfer.TransferUuid)
+	}
+
+	if transfer.IdempotencyKey != "" {
+		if _, found := a.transferKeys[transfer.IdempotencyKey]; found {
+			return uuid.Nil, domain.ErrDuplicateIdempotencyKey
+		}
+
+		a.transferKeys[transfer.IdempotencyKey] = transfer.TransferUuid
+	}
+
+	a.transfers[transfer.TransferUuid] = transfer
+
+	return transfer.TransferUuid, nil
+}
+
+// CreateTransferTransactionPair posts both legs of a pending transfer and marks it succeeded at
+// once. It returns the source and destination accounts as they were committed.
+func (a *MemoryAdapter) CreateTransferTransactionPair(ctx context.Context, transfer domain.Transfer,
+	fromEntry domain.LedgerEntry, toEntry domain.LedgerEntry) (domain.Account, domain.Account, error) {
+	if err := ctx.Err(); err != nil {
+		return domain.Account{}, domain.Account{}, err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	storedTransfer, found := a.transfers[transfer.TransferUuid]
+
+	if !found || storedTransfer.Status != domain.TransferStatusPending {
+		return domain.Account{}, domain.Account{}, fmt.Errorf("%w : %v", domain.ErrTransferNotPending,
+			transfer.TransferUuid)
+	}
+
+	tx := a.begin()
+
+	if _, err := tx.post(fromEntry); err != nil {
+		return domain.Account{}, domain.Account{}, err
+	}
+
+	if _, err := tx.post(toEntry); err != nil {
+		return domain.Account{}, domain.Account{}, err
+	}
+
+	tx.commit()
+
+	storedTransfer.Status = domain.TransferStatusSucceeded
+	a.transfers[transfer.TransferUuid] = storedTransfer
+
+	return a.accounts[fromEntry.AccountUuid], a.accounts[toEntry.AccountUuid], nil
+}
+
+// FailTransfer marks a pending transfer failed for reason.
+func (a *MemoryAdapter) FailTransfer(ctx context.Context, transferUuid uuid.UUID, reason string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	storedTransfer, found := a.transfers[transferUuid]
+
+	if !found || storedTransfer.Status != domain.TransferStatusPending {
+		return fmt.Errorf("%w : %v", domain.ErrTransferNotPending, transferUuid)
+	}
+
+	storedTransfer.Status = domain.TransferStatusFailed
+	storedTransfer.FailureReason = reason
+	a.transfers[transferUuid] = storedTransfer
+
+	return nil
+}
+
+// IsProduction is always false, a memory adapter only holds demo data.
+func (a *MemoryAdapter) IsProduction(ctx context.Context) (bool, error) {
+	return false, ctx.Err()
+}
+
+// SeedAccount opens acct with a zero balance and posts entries to it, unless its UUID or number is
+// taken. The account is removed again if an entry fails.
+func (a *MemoryAdapter) SeedAccount(ctx context.Context, acct domain.Account,
+	entries []domain.LedgerEntry) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if _, found := a.accounts[acct.AccountUuid]; found {
+		return false, nil
+	}
+
+	if _, found := a.accountNumbers[acct.AccountNumber]; found {
+		return false, nil
+	}
+
+	acct.Balance = domain.ZeroMoney(acct.Currency())
+	acct.Version = 0
+	a.accounts[acct.AccountUuid] = acct
+	a.accountNumbers[acct.AccountNumber] = acct.AccountUuid
+
+	tx := a.begin()
+
+	for _, entry := range entries {
+		if _, err := tx.post(entry); err != nil {
+			delete(a.accounts, acct.AccountUuid)
+			delete(a.accountNumbers, acct.AccountNumber)
+
+			return false, err
+		}
+	}
+
+	tx.commit()
+
+	return true, nil
+}
+
+// ledgerTx stages the entries of one call and the balances they lead to, the adapter is only
+// changed by commit.
+type ledgerTx struct {
+	adapter  *MemoryAdapter
+	accounts map[uuid.UUID]domain.Account
+	entries  []domain.LedgerEntry
+	keys     map[string]bool
+}
+
+func (a *MemoryAdapter) begin() *ledgerTx {
+	return &ledgerTx{
+		adapter:  a,
+		accounts: map[uuid.UUID]domain.Account{},
+		keys:     map[string]bool{},
+	}
+}
+
+// post applies entry to the staged balance of its account and returns the account.
+func (tx *ledgerTx) post(entry domain.LedgerEntry) (domain.Account, error) {
+	account, found := tx.accounts[entry.AccountUuid]
+
+	if !found {
+		account, found = tx.adapter.accounts[entry.AccountUuid]
+	}
+
+	if !found {
+		return domain.Account{}, fmt.Errorf("%w : %v", domain.ErrAccountNotFound, entry.AccountUuid)
+	}
+
+	if key := entry.IdempotencyKey; key != "" {
+		if _, used := tx.adapter.transactionKeys[key]; used || tx.keys[key] {
+			return domain.Account{}, domain.ErrDuplicateIdempotencyKey
+		}
+
+		tx.keys[key] = true
+	}
+
+	var balance domain.Money
+	var err error
+
+	if entry.TransactionType == domain.TransactionTypeOut {
+		balance, err = account.Balance.Sub(entry.Amount)
+	} else {
+		balance, err = account.Balance.Add(entry.Amount)
+	}
+
+	if err != nil {
+		return domain.Account{}, err
+	}
+
+	if balance.IsNegative() {
+		return domain.Account{}, domain.ErrInsufficientBalance
+	}
+
+	account.Balance = balance
+	account.Version++
+	tx.accounts[account.AccountUuid] = account
+	tx.entries = append(tx.entries, entry)
+
+	return account, nil
+}
+
+func (tx *ledgerTx) commit() {
+	a := tx.adapter
+
+	for accountUuid, account := range tx.accounts {
+		a.accounts[accountUuid] = account
+	}
+
+	for _, entry := range tx.entries {
+		if entry.IdempotencyKey != "" {
+			a.transactionKeys[entry.IdempotencyKey] = len(a.transactions)
+		}
+
+		a.transactions = append(a.transactions, entry)
+	}
+}
+
+func matchesFilter(entry domain.LedgerEntry, filter domain.TransactionFilter) (bool, error) {
+	if !filter.FromTimestamp.IsZero() && entry.Timestamp.Before(filter.FromTimestamp) {
+		return false, nil
+	}
+
+	if !filter.ToTimestamp.IsZero() && entry.Timestamp.After(filter.ToTimestamp) {
+		return false, nil
+	}
+
+	if filter.TransactionType != "" && entry.TransactionType != filter.TransactionType {
+		return false, nil
+	}
+
+	if filter.MinAmount != nil {
+		if cmp, err := entry.Amount.Cmp(*filter.MinAmount); err != nil || cmp < 0 {
+			return false, err
+		}
+	}
+
+	if filter.MaxAmount != nil {
+		if cmp, err := entry.Amount.Cmp(*filter.MaxAmount); err != nil || cmp > 0 {
+			return false, err
+		}
+	}
+
+	if filter.NotesContains != "" &&
+		!strings.Contains(strings.ToLower(entry.Notes), strings.ToLower(filter.NotesContains)) {
+		return false, nil
+	}
+
+	return true, nil
+}
+
+// isBefore reports whether entry comes before the given position in ascending (timestamp, uuid)
+// order. UUIDs compare bytewise like Postgres does.
+func isBefore(entry domain.LedgerEntry, timestamp time.Time, transactionUuid uuid.UUID) bool {
+	if !entry.Timestamp.Equal(timestamp) {
+		return entry.Timestamp.Before(timestamp)
+	}
+
+	return bytes.Compare(entry.TransactionUuid[:], transactionUuid[:]) < 0
+}
@@ -0,0 +1,42 @@
+package memory
+
+import (
+	"context"
+	"grpcbank/src/application/domain"
+	"grpcbank/src/port"
+	"grpcbank/src/port/porttest"
+	"testing"
+)
+
+func TestMemoryAdapter(t *testing.T) {
+	porttest.TestBankDatabasePort(t, func(t *testing.T, accounts []domain.Account) port.BankDatabasePort {
+		adapter := NewMemoryAdapter()
+
+		for _, acct := range accounts {
+			if err := adapter.CreateAccount(context.Background(), acct); err != nil {
+				t.Fatalf("CreateAccount : %v", err)
+			}
+		}
+
+		return adapter
+	})
+}
+
+// productionAdapter is a memory adapter claiming to be a production database.
+type productionAdapter struct {
+	*MemoryAdapter
+}
+
+func (productionAdapter) IsProduction(context.Context) (bool, error) {
+	return true, nil
+}
+
+func TestMemoryAdapterSeed(t *testing.T) {
+	porttest.TestBankSeedPort(t, func(t *testing.T, production bool) porttest.SeedDatabase {
+		if production {
+			return productionAdapter{NewMemoryAdapter()}
+		}
+
+		return NewMemoryAdapter()
+	})
+}
@@ -0,0 +1,72 @@
+package metrics
+
+import (
+	"grpcbank/src/application"
+	"grpcbank/src/application/domain"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const namespace = "bank"
+
+// PrometheusMetrics exposes the business events of the bank as Prometheus metrics.
+type PrometheusMetrics struct {
+	transfers          *prometheus.CounterVec
+	transactions       *prometheus.CounterVec
+	transactionAmounts *prometheus.CounterVec
+	exchangeRates      *prometheus.CounterVec
+}
+
+func NewPrometheusMetrics(registerer prometheus.Registerer) (*PrometheusMetrics, error) {
+	m := &PrometheusMetrics{
+		transfers: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "transfers_total",
+			Help:      "Transfers by outcome, success or the reason they failed.",
+		}, []string{"outcome"}),
+		transactions: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "transactions_total",
+			Help:      "Transactions by type and outcome, success or the reason they failed.",
+		}, []string{"type", "outcome"}),
+		transactionAmounts: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "transaction_amount_total",
+			Help:      "Sum of the amounts of posted transactions, in major units of their currency.",
+		}, []string{"currency", "type"}),
+		exchangeRates: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "exchange_rates_created_total",
+			Help:      "Exchange rates created, by currency pair.",
+		}, []string{"from_currency", "to_currency"}),
+	}
+
+	for _, collector := range []prometheus.Collector{m.transfers, m.transactions, m.transactionAmounts,
+		m.exchangeRates} {
+		if err := registerer.Register(collector); err != nil {
+			return nil, err
+		}
+	}
+
+	return m, nil
+}
+
+func (m *PrometheusMetrics) RecordTransfer(outcome string) {
+	m.transfers.WithLabelValues(outcome).Inc()
+}
+
+// RecordTransaction labels amounts by currency only once posted, since the currency of a rejected
+// transaction is whatever the client sent.
+func (m *PrometheusMetrics) RecordTransaction(transactionType string, amount domain.Money, outcome string) {
+	m.transactions.WithLabelValues(transactionType, outcome).Inc()
+
+	if outcome == application.OutcomeSuccess && amount.IsPositive() {
+		units, nanos := amount.UnitsNanos()
+		m.transactionAmounts.WithLabelValues(amount.Currency, transactionType).
+			Add(float64(units) + float64(nanos)/1e9)
+	}
+}
+
+func (m *PrometheusMetrics) RecordExchangeRate(pair domain.CurrencyPair) {
+	m.exchangeRates.WithLabelValues(pair.FromCurrency, pair.ToCurrency).Inc()
+}
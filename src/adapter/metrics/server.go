@@ -0,0 +1,49 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Server serves the metrics of gatherer on /metrics over plain HTTP.
+type Server struct {
+	server *http.Server
+	logger *slog.Logger
+}
+
+func NewServer(listenAddress string, gatherer prometheus.Gatherer, logger *slog.Logger) *Server {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(gatherer, promhttp.HandlerOpts{
+		ErrorLog: slog.NewLogLogger(logger.Handler(), slog.LevelError),
+	}))
+
+	return &Server{
+		server: &http.Server{
+			Addr:              listenAddress,
+			Handler:           mux,
+			ReadHeaderTimeout: 5 * time.Second,
+		},
+		logger: logger,
+	}
+}
+
+// Run serves until Shutdown is called.
+func (s *Server) Run() {
+	s.logger.Info("Metrics listening", slog.String("address", s.server.Addr))
+
+	if err := s.server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		s.logger.Error("Failed to serve metrics", slog.String("address", s.server.Addr), slog.Any("error", err))
+	}
+}
+
+func (s *Server) Shutdown(ctx context.Context) {
+	if err := s.server.Shutdown(ctx); err != nil {
+		s.logger.Error("Can't stop metrics server", slog.Any("error", err))
+	}
+}
@@ -0,0 +1,155 @@
+package application
+
+import (
+	"context"
+	"grpcbank/src/application/domain"
+	"grpcbank/src/logging"
+	"grpcbank/src/port"
+	"log/slog"
+	"sync"
+)
+
+const balanceSubscriptionBuffer = 16
+
+type balanceSubscription struct {
+	updates chan domain.BalanceUpdate
+	// version of the newest update queued for the subscriber
+	version int64
+}
+
+// BalanceBroker fans committed balance updates out to in-process subscribers. With a relay
+// attached, updates travel through the relay so subscribers on every instance see them.
+type BalanceBroker struct {
+	mu            sync.Mutex
+	subscriptions map[string]map[*balanceSubscription]struct{}
+	relay         port.BalanceRelayPort
+	logger        *slog.Logger
+}
+
+func NewBalanceBroker(logger *slog.Logger) *BalanceBroker {
+	return &BalanceBroker{
+		subscriptions: make(map[string]map[*balanceSubscription]struct{}),
+		logger:        logger,
+	}
+}
+
+func (b *BalanceBroker) UseRelay(relay port.BalanceRelayPort) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.relay = relay
+}
+
+// Subscribe returns a channel of updates for accountNumber and a function ending the subscription.
+// The channel starts with the update returned by current, which is loaded once the subscription is
+// in place so no later change is missed. Relayed updates can arrive out of order, an update no newer
+// than one already queued is dropped.
+func (b *BalanceBroker) Subscribe(accountNumber string,
+	current func() (domain.BalanceUpdate, error)) (<-chan domain.BalanceUpdate, func(), error) {
+	sub := &balanceSubscription{
+		// room for the current balance ahead of the buffered updates
+		updates: make(chan domain.BalanceUpdate, balanceSubscriptionBuffer+1),
+	}
+
+	b.mu.Lock()
+
+	if b.subscriptions[accountNumber] == nil {
+		b.subscriptions[accountNumber] = make(map[*balanceSubscription]struct{})
+	}
+
+	b.subscriptions[accountNumber][sub] = struct{}{}
+	b.mu.Unlock()
+
+	var once sync.Once
+
+	unsubscribe := func() {
+		once.Do(func() {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+
+			delete(b.subscriptions[accountNumber], sub)
+
+			if len(b.subscriptions[accountNumber]) == 0 {
+				delete(b.subscriptions, accountNumber)
+			}
+
+			close(sub.updates)
+		})
+	}
+
+	snapshot, err := current()
+
+	if err != nil {
+		unsubscribe()
+		return nil, nil, err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	pending := make([]domain.BalanceUpdate, 0, len(sub.updates))
+
+	for len(sub.updates) > 0 {
+		pending = append(pending, <-sub.updates)
+	}
+
+	sub.updates <- snapshot
+	sub.version = snapshot.Version
+
+	for _, update := range pending {
+		if update.Version > sub.version {
+			sub.updates <- update
+			sub.version = update.Version
+		}
+	}
+
+	return sub.updates, unsubscribe, nil
+}
+
+// Publish sends a committed update to the subscribers of its account. The update is relayed with
+// ctx stripped of its cancellation, since the change it reports is already committed.
+func (b *BalanceBroker) Publish(ctx context.Context, update domain.BalanceUpdate) {
+	b.mu.Lock()
+	relay := b.relay
+	b.mu.Unlock()
+
+	if relay != nil {
+		err := relay.PublishBalanceUpdate(context.WithoutCancel(ctx), update)
+
+		if err == nil {
+			return
+		}
+
+		b.logger.ErrorContext(ctx, "Can't relay balance update, delivering locally",
+			slog.String(logging.AccountNumberKey, update.AccountNumber), slog.Any("error", err))
+	}
+
+	b.Deliver(update)
+}
+
+// Deliver hands update to the local subscribers of its account. A subscriber that falls behind
+// loses its oldest pending update rather than blocking the publisher, a subscriber that has
+// already been handed a newer balance doesn't get the update at all.
+func (b *BalanceBroker) Deliver(update domain.BalanceUpdate) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for sub := range b.subscriptions[update.AccountNumber] {
+		if update.Version <= sub.version {
+			continue
+		}
+
+		sub.version = update.Version
+
+		select {
+		case sub.updates <- update:
+		default:
+			select {
+			case <-sub.updates:
+			default:
+			}
+
+			sub.updates <- update
+		}
+	}
+}
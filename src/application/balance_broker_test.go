@@ -0,0 +1,164 @@
+package application_test
+
+import (
+	"context"
+	"errors"
+	"grpcbank/src/application"
+	"grpcbank/src/application/domain"
+	"io"
+	"log/slog"
+	"testing"
+)
+
+func balanceUpdate(version int64) domain.BalanceUpdate {
+	return domain.BalanceUpdate{
+		AccountNumber: "7835697001",
+		Balance:       domain.NewMoney("USD", 100*version),
+		Version:       version,
+	}
+}
+
+func currentBalance(version int64) func() (domain.BalanceUpdate, error) {
+	return func() (domain.BalanceUpdate, error) {
+		return balanceUpdate(version), nil
+	}
+}
+
+// receivedVersions drains the updates queued on updates.
+func receivedVersions(updates <-chan domain.BalanceUpdate) []int64 {
+	var versions []int64
+
+	for {
+		select {
+		case update := <-updates:
+			versions = append(versions, update.Version)
+		default:
+			return versions
+		}
+	}
+}
+
+func expectVersions(t *testing.T, got []int64, want ...int64) {
+	t.Helper()
+
+	if len(got) != len(want) {
+		t.Fatalf("versions = %v, want %v", got, want)
+	}
+
+	for i := range got {
+		if got[i] != want[i] {
+			t.Fatalf("versions = %v, want %v", got, want)
+		}
+	}
+}
+
+func newBalanceBroker() *application.BalanceBroker {
+	return application.NewBalanceBroker(slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
+
+func TestBalanceBrokerDropsStaleUpdates(t *testing.T) {
+	broker := newBalanceBroker()
+	updates, unsubscribe, err := broker.Subscribe("7835697001", currentBalance(2))
+
+	if err != nil {
+		t.Fatalf("Subscribe : %v", err)
+	}
+
+	defer unsubscribe()
+
+	for _, version := range []int64{1, 2, 4, 3, 5} {
+		broker.Deliver(balanceUpdate(version))
+	}
+
+	expectVersions(t, receivedVersions(updates), 2, 4, 5)
+}
+
+func TestBalanceBrokerKeepsUpdatesCommittedWhileSubscribing(t *testing.T) {
+	broker := newBalanceBroker()
+
+	updates, unsubscribe, err := broker.Subscribe("7835697001", func() (domain.BalanceUpdate, error) {
+		// committed before and after the current balance was read
+		broker.Deliver(balanceUpdate(3))
+		broker.Deliver(balanceUpdate(5))
+
+		return balanceUpdate(4), nil
+	})
+
+	if err != nil {
+		t.Fatalf("Subscribe : %v", err)
+	}
+
+	defer unsubscribe()
+
+	expectVersions(t, receivedVersions(updates), 4, 5)
+}
+
+func TestBalanceBrokerSubscribeFailure(t *testing.T) {
+	broker := newBalanceBroker()
+	errNoAccount := errors.New("no account")
+
+	_, _, err := broker.Subscribe("7835697001", func() (domain.BalanceUpdate, error) {
+		return domain.BalanceUpdate{}, errNoAccount
+	})
+
+	if !errors.Is(err, errNoAccount) {
+		t.Fatalf("Subscribe = %v, want %v", err, errNoAccount)
+	}
+
+	// the failed subscription is gone, delivering must not touch its closed channel
+	broker.Deliver(balanceUpdate(1))
+}
+
+type contextKey struct{}
+
+type recordingRelay struct {
+	ctx     context.Context
+	updates []domain.BalanceUpdate
+	err     error
+}
+
+func (r *recordingRelay) PublishBalanceUpdate(ctx context.Context, update domain.BalanceUpdate) error {
+	r.ctx = ctx
+	r.updates = append(r.updates, update)
+
+	return r.err
+}
+
+func TestBalanceBrokerRelaysCommittedUpdates(t *testing.T) {
+	broker := newBalanceBroker()
+	relay := &recordingRelay{}
+	broker.UseRelay(relay)
+
+	updates, unsubscribe, err := broker.Subscribe("7835697001", currentBalance(1))
+
+	if err != nil {
+		t.Fatalf("Subscribe : %v", err)
+	}
+
+	defer unsubscribe()
+
+	ctx, cancel := context.WithCancel(context.WithValue(context.Background(), contextKey{}, "request"))
+	cancel()
+
+	broker.Publish(ctx, balanceUpdate(2))
+
+	if len(relay.updates) != 1 || relay.updates[0].Version != 2 {
+		t.Fatalf("relayed updates = %+v, want version 2", relay.updates)
+	}
+
+	if err := relay.ctx.Err(); err != nil {
+		t.Errorf("relay context error = %v, want none", err)
+	}
+
+	if got := relay.ctx.Value(contextKey{}); got != "request" {
+		t.Errorf("relay context value = %v, want request", got)
+	}
+
+	// relayed updates come back through Deliver
+	expectVersions(t, receivedVersions(updates), 1)
+
+	relay.err = errors.New("connection refused")
+	broker.Publish(ctx, balanceUpdate(3))
+
+	expectVersions(t, receivedVersions(updates), 3)
+}
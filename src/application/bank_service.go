@@ -1,184 +1,709 @@
 package application
 
 import (
+	"context"
+	"encoding/base64"
+	"errors"
 	"fmt"
 	"github.com/google/uuid"
-	"grpcbank/src/adapter/database"
 	"grpcbank/src/application/domain"
+	"grpcbank/src/logging"
 	"grpcbank/src/port"
-	"log"
+	"log/slog"
+	"strings"
 	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type BankService struct {
-	db port.BankDatabasePort
+	db                   port.BankDatabasePort
+	balances             *BalanceBroker
+	exchangeRates        *ExchangeRateBroker
+	idempotencyRetention time.Duration
+	logger               *slog.Logger
 }
 
-func NewBankService(dbPort port.BankDatabasePort) *BankService {
+// NewBankService creates the service. Committed balance changes are published to balances and
+// created rates to exchangeRates, and idempotencyRetention is how long an idempotency key keeps
+// returning the original outcome before it may be reused for a new request.
+func NewBankService(dbPort port.BankDatabasePort, balances *BalanceBroker, exchangeRates *ExchangeRateBroker,
+	idempotencyRetention time.Duration, logger *slog.Logger) *BankService {
 	return &BankService{
-		db: dbPort,
+		db:                   dbPort,
+		balances:             balances,
+		exchangeRates:        exchangeRates,
+		idempotencyRetention: idempotencyRetention,
+		logger:               logger,
 	}
 }
 
-func (s *BankService) FindCurrentBalance(accountNumber string) (float64, error) {
-	bankAccount, err := s.db.GetBankAccountByAccountNumber(accountNumber)
+// Ping reports whether the bank storage is reachable.
+func (s *BankService) Ping(ctx context.Context) error {
+	return s.db.Ping(ctx)
+}
+
+func (s *BankService) FindCurrentBalance(ctx context.Context, accountNumber string) (domain.Money, error) {
+	bankAccount, err := s.db.GetBankAccountByAccountNumber(ctx, accountNumber)
 
 	if err != nil {
-		log.Println("Error on FindCurrentBalance :", err)
-		return 0, err
+		s.logger.WarnContext(ctx, "Can't find current balance", slog.String(logging.AccountNumberKey, accountNumber),
+			slog.Any("error", err))
+		return domain.Money{}, err
+	}
+
+	if err := authorizeAccount(ctx, bankAccount); err != nil {
+		return domain.Money{}, err
 	}
 
-	return bankAccount.CurrentBalance, nil
+	return bankAccount.Balance, nil
 }
 
-func (s *BankService) CreateExchangeRate(exchangeRate domain.ExchangeRate) (uuid.UUID, error) {
-	newUuid := uuid.New()
-	now := time.Now()
+func (s *BankService) CreateExchangeRate(ctx context.Context, exchangeRate domain.ExchangeRate) (uuid.UUID, error) {
+	savedUuid, err := s.db.CreateExchangeRate(ctx, exchangeRate)
 
-	exchangeRateOrm := database.BankExchangeRateOrm{
-		ExchangeRateUuid:   newUuid,
-		FromCurrency:       exchangeRate.FromCurrency,
-		ToCurrency:         exchangeRate.ToCurrency,
-		Rate:               exchangeRate.Rate,
-		ValidFromTimestamp: exchangeRate.ValidFromTimestamp,
-		ValidToTimestamp:   exchangeRate.ValidToTimestamp,
-		CreatedAt:          now,
-		UpdatedAt:          now,
+	if err != nil {
+		return uuid.Nil, err
 	}
 
-	return s.db.CreateExchangeRate(exchangeRateOrm)
+	s.exchangeRates.Publish(exchangeRate)
+
+	return savedUuid, nil
 }
 
-func (s *BankService) FindExchangeRate(fromCur string, toCur string, ts time.Time) (float64, error) {
-	exchangeRate, err := s.db.GetExchangeRateAtTimestamp(fromCur, toCur, ts)
+func (s *BankService) FindExchangeRate(ctx context.Context, fromCur string, toCur string,
+	ts time.Time) (domain.Rate, error) {
+	exchangeRate, err := s.db.GetExchangeRateAtTimestamp(ctx, fromCur, toCur, ts)
 
 	if err != nil {
-		return 0, err
+		return domain.Rate{}, err
 	}
 
 	return exchangeRate.Rate, nil
 }
 
-func (s *BankService) CreateTransaction(acct string, bankTrx domain.Transaction) (uuid.UUID, error) {
-	newUuid := uuid.New()
+func (s *BankService) CreateTransaction(ctx context.Context, acct string, bankTrx domain.Transaction) (uuid.UUID, error) {
 	now := time.Now()
 
-	bankAccountOrm, err := s.db.GetBankAccountByAccountNumber(acct)
+	bankAccount, err := s.db.GetBankAccountByAccountNumber(ctx, acct)
 
 	if err != nil {
-		log.Printf("Can't create transaction for %v : %v\n", acct, err)
-		return uuid.Nil, fmt.Errorf("can't find account number %v : %v", acct, err.Error())
+		s.logger.WarnContext(ctx, "Can't create transaction", slog.String(logging.AccountNumberKey, acct),
+			slog.Any("error", err))
+		return uuid.Nil, fmt.Errorf("can't find account number %v : %w", acct, err)
 	}
 
-	if bankTrx.TransactionType == domain.TransactionTypeOut && bankAccountOrm.CurrentBalance < bankTrx.Amount {
-		return bankAccountOrm.AccountUuid, fmt.Errorf(
-			"insufficient account balance %v for [out] transaction amount %v",
-			bankAccountOrm.CurrentBalance, bankTrx.Amount,
+	if err := authorizeAccount(ctx, bankAccount); err != nil {
+		return uuid.Nil, err
+	}
+
+	if err := validateTransaction(bankAccount, bankTrx); err != nil {
+		return bankAccount.AccountUuid, err
+	}
+
+	if bankTrx.IdempotencyKey != "" {
+		existingUuid, found, err := s.findTransactionReplay(ctx, bankAccount, bankTrx, now)
+
+		if found || err != nil {
+			return existingUuid, err
+		}
+	}
+
+	entry := newLedgerEntry(bankAccount, bankTrx, now)
+
+	updatedAccount, err := s.db.CreateTransaction(ctx, entry)
+
+	if errors.Is(err, domain.ErrDuplicateIdempotencyKey) {
+		// a concurrent request with the same key won the race, report its outcome
+		existingUuid, found, err := s.findTransactionReplay(ctx, bankAccount, bankTrx, now)
+
+		if err == nil && !found {
+			return uuid.Nil, expiredReplayError(bankTrx.IdempotencyKey)
+		}
+
+		return existingUuid, err
+	}
+
+	if errors.Is(err, domain.ErrInsufficientBalance) {
+		return bankAccount.AccountUuid, fmt.Errorf(
+			"%w for [out] transaction amount %v", domain.ErrInsufficientBalance, bankTrx.Amount,
 		)
 	}
 
-	transactionOrm := database.BankTransactionOrm{
-		TransactionUuid:      newUuid,
-		AccountUuid:          bankAccountOrm.AccountUuid,
-		TransactionTimestamp: now,
-		Amount:               bankTrx.Amount,
-		TransactionType:      bankTrx.TransactionType,
-		Notes:                bankTrx.Notes,
-		CreatedAt:            now,
-		UpdatedAt:            now,
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	s.publishBalance(ctx, updatedAccount, entry)
+
+	return entry.TransactionUuid, nil
+}
+
+// authorizeAccount allows the principal of ctx to use only the accounts it owns, unless it was
+// granted domain.PermissionAccountsAny. Calls without a principal are trusted, see
+// domain.PrincipalFromContext.
+func authorizeAccount(ctx context.Context, acct domain.Account) error {
+	principal, ok := domain.PrincipalFromContext(ctx)
+
+	if !ok || principal.Can(domain.PermissionAccountsAny) ||
+		(principal.Subject != "" && principal.Subject == acct.OwnerSubject) {
+		return nil
+	}
+
+	return fmt.Errorf("%w : account number %v is not owned by the caller", domain.ErrPermissionDenied,
+		acct.AccountNumber)
+}
+
+func validateTransaction(acct domain.Account, bankTrx domain.Transaction) error {
+	if bankTrx.TransactionType != domain.TransactionTypeIn && bankTrx.TransactionType != domain.TransactionTypeOut {
+		return fmt.Errorf("%w : got %v", domain.ErrInvalidTransactionType, bankTrx.TransactionType)
+	}
+
+	if bankTrx.Amount.Currency != acct.Currency() {
+		return fmt.Errorf("%w : account %v is in %v, transaction is in %v",
+			domain.ErrCurrencyMismatch, acct.AccountNumber, acct.Currency(), bankTrx.Amount.Currency)
+	}
+
+	if !bankTrx.Amount.IsPositive() {
+		return fmt.Errorf("%w : transaction amount %v must be positive", domain.ErrInvalidAmount, bankTrx.Amount)
+	}
+
+	return nil
+}
+
+// validateTransactionFilter requires the amount bounds to be in the account currency, since the
+// storages compare them with the stored amounts without converting them.
+func validateTransactionFilter(acct domain.Account, filter domain.TransactionFilter) error {
+	for _, bound := range []*domain.Money{filter.MinAmount, filter.MaxAmount} {
+		if bound != nil && bound.Currency != acct.Currency() {
+			return fmt.Errorf("%w : account %v is in %v, amount filter is in %v",
+				domain.ErrCurrencyMismatch, acct.AccountNumber, acct.Currency(), bound.Currency)
+		}
+	}
+
+	return nil
+}
+
+func newLedgerEntry(acct domain.Account, bankTrx domain.Transaction, now time.Time) domain.LedgerEntry {
+	return domain.LedgerEntry{
+		TransactionUuid: uuid.New(),
+		AccountUuid:     acct.AccountUuid,
+		Timestamp:       now,
+		Amount:          bankTrx.Amount,
+		TransactionType: bankTrx.TransactionType,
+		Notes:           bankTrx.Notes,
+		IdempotencyKey:  bankTrx.IdempotencyKey,
+	}
+}
+
+const (
+	defaultTransactionPageSize = 50
+	maxTransactionPageSize     = 500
+)
+
+// ListTransactions returns one page of an account's transactions, newest first, and the token of
+// the next page, which is empty on the last page.
+func (s *BankService) ListTransactions(ctx context.Context, accountNumber string, filter domain.TransactionFilter, pageSize int,
+	pageToken string) ([]domain.Transaction, string, error) {
+	bankAccount, err := s.db.GetBankAccountByAccountNumber(ctx, accountNumber)
+
+	if err != nil {
+		return nil, "", fmt.Errorf("can't find account number %v : %w", accountNumber, err)
+	}
+
+	if err := authorizeAccount(ctx, bankAccount); err != nil {
+		return nil, "", err
+	}
+
+	if err := validateTransactionFilter(bankAccount, filter); err != nil {
+		return nil, "", err
+	}
+
+	if pageSize <= 0 {
+		pageSize = defaultTransactionPageSize
+	} else if pageSize > maxTransactionPageSize {
+		pageSize = maxTransactionPageSize
 	}
 
-	savedUuid, err := s.db.CreateTransaction(bankAccountOrm, transactionOrm)
+	var after *domain.TransactionCursor
+
+	if pageToken != "" {
+		cursor, err := decodePageToken(pageToken)
+
+		if err != nil {
+			return nil, "", err
+		}
+
+		after = &cursor
+	}
+
+	// one extra row tells whether another page follows
+	entries, err := s.db.ListTransactions(ctx, bankAccount, filter, after, pageSize+1)
+
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Can't list transactions", slog.String(logging.AccountNumberKey, accountNumber),
+			slog.Any("error", err))
+		return nil, "", err
+	}
 
-	return savedUuid, err
+	nextPageToken := ""
+
+	if len(entries) > pageSize {
+		entries = entries[:pageSize]
+		last := entries[pageSize-1]
+		nextPageToken = encodePageToken(domain.TransactionCursor{
+			Timestamp:       last.Timestamp,
+			TransactionUuid: last.TransactionUuid,
+		})
+	}
+
+	transactions := make([]domain.Transaction, 0, len(entries))
+
+	for _, entry := range entries {
+		transactions = append(transactions, toTransaction(bankAccount, entry))
+	}
+
+	return transactions, nextPageToken, nil
+}
+
+// toTransaction describes entry of acct to the callers of the service.
+func toTransaction(acct domain.Account, entry domain.LedgerEntry) domain.Transaction {
+	return domain.Transaction{
+		TransactionUuid: entry.TransactionUuid,
+		AccountNumber:   acct.AccountNumber,
+		Amount:          entry.Amount,
+		Timestamp:       entry.Timestamp,
+		TransactionType: entry.TransactionType,
+		Notes:           entry.Notes,
+	}
 }
 
-func (s *BankService) CalculateTransactionSummary(trxSummary *domain.TransactionSummary,
+func (s *BankService) CalculateTransactionSummary(ctx context.Context, trxSummary *domain.TransactionSummary,
 	bankTrx domain.Transaction) error {
+	if err := trxSummary.Accepts(bankTrx.Amount); err != nil {
+		return err
+	}
+
+	if trxSummary.SumIn.Currency == "" && trxSummary.SumIn.IsZero() && trxSummary.SumOut.IsZero() {
+		trxSummary.SumIn = domain.ZeroMoney(bankTrx.Amount.Currency)
+		trxSummary.SumOut = domain.ZeroMoney(bankTrx.Amount.Currency)
+	}
+
+	var err error
+
 	switch bankTrx.TransactionType {
 	case domain.TransactionTypeIn:
-		trxSummary.SumIn += bankTrx.Amount
+		trxSummary.SumIn, err = trxSummary.SumIn.Add(bankTrx.Amount)
 	case domain.TransactionTypeOut:
-		trxSummary.SumOut += bankTrx.Amount
+		trxSummary.SumOut, err = trxSummary.SumOut.Add(bankTrx.Amount)
 	default:
 		return fmt.Errorf("unknown transaction type %v", bankTrx.TransactionType)
 	}
 
-	trxSummary.SumTotal = trxSummary.SumIn - trxSummary.SumOut
+	if err != nil {
+		return err
+	}
 
-	return nil
+	trxSummary.SumTotal, err = trxSummary.SumIn.Sub(trxSummary.SumOut)
+
+	return err
 }
 
-func (s *BankService) Transfer(transferTrx domain.TransferTransaction) (uuid.UUID, bool, error) {
+func (s *BankService) transfer(ctx context.Context, transferTrx domain.TransferTransaction) (domain.Transfer,
+	error) {
 	now := time.Now()
 
-	fromAccountOrm, err := s.db.GetBankAccountByAccountNumber(transferTrx.FromAccountNumber)
+	fromAccount, err := s.db.GetBankAccountByAccountNumber(ctx, transferTrx.FromAccountNumber)
 
 	if err != nil {
-		log.Printf("Can't find transfer from account %v : %v\n", transferTrx.FromAccountNumber, err)
-		return uuid.Nil, false, domain.ErrTransferSourceAccountNotFound
+		s.logger.WarnContext(ctx, "Can't find transfer source account",
+			slog.String(logging.FromAccountNumberKey, transferTrx.FromAccountNumber), slog.Any("error", err))
+
+		if !errors.Is(err, domain.ErrAccountNotFound) {
+			return domain.Transfer{}, err
+		}
+
+		return domain.Transfer{}, domain.ErrTransferSourceAccountNotFound
 	}
 
-	if fromAccountOrm.CurrentBalance < transferTrx.Amount {
-		return uuid.Nil, false, domain.ErrTransferTransactionPair
+	// checked before replaying, so a key can't reveal the outcome of someone else's transfer
+	if err := authorizeAccount(ctx, fromAccount); err != nil {
+		return domain.Transfer{}, err
+	}
+
+	if transferTrx.IdempotencyKey != "" {
+		existing, found, err := s.findTransferReplay(ctx, transferTrx, now)
+		trace.SpanFromContext(ctx).SetAttributes(attribute.Bool("bank.transfer.replay", found))
+
+		if err != nil {
+			return domain.Transfer{}, err
+		}
+
+		if found {
+			return s.replayTransfer(ctx, existing, transferTrx)
+		}
 	}
 
-	toAccountOrm, err := s.db.GetBankAccountByAccountNumber(transferTrx.ToAccountNumber)
+	toAccount, err := s.db.GetBankAccountByAccountNumber(ctx, transferTrx.ToAccountNumber)
 
 	if err != nil {
-		log.Printf("Can't find transfer to account %v : %v\n", transferTrx.ToAccountNumber, err)
-		return uuid.Nil, false, domain.ErrTransferDestinationAccountNotFound
+		s.logger.WarnContext(ctx, "Can't find transfer destination account",
+			slog.String(logging.ToAccountNumberKey, transferTrx.ToAccountNumber), slog.Any("error", err))
+
+		if !errors.Is(err, domain.ErrAccountNotFound) {
+			return domain.Transfer{}, err
+		}
+
+		return domain.Transfer{}, domain.ErrTransferDestinationAccountNotFound
 	}
 
-	fromTransactionOrm := database.BankTransactionOrm{
-		TransactionUuid:      uuid.New(),
-		TransactionTimestamp: now,
-		TransactionType:      domain.TransactionTypeOut,
-		AccountUuid:          fromAccountOrm.AccountUuid,
-		Amount:               transferTrx.Amount,
-		Notes:                "Transfer out to " + transferTrx.ToAccountNumber,
-		CreatedAt:            now,
-		UpdatedAt:            now,
+	if !transferTrx.Amount.IsPositive() {
+		return domain.Transfer{}, domain.ErrInvalidAmount
+	}
+
+	sourceAmount, destinationAmount, rate, err := s.convertTransferAmount(ctx, fromAccount.Currency(),
+		toAccount.Currency(), transferTrx.Amount, now)
+
+	trace.SpanFromContext(ctx).SetAttributes(
+		attribute.String("bank.source_currency", fromAccount.Currency()),
+		attribute.String("bank.destination_currency", toAccount.Currency()),
+	)
+
+	if err != nil {
+		s.logger.WarnContext(ctx, "Can't convert transfer amount", transferAttrs(transferTrx),
+			slog.Any("error", err))
+		return domain.Transfer{}, err
 	}
 
-	toTransactionOrm := database.BankTransactionOrm{
-		TransactionUuid:      uuid.New(),
-		TransactionTimestamp: now,
-		TransactionType:      domain.TransactionTypeIn,
-		AccountUuid:          toAccountOrm.AccountUuid,
-		Amount:               transferTrx.Amount,
-		Notes:                "Transfer in from " + transferTrx.FromAccountNumber,
-		CreatedAt:            now,
-		UpdatedAt:            now,
+	transfer := domain.Transfer{
+		TransferUuid:    uuid.New(),
+		FromAccountUuid: fromAccount.AccountUuid,
+		ToAccountUuid:   toAccount.AccountUuid,
+		Amount:          transferTrx.Amount,
+		Conversion: domain.Conversion{
+			SourceAmount:      sourceAmount,
+			DestinationAmount: destinationAmount,
+			Rate:              rate,
+		},
+		Timestamp:      now,
+		Status:         domain.TransferStatusPending,
+		IdempotencyKey: transferTrx.IdempotencyKey,
 	}
 
-	newTransferUuid := uuid.New()
+	if _, err := s.db.CreateTransfer(ctx, transfer); errors.Is(err, domain.ErrDuplicateIdempotencyKey) {
+		existing, found, err := s.findTransferReplay(ctx, transferTrx, now)
+
+		if err == nil && !found {
+			return domain.Transfer{}, expiredReplayError(transferTrx.IdempotencyKey)
+		}
 
-	transferOrm := database.BankTransferOrm{
-		TransferUuid:      newTransferUuid,
-		FromAccountUuid:   fromAccountOrm.AccountUuid,
-		ToAccountUuid:     toAccountOrm.AccountUuid,
-		Currency:          transferTrx.Currency,
-		Amount:            transferTrx.Amount,
-		TransferTimestamp: now,
-		TransferSuccess:   false,
-		CreatedAt:         now,
-		UpdatedAt:         now,
+		if err != nil {
+			return domain.Transfer{}, err
+		}
+
+		return s.replayTransfer(ctx, existing, transferTrx)
+	} else if err != nil {
+		s.logger.ErrorContext(ctx, "Can't create transfer", transferAttrs(transferTrx), slog.Any("error", err))
+		return domain.Transfer{}, fmt.Errorf("%w : %w", domain.ErrTransferRecordFailed, err)
 	}
 
-	if _, err := s.db.CreateTransfer(transferOrm); err != nil {
-		log.Printf("Can't create transfer from %v to %v : %v\n", transferTrx.FromAccountNumber, transferTrx.ToAccountNumber, err)
-		return uuid.Nil, false, domain.ErrTransferRecordFailed
+	return s.postTransfer(ctx, transfer, transferTrx)
+}
+
+// replayTransfer answers transferTrx with the outcome of existing, the transfer recorded with its
+// idempotency key. A transfer still pending is posted: should the request that recorded it still
+// be posting it, one of the two postings waits for the other and then finds it posted, and should
+// that request have failed half way, the posting is resumed.
+func (s *BankService) replayTransfer(ctx context.Context, existing domain.Transfer,
+	transferTrx domain.TransferTransaction) (domain.Transfer, error) {
+	if existing.Status != domain.TransferStatusPending {
+		return existing, existing.Err()
+	}
+
+	return s.postTransfer(ctx, existing, transferTrx)
+}
+
+// postTransfer posts the legs of a pending transfer with the amounts recorded with it. A refused
+// posting fails the transfer for good, while any other failure leaves it pending so that a retry
+// with the same idempotency key resumes it.
+func (s *BankService) postTransfer(ctx context.Context, transfer domain.Transfer,
+	transferTrx domain.TransferTransaction) (domain.Transfer, error) {
+	fromEntry := domain.LedgerEntry{
+		TransactionUuid: uuid.New(),
+		AccountUuid:     transfer.FromAccountUuid,
+		Timestamp:       transfer.Timestamp,
+		Amount:          transfer.Conversion.SourceAmount,
+		TransactionType: domain.TransactionTypeOut,
+		Notes:           "Transfer out to " + transferTrx.ToAccountNumber,
+		Conversion:      &transfer.Conversion,
+	}
+
+	toEntry := domain.LedgerEntry{
+		TransactionUuid: uuid.New(),
+		AccountUuid:     transfer.ToAccountUuid,
+		Timestamp:       transfer.Timestamp,
+		Amount:          transfer.Conversion.DestinationAmount,
+		TransactionType: domain.TransactionTypeIn,
+		Notes:           "Transfer in from " + transferTrx.FromAccountNumber,
+		Conversion:      &transfer.Conversion,
 	}
 
-	if transferPairSuccess, _ := s.db.CreateTransferTransactionPair(fromAccountOrm,
-		toAccountOrm, fromTransactionOrm, toTransactionOrm); transferPairSuccess {
-		err := s.db.UpdateTransferStatus(transferOrm, true)
+	fromAccount, toAccount, err := s.db.CreateTransferTransactionPair(ctx, transfer, fromEntry, toEntry)
+
+	if errors.Is(err, domain.ErrInsufficientBalance) {
+		err = s.db.FailTransfer(ctx, transfer.TransferUuid, domain.ErrInsufficientBalance.Error())
+
+		if err == nil {
+			transfer.Status = domain.TransferStatusFailed
+			transfer.FailureReason = domain.ErrInsufficientBalance.Error()
+
+			return transfer, transfer.Err()
+		}
+	}
+
+	if errors.Is(err, domain.ErrTransferNotPending) && transferTrx.IdempotencyKey != "" {
+		// another request with the same key posted or failed it first, its outcome is the one
+		existing, found, err := s.findTransferReplay(ctx, transferTrx, time.Now())
+
+		if err == nil && found && existing.Status != domain.TransferStatusPending {
+			return existing, existing.Err()
+		}
+	}
+
+	if err != nil {
+		s.logger.WarnContext(ctx, "Can't create transfer transaction pair", transferAttrs(transferTrx),
+			slog.Any("error", err))
+		return transfer, fmt.Errorf("%w : %w", domain.ErrTransferTransactionPair, err)
+	}
+
+	s.publishBalance(ctx, fromAccount, fromEntry)
+	s.publishBalance(ctx, toAccount, toEntry)
+
+	transfer.Status = domain.TransferStatusSucceeded
+
+	return transfer, nil
+}
+
+func transferAttrs(transferTrx domain.TransferTransaction) slog.Attr {
+	return slog.Group("transfer",
+		slog.String(logging.FromAccountNumberKey, transferTrx.FromAccountNumber),
+		slog.String(logging.ToAccountNumberKey, transferTrx.ToAccountNumber),
+		slog.String("amount", transferTrx.Amount.String()),
+	)
+}
+
+// WatchBalance subscribes to committed balance changes of an account. The first update carries the
+// current balance and no transaction. The returned function ends the subscription and closes the channel.
+func (s *BankService) WatchBalance(ctx context.Context, accountNumber string) (<-chan domain.BalanceUpdate,
+	func(), error) {
+	bankAccount, err := s.db.GetBankAccountByAccountNumber(ctx, accountNumber)
+
+	if err != nil {
+		return nil, nil, fmt.Errorf("can't find account number %v : %w", accountNumber, err)
+	}
+
+	if err := authorizeAccount(ctx, bankAccount); err != nil {
+		return nil, nil, err
+	}
+
+	return s.balances.Subscribe(accountNumber, func() (domain.BalanceUpdate, error) {
+		current, err := s.db.GetBankAccountByAccountNumber(ctx, accountNumber)
+
 		if err != nil {
-			return uuid.Nil, false, err
+			return domain.BalanceUpdate{}, fmt.Errorf("can't find account number %v : %w", accountNumber, err)
 		}
-		return newTransferUuid, true, nil
+
+		return domain.BalanceUpdate{
+			AccountNumber: current.AccountNumber,
+			Balance:       current.Balance,
+			Version:       current.Version,
+		}, nil
+	})
+}
+
+func (s *BankService) publishBalance(ctx context.Context, acct domain.Account, entry domain.LedgerEntry) {
+	s.balances.Publish(ctx, domain.BalanceUpdate{
+		AccountNumber: acct.AccountNumber,
+		Balance:       acct.Balance,
+		Version:       acct.Version,
+		Transaction:   toTransaction(acct, entry),
+	})
+}
+
+// convertTransferAmount works out how much leaves the source account and arrives at the destination
+// account. The transfer amount must be in one of the two account currencies; the other side is
+// converted with the source to destination rate valid at the given time.
+func (s *BankService) convertTransferAmount(ctx context.Context, sourceCur string, destinationCur string,
+	amount domain.Money, at time.Time) (domain.Money, domain.Money, domain.Rate, error) {
+	if amount.Currency != sourceCur && amount.Currency != destinationCur {
+		return domain.Money{}, domain.Money{}, domain.Rate{}, fmt.Errorf(
+			"%w : transfer currency %v must match source account currency %v or destination account currency %v",
+			domain.ErrCurrencyMismatch, amount.Currency, sourceCur, destinationCur)
+	}
+
+	if sourceCur == destinationCur {
+		return amount, amount, domain.NewRate(1, 0), nil
+	}
+
+	ctx, span := tracer.Start(ctx, "BankService.findConversionRate")
+	rate, err := s.findConversionRate(ctx, sourceCur, destinationCur, at)
+	recordSpanError(span, err)
+	span.End()
+
+	if err != nil {
+		return domain.Money{}, domain.Money{}, domain.Rate{}, err
+	}
+
+	sourceAmount, destinationAmount := amount, amount
+
+	if amount.Currency == sourceCur {
+		destinationAmount, err = amount.Convert(destinationCur, rate, domain.DefaultRoundingMode)
 	} else {
-		return newTransferUuid, false, domain.ErrTransferTransactionPair
+		sourceAmount, err = amount.Convert(sourceCur, rate.Invert(), domain.DefaultRoundingMode)
+	}
+
+	if err != nil {
+		return domain.Money{}, domain.Money{}, domain.Rate{}, err
+	}
+
+	if !sourceAmount.IsPositive() || !destinationAmount.IsPositive() {
+		return domain.Money{}, domain.Money{}, domain.Rate{}, fmt.Errorf(
+			"%w : %v is too small to convert between %v and %v", domain.ErrInvalidAmount, amount, sourceCur, destinationCur)
+	}
+
+	return sourceAmount, destinationAmount, rate, nil
+}
+
+// findConversionRate returns the fromCur to toCur rate valid at the given time, falling back to
+// the inverse of a stored toCur to fromCur rate. The inverse is rounded to the precision rates are
+// stored with, so the rate recorded on a transfer is the one it was converted with. Storage failures
+// are returned as they are.
+func (s *BankService) findConversionRate(ctx context.Context, fromCur string, toCur string,
+	at time.Time) (domain.Rate, error) {
+	rate, err := s.FindExchangeRate(ctx, fromCur, toCur, at)
+
+	if err == nil || !errors.Is(err, domain.ErrExchangeRateNotFound) {
+		return rate, err
 	}
+
+	rate, err = s.FindExchangeRate(ctx, toCur, fromCur, at)
+
+	if err == nil {
+		return rate.Invert().Round(domain.DefaultRoundingMode), nil
+	}
+
+	if !errors.Is(err, domain.ErrExchangeRateNotFound) {
+		return domain.Rate{}, err
+	}
+
+	return domain.Rate{}, fmt.Errorf("%w : %v to %v at %v", domain.ErrExchangeRateNotFound,
+		fromCur, toCur, at.Format(time.RFC3339))
+}
+
+// expiredReplayError reports a key the storage still holds although the request it was used for is
+// older than the retention window, so that request can't be replayed.
+func expiredReplayError(key string) error {
+	return fmt.Errorf("%w : %v, its original request is past the retention window", domain.ErrDuplicateIdempotencyKey,
+		key)
+}
+
+// findTransactionReplay looks up a transaction previously created with the same idempotency key.
+// It fails with domain.ErrIdempotencyKeyReused when the stored transaction doesn't match bankTrx.
+func (s *BankService) findTransactionReplay(ctx context.Context, acct domain.Account,
+	bankTrx domain.Transaction, now time.Time) (uuid.UUID, bool, error) {
+	existing, found, err := s.db.FindTransactionByIdempotencyKey(ctx, bankTrx.IdempotencyKey,
+		now.Add(-s.idempotencyRetention))
+
+	if err != nil {
+		return acct.AccountUuid, false, err
+	}
+
+	if !found {
+		return uuid.Nil, false, nil
+	}
+
+	if existing.AccountUuid != acct.AccountUuid || existing.Amount != bankTrx.Amount ||
+		existing.TransactionType != bankTrx.TransactionType {
+		return acct.AccountUuid, true, fmt.Errorf("%w : %v", domain.ErrIdempotencyKeyReused, bankTrx.IdempotencyKey)
+	}
+
+	return existing.TransactionUuid, true, nil
+}
+
+// findTransferReplay looks up a transfer previously created with the same idempotency key and
+// returns it. It fails with domain.ErrIdempotencyKeyReused on a different request.
+func (s *BankService) findTransferReplay(ctx context.Context, transferTrx domain.TransferTransaction,
+	now time.Time) (domain.Transfer, bool, error) {
+	existing, found, err := s.db.FindTransferByIdempotencyKey(ctx, transferTrx.IdempotencyKey,
+		now.Add(-s.idempotencyRetention))
+
+	if err != nil || !found {
+		return domain.Transfer{}, false, err
+	}
+
+	reusedErr := fmt.Errorf("%w : %v", domain.ErrIdempotencyKeyReused, transferTrx.IdempotencyKey)
+
+	fromAccount, err := s.db.GetBankAccountByAccountNumber(ctx, transferTrx.FromAccountNumber)
+
+	if err != nil && !errors.Is(err, domain.ErrAccountNotFound) {
+		return domain.Transfer{}, false, err
+	}
+
+	if err != nil || existing.FromAccountUuid != fromAccount.AccountUuid {
+		return domain.Transfer{}, true, reusedErr
+	}
+
+	toAccount, err := s.db.GetBankAccountByAccountNumber(ctx, transferTrx.ToAccountNumber)
+
+	if err != nil && !errors.Is(err, domain.ErrAccountNotFound) {
+		return domain.Transfer{}, false, err
+	}
+
+	if err != nil || existing.ToAccountUuid != toAccount.AccountUuid {
+		return domain.Transfer{}, true, reusedErr
+	}
+
+	if existing.Amount != transferTrx.Amount {
+		return domain.Transfer{}, true, reusedErr
+	}
+
+	return existing, true, nil
+}
+
+func encodePageToken(cursor domain.TransactionCursor) string {
+	raw := cursor.Timestamp.UTC().Format(time.RFC3339Nano) + "|" + cursor.TransactionUuid.String()
+
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodePageToken(pageToken string) (domain.TransactionCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(pageToken)
+
+	if err != nil {
+		return domain.TransactionCursor{}, domain.ErrInvalidPageToken
+	}
+
+	timestampStr, uuidStr, ok := strings.Cut(string(raw), "|")
+
+	if !ok {
+		return domain.TransactionCursor{}, domain.ErrInvalidPageToken
+	}
+
+	timestamp, err := time.Parse(time.RFC3339Nano, timestampStr)
+
+	if err != nil {
+		return domain.TransactionCursor{}, domain.ErrInvalidPageToken
+	}
+
+	transactionUuid, err := uuid.Parse(uuidStr)
+
+	if err != nil {
+		return domain.TransactionCursor{}, domain.ErrInvalidPageToken
+	}
+
+	return domain.TransactionCursor{
+		Timestamp:       timestamp,
+		TransactionUuid: transactionUuid,
+	}, nil
 }
@@ -2,7 +2,10 @@ package domain
 
 import (
 	"errors"
+	"fmt"
 	"time"
+
+	"github.com/google/uuid"
 )
 
 const (
@@ -11,37 +14,113 @@ const (
 	TransactionTypeOut     string = "OUT"
 )
 
+const (
+	TransactionResultSuccess    string = "SUCCESS"
+	TransactionResultFailed     string = "FAILED"
+	TransactionResultNotApplied string = "NOT_APPLIED"
+)
+
+const (
+	TransferStatusPending   string = "PENDING"
+	TransferStatusSucceeded string = "SUCCEEDED"
+	TransferStatusFailed    string = "FAILED"
+)
+
+type CurrencyPair struct {
+	FromCurrency string
+	ToCurrency   string
+}
+
 type ExchangeRate struct {
 	FromCurrency       string
 	ToCurrency         string
-	Rate               float64
+	Rate               Rate
 	ValidFromTimestamp time.Time
 	ValidToTimestamp   time.Time
 }
 
 type Transaction struct {
-	Amount          float64
+	TransactionUuid uuid.UUID
+	AccountNumber   string
+	Amount          Money
 	Timestamp       time.Time
 	TransactionType string
 	Notes           string
+	IdempotencyKey  string
+}
+
+// TransactionResult is the outcome of one transaction of a batch. Err is set when Status is
+// TransactionResultFailed.
+type TransactionResult struct {
+	TransactionUuid uuid.UUID
+	Status          string
+	Err             error
 }
 
+// TransactionFilter narrows a transaction listing. Zero values leave a criterion unbounded.
+type TransactionFilter struct {
+	FromTimestamp   time.Time
+	ToTimestamp     time.Time
+	TransactionType string
+	MinAmount       *Money
+	MaxAmount       *Money
+	NotesContains   string
+}
+
+// TransactionCursor is the position of the last transaction on a page, listings are ordered
+// by timestamp then UUID, newest first.
+type TransactionCursor struct {
+	Timestamp       time.Time
+	TransactionUuid uuid.UUID
+}
+
+// TransactionSummary sums up transactions of one currency, which the first transaction sets.
 type TransactionSummary struct {
 	SummaryOnDate time.Time
-	SumIn         float64
-	SumOut        float64
-	SumTotal      float64
+	SumIn         Money
+	SumOut        Money
+	SumTotal      Money
+}
+
+// Accepts checks that amount is in the currency of the transactions summed up so far.
+func (s TransactionSummary) Accepts(amount Money) error {
+	if s.SumIn.Currency != "" && s.SumIn.Currency != amount.Currency {
+		return fmt.Errorf("%w : summary is in %v, transaction is in %v", ErrCurrencyMismatch, s.SumIn.Currency,
+			amount.Currency)
+	}
+
+	return nil
+}
+
+// BalanceUpdate is emitted after a transaction changing an account balance has been committed.
+// Version is the account version the balance was committed with, updates are ordered by it.
+type BalanceUpdate struct {
+	AccountNumber string
+	Balance       Money
+	Version       int64
+	Transaction   Transaction
 }
 
 type TransferTransaction struct {
 	FromAccountNumber string
 	ToAccountNumber   string
-	Currency          string
-	Amount            float64
+	Amount            Money
+	IdempotencyKey    string
 }
 
+var ErrAccountNotFound = errors.New("account not found")
 var ErrTransferSourceAccountNotFound = errors.New("source account not found")
 var ErrTransferDestinationAccountNotFound = errors.New("destination account not found")
 var ErrTransferRecordFailed = errors.New("can't create transfer record")
 var ErrTransferTransactionPair = errors.New("can't create transfer transaction pair, " +
 	"possibly insufficient balance on source account")
+var ErrTransferNotPending = errors.New("transfer is no longer pending")
+var ErrInsufficientBalance = errors.New("insufficient account balance")
+var ErrDuplicateIdempotencyKey = errors.New("idempotency key already used")
+var ErrIdempotencyKeyReused = errors.New("idempotency key was already used for a different request")
+var ErrExchangeRateNotFound = errors.New("no exchange rate valid at transfer time")
+var ErrInvalidPageToken = errors.New("invalid page token")
+var ErrBatchTooLarge = errors.New("too many transactions in one batch")
+var ErrInvalidTransactionType = errors.New("transaction type must be IN or OUT")
+var ErrPermissionDenied = errors.New("permission denied")
+var ErrProductionDatabase = errors.New("database is flagged as production")
@@ -0,0 +1,88 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Account is a bank account as kept in storage. Its balance is in the account currency. Version
+// counts the entries posted to the account, so a later balance always has a higher version.
+type Account struct {
+	AccountUuid   uuid.UUID
+	AccountNumber string
+	AccountName   string
+	OwnerSubject  string
+	Balance       Money
+	Version       int64
+}
+
+func (a Account) Currency() string {
+	return a.Balance.Currency
+}
+
+// AccountFixture is a demo account, opened with InitialDeposit in its currency. A zero deposit
+// opens it empty.
+type AccountFixture struct {
+	AccountUuid    uuid.UUID
+	AccountNumber  string
+	AccountName    string
+	OwnerSubject   string
+	InitialDeposit Money
+}
+
+// LedgerEntry is a transaction posted to an account, its amount is in the account currency.
+// Conversion is set on the two legs of a transfer.
+type LedgerEntry struct {
+	TransactionUuid uuid.UUID
+	AccountUuid     uuid.UUID
+	Timestamp       time.Time
+	Amount          Money
+	TransactionType string
+	Notes           string
+	IdempotencyKey  string
+	Conversion      *Conversion
+}
+
+// Conversion records how much of a transfer left the source account and arrived at the destination
+// account, and the source to destination rate applied.
+type Conversion struct {
+	SourceAmount      Money
+	DestinationAmount Money
+	Rate              Rate
+}
+
+// Transfer moves Amount, which is in the currency of one of the two accounts, between accounts.
+// It is recorded as TransferStatusPending before its ledger entries are posted, and is then either
+// TransferStatusSucceeded once they are, or TransferStatusFailed with the FailureReason the posting
+// was refused for. A transfer whose posting was interrupted stays pending.
+type Transfer struct {
+	TransferUuid    uuid.UUID
+	FromAccountUuid uuid.UUID
+	ToAccountUuid   uuid.UUID
+	Amount          Money
+	Conversion      Conversion
+	Timestamp       time.Time
+	Status          string
+	FailureReason   string
+	IdempotencyKey  string
+}
+
+// transferFailures are the errors a posting can be refused with, recorded by their text.
+var transferFailures = []error{ErrInsufficientBalance}
+
+// Err returns the error a failed transfer was refused with, and nil for any other status.
+func (t Transfer) Err() error {
+	if t.Status != TransferStatusFailed {
+		return nil
+	}
+
+	for _, failure := range transferFailures {
+		if t.FailureReason == failure.Error() {
+			return failure
+		}
+	}
+
+	return fmt.Errorf("%w : %v", ErrTransferTransactionPair, t.FailureReason)
+}
@@ -0,0 +1,345 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+type RoundingMode int
+
+const (
+	RoundHalfEven RoundingMode = iota
+	RoundHalfUp
+	RoundDown
+	RoundUp
+)
+
+// DefaultRoundingMode is applied to FX conversions unless a caller asks otherwise.
+const DefaultRoundingMode = RoundHalfEven
+
+const nanosPerUnit = 1_000_000_000
+
+// currencyExponents holds the ISO 4217 minor unit exponent of each known currency.
+// Currencies not listed here default to 2.
+var currencyExponents = map[string]int32{
+	"BHD": 3,
+	"CLP": 0,
+	"IQD": 3,
+	"JOD": 3,
+	"JPY": 0,
+	"KRW": 0,
+	"KWD": 3,
+	"LYD": 3,
+	"OMR": 3,
+	"TND": 3,
+	"VND": 0,
+}
+
+var ErrInvalidAmount = errors.New("invalid amount")
+var ErrCurrencyMismatch = errors.New("currency mismatch")
+
+func CurrencyExponent(currency string) int32 {
+	if exp, ok := currencyExponents[currency]; ok {
+		return exp
+	}
+
+	return 2
+}
+
+// Money is an exact amount expressed in minor units (e.g. cents) of an ISO 4217 currency.
+type Money struct {
+	Currency   string
+	MinorUnits int64
+}
+
+func NewMoney(currency string, minorUnits int64) Money {
+	return Money{
+		Currency:   currency,
+		MinorUnits: minorUnits,
+	}
+}
+
+func ZeroMoney(currency string) Money {
+	return NewMoney(currency, 0)
+}
+
+// ParseMoney parses a decimal string such as "-12.50". Digits beyond the currency
+// exponent are accepted only when they are zero, so "10.0000" is valid for USD.
+func ParseMoney(currency string, amount string) (Money, error) {
+	exp := CurrencyExponent(currency)
+	s := strings.TrimSpace(amount)
+	negative := false
+
+	if strings.HasPrefix(s, "-") {
+		negative = true
+		s = s[1:]
+	} else if strings.HasPrefix(s, "+") {
+		s = s[1:]
+	}
+
+	intPart, fracPart, _ := strings.Cut(s, ".")
+
+	if intPart == "" && fracPart == "" || !isDigits(intPart) || !isDigits(fracPart) {
+		return Money{}, fmt.Errorf("%w : %q", ErrInvalidAmount, amount)
+	}
+
+	if len(fracPart) > int(exp) {
+		if strings.Trim(fracPart[exp:], "0") != "" {
+			return Money{}, fmt.Errorf("%w : %q has more than %d decimal places for %v",
+				ErrInvalidAmount, amount, exp, currency)
+		}
+
+		fracPart = fracPart[:exp]
+	}
+
+	fracPart += strings.Repeat("0", int(exp)-len(fracPart))
+
+	minor, ok := new(big.Int).SetString("0"+intPart+fracPart, 10)
+
+	if ok && negative {
+		minor.Neg(minor)
+	}
+
+	if !ok || !minor.IsInt64() {
+		return Money{}, fmt.Errorf("%w : %q is out of range", ErrInvalidAmount, amount)
+	}
+
+	return NewMoney(currency, minor.Int64()), nil
+}
+
+// MoneyFromUnitsNanos builds Money from the google.type.Money style representation,
+// rejecting nanos finer than the currency exponent or with a sign differing from units.
+func MoneyFromUnitsNanos(currency string, units int64, nanos int32) (Money, error) {
+	if nanos <= -nanosPerUnit || nanos >= nanosPerUnit ||
+		(units > 0 && nanos < 0) || (units < 0 && nanos > 0) {
+		return Money{}, fmt.Errorf("%w : units %d and nanos %d", ErrInvalidAmount, units, nanos)
+	}
+
+	exp := CurrencyExponent(currency)
+	nanosPerMinor := int32(pow10(9 - exp))
+
+	if nanos%nanosPerMinor != 0 {
+		return Money{}, fmt.Errorf("%w : nanos %d have more than %d decimal places for %v",
+			ErrInvalidAmount, nanos, exp, currency)
+	}
+
+	minor := new(big.Int).Mul(big.NewInt(units), big.NewInt(pow10(exp)))
+	minor.Add(minor, big.NewInt(int64(nanos/nanosPerMinor)))
+
+	if !minor.IsInt64() {
+		return Money{}, fmt.Errorf("%w : units %d is out of range", ErrInvalidAmount, units)
+	}
+
+	return NewMoney(currency, minor.Int64()), nil
+}
+
+func (m Money) UnitsNanos() (int64, int32) {
+	exp := CurrencyExponent(m.Currency)
+	scale := pow10(exp)
+
+	return m.MinorUnits / scale, int32(m.MinorUnits%scale) * int32(pow10(9-exp))
+}
+
+func (m Money) String() string {
+	exp := CurrencyExponent(m.Currency)
+	digits := new(big.Int).Abs(big.NewInt(m.MinorUnits)).String()
+
+	if len(digits) <= int(exp) {
+		digits = strings.Repeat("0", int(exp)-len(digits)+1) + digits
+	}
+
+	sign := ""
+
+	if m.MinorUnits < 0 {
+		sign = "-"
+	}
+
+	if exp == 0 {
+		return sign + digits
+	}
+
+	return sign + digits[:len(digits)-int(exp)] + "." + digits[len(digits)-int(exp):]
+}
+
+func (m Money) IsZero() bool {
+	return m.MinorUnits == 0
+}
+
+func (m Money) IsNegative() bool {
+	return m.MinorUnits < 0
+}
+
+func (m Money) IsPositive() bool {
+	return m.MinorUnits > 0
+}
+
+func (m Money) Neg() Money {
+	return NewMoney(m.Currency, -m.MinorUnits)
+}
+
+func (m Money) Add(other Money) (Money, error) {
+	if m.Currency != other.Currency {
+		return Money{}, fmt.Errorf("%w : %v and %v", ErrCurrencyMismatch, m.Currency, other.Currency)
+	}
+
+	sum := m.MinorUnits + other.MinorUnits
+
+	if (other.MinorUnits > 0 && sum < m.MinorUnits) || (other.MinorUnits < 0 && sum > m.MinorUnits) {
+		return Money{}, fmt.Errorf("%w : overflow adding %v and %v", ErrInvalidAmount, m, other)
+	}
+
+	return NewMoney(m.Currency, sum), nil
+}
+
+func (m Money) Sub(other Money) (Money, error) {
+	return m.Add(other.Neg())
+}
+
+// Cmp returns -1, 0 or +1 depending on whether m is less than, equal to or greater than other.
+func (m Money) Cmp(other Money) (int, error) {
+	if m.Currency != other.Currency {
+		return 0, fmt.Errorf("%w : %v and %v", ErrCurrencyMismatch, m.Currency, other.Currency)
+	}
+
+	switch {
+	case m.MinorUnits < other.MinorUnits:
+		return -1, nil
+	case m.MinorUnits > other.MinorUnits:
+		return 1, nil
+	default:
+		return 0, nil
+	}
+}
+
+// Convert multiplies m by rate into toCurrency, rounding to the target currency's minor unit.
+func (m Money) Convert(toCurrency string, rate Rate, mode RoundingMode) (Money, error) {
+	if rate.value == nil || rate.value.Sign() <= 0 {
+		return Money{}, fmt.Errorf("invalid exchange rate %v", rate)
+	}
+
+	amount := new(big.Rat).SetFrac(big.NewInt(m.MinorUnits), big.NewInt(pow10(CurrencyExponent(m.Currency))))
+	amount.Mul(amount, rate.value)
+	amount.Mul(amount, new(big.Rat).SetInt64(pow10(CurrencyExponent(toCurrency))))
+
+	minor := roundRat(amount, mode)
+
+	if !minor.IsInt64() {
+		return Money{}, fmt.Errorf("%w : converted amount is out of range", ErrInvalidAmount)
+	}
+
+	return NewMoney(toCurrency, minor.Int64()), nil
+}
+
+// Rate is an exact decimal exchange rate.
+type Rate struct {
+	value *big.Rat
+}
+
+// RateScale is the number of decimal places a rate is stored and printed with.
+const RateScale = 10
+
+func NewRate(value int64, scale int32) Rate {
+	return Rate{
+		value: new(big.Rat).SetFrac(big.NewInt(value), big.NewInt(pow10(scale))),
+	}
+}
+
+func ParseRate(rate string) (Rate, error) {
+	value, ok := new(big.Rat).SetString(strings.TrimSpace(rate))
+
+	if !ok || strings.ContainsAny(rate, "/eE") {
+		return Rate{}, fmt.Errorf("invalid exchange rate %q", rate)
+	}
+
+	return Rate{value: value}, nil
+}
+
+func (r Rate) String() string {
+	if r.value == nil {
+		return new(big.Rat).FloatString(RateScale)
+	}
+
+	return r.value.FloatString(RateScale)
+}
+
+func (r Rate) Invert() Rate {
+	if r.value == nil || r.value.Sign() == 0 {
+		return r
+	}
+
+	return Rate{value: new(big.Rat).Inv(r.value)}
+}
+
+// Round returns r rounded to the RateScale decimal places rates are stored with.
+func (r Rate) Round(mode RoundingMode) Rate {
+	if r.value == nil {
+		return r
+	}
+
+	scale := big.NewInt(pow10(RateScale))
+	scaled := roundRat(new(big.Rat).Mul(r.value, new(big.Rat).SetInt(scale)), mode)
+
+	return Rate{value: new(big.Rat).SetFrac(scaled, scale)}
+}
+
+func (r Rate) Equal(other Rate) bool {
+	if r.value == nil || other.value == nil {
+		return r.value == other.value
+	}
+
+	return r.value.Cmp(other.value) == 0
+}
+
+func roundRat(x *big.Rat, mode RoundingMode) *big.Int {
+	quo, rem := new(big.Int).QuoRem(x.Num(), x.Denom(), new(big.Int))
+
+	if rem.Sign() == 0 {
+		return quo
+	}
+
+	away := big.NewInt(int64(x.Sign()))
+
+	switch mode {
+	case RoundDown:
+		return quo
+	case RoundUp:
+		return quo.Add(quo, away)
+	}
+
+	// compare 2*|rem| against the denominator to find which side of the half we are on
+	twiceRem := new(big.Int).Abs(rem)
+	twiceRem.Lsh(twiceRem, 1)
+
+	switch twiceRem.Cmp(x.Denom()) {
+	case 1:
+		return quo.Add(quo, away)
+	case 0:
+		if mode == RoundHalfUp || quo.Bit(0) == 1 {
+			return quo.Add(quo, away)
+		}
+	}
+
+	return quo
+}
+
+func isDigits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+
+	return true
+}
+
+func pow10(exp int32) int64 {
+	result := int64(1)
+
+	for i := int32(0); i < exp; i++ {
+		result *= 10
+	}
+
+	return result
+}
@@ -0,0 +1,277 @@
+package domain
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"testing"
+)
+
+func TestParseMoney(t *testing.T) {
+	tests := []struct {
+		currency string
+		amount   string
+		want     int64
+		wantErr  bool
+	}{
+		{"USD", "12.50", 1250, false},
+		{"USD", "-12.50", -1250, false},
+		{"USD", "+12.5", 1250, false},
+		{"USD", " 7 ", 700, false},
+		{"USD", ".5", 50, false},
+		{"USD", "3.", 300, false},
+		{"USD", "-0", 0, false},
+		{"USD", "10.0000", 1000, false},
+		{"USD", "10.001", 0, true},
+		{"JPY", "1500", 1500, false},
+		{"JPY", "-1500", -1500, false},
+		{"JPY", "1500.0", 1500, false},
+		{"JPY", "1500.5", 0, true},
+		{"KWD", "1.005", 1005, false},
+		{"KWD", "-0.005", -5, false},
+		{"KWD", "0.0051", 0, true},
+		{"USD", "92233720368547758.07", math.MaxInt64, false},
+		{"USD", "92233720368547758.08", 0, true},
+		{"USD", "-92233720368547758.08", math.MinInt64, false},
+		{"USD", "-92233720368547758.09", 0, true},
+		{"JPY", "9223372036854775808", 0, true},
+		{"USD", "", 0, true},
+		{"USD", "-", 0, true},
+		{"USD", ".", 0, true},
+		{"USD", "+-1", 0, true},
+		{"USD", "1e3", 0, true},
+		{"USD", "1,000", 0, true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseMoney(tt.currency, tt.amount)
+
+		if tt.wantErr {
+			if !errors.Is(err, ErrInvalidAmount) {
+				t.Errorf("ParseMoney(%v, %q) = %v, %v, want %v", tt.currency, tt.amount, got, err, ErrInvalidAmount)
+			}
+
+			continue
+		}
+
+		if want := NewMoney(tt.currency, tt.want); err != nil || got != want {
+			t.Errorf("ParseMoney(%v, %q) = %v, %v, want %v", tt.currency, tt.amount, got, err, want)
+		}
+	}
+}
+
+func TestMoneyFromUnitsNanos(t *testing.T) {
+	tests := []struct {
+		currency string
+		units    int64
+		nanos    int32
+		want     int64
+		wantErr  bool
+	}{
+		{"USD", 12, 500_000_000, 1250, false},
+		{"USD", -12, -500_000_000, -1250, false},
+		{"USD", 0, -500_000_000, -50, false},
+		{"USD", 12, -500_000_000, 0, true},
+		{"USD", -12, 500_000_000, 0, true},
+		{"USD", 0, 1_000_000_000, 0, true},
+		{"USD", 0, -1_000_000_000, 0, true},
+		{"USD", 0, 5_000_000, 0, true},
+		{"JPY", 1500, 0, 1500, false},
+		{"JPY", -1500, 0, -1500, false},
+		{"JPY", 1500, 500_000_000, 0, true},
+		{"KWD", 1, 5_000_000, 1005, false},
+		{"KWD", -1, -5_000_000, -1005, false},
+		{"KWD", 1, 500_000, 0, true},
+		{"USD", 92233720368547758, 70_000_000, math.MaxInt64, false},
+		{"USD", 92233720368547758, 80_000_000, 0, true},
+		{"USD", -92233720368547758, -80_000_000, math.MinInt64, false},
+		{"USD", math.MaxInt64, 0, 0, true},
+		{"JPY", math.MaxInt64, 0, math.MaxInt64, false},
+		{"KWD", math.MinInt64 / 100, 0, 0, true},
+	}
+
+	for _, tt := range tests {
+		got, err := MoneyFromUnitsNanos(tt.currency, tt.units, tt.nanos)
+
+		if tt.wantErr {
+			if !errors.Is(err, ErrInvalidAmount) {
+				t.Errorf("MoneyFromUnitsNanos(%v, %d, %d) = %v, %v, want %v", tt.currency, tt.units, tt.nanos,
+					got, err, ErrInvalidAmount)
+			}
+
+			continue
+		}
+
+		if want := NewMoney(tt.currency, tt.want); err != nil || got != want {
+			t.Errorf("MoneyFromUnitsNanos(%v, %d, %d) = %v, %v, want %v", tt.currency, tt.units, tt.nanos,
+				got, err, want)
+		}
+	}
+}
+
+func TestMoneyUnitsNanosAndString(t *testing.T) {
+	tests := []struct {
+		money  Money
+		units  int64
+		nanos  int32
+		string string
+	}{
+		{NewMoney("USD", 1250), 12, 500_000_000, "12.50"},
+		{NewMoney("USD", -1250), -12, -500_000_000, "-12.50"},
+		{NewMoney("USD", 5), 0, 50_000_000, "0.05"},
+		{NewMoney("USD", -5), 0, -50_000_000, "-0.05"},
+		{NewMoney("USD", 0), 0, 0, "0.00"},
+		{NewMoney("JPY", 1500), 1500, 0, "1500"},
+		{NewMoney("JPY", -7), -7, 0, "-7"},
+		{NewMoney("JPY", 0), 0, 0, "0"},
+		{NewMoney("KWD", 1005), 1, 5_000_000, "1.005"},
+		{NewMoney("KWD", -5), 0, -5_000_000, "-0.005"},
+		{NewMoney("USD", math.MaxInt64), 92233720368547758, 70_000_000, "92233720368547758.07"},
+		{NewMoney("USD", math.MinInt64), -92233720368547758, -80_000_000, "-92233720368547758.08"},
+		{NewMoney("JPY", math.MinInt64), math.MinInt64, 0, "-9223372036854775808"},
+	}
+
+	for _, tt := range tests {
+		units, nanos := tt.money.UnitsNanos()
+
+		if units != tt.units || nanos != tt.nanos {
+			t.Errorf("%+v.UnitsNanos() = %d, %d, want %d, %d", tt.money, units, nanos, tt.units, tt.nanos)
+		}
+
+		if got := tt.money.String(); got != tt.string {
+			t.Errorf("%+v.String() = %q, want %q", tt.money, got, tt.string)
+		}
+
+		// every amount survives the round trip through both representations
+		if parsed, err := ParseMoney(tt.money.Currency, tt.string); err != nil || parsed != tt.money {
+			t.Errorf("ParseMoney(%v, %q) = %v, %v, want %v", tt.money.Currency, tt.string, parsed, err, tt.money)
+		}
+
+		if built, err := MoneyFromUnitsNanos(tt.money.Currency, units, nanos); err != nil || built != tt.money {
+			t.Errorf("MoneyFromUnitsNanos(%v, %d, %d) = %v, %v, want %v", tt.money.Currency, units, nanos,
+				built, err, tt.money)
+		}
+	}
+}
+
+func TestMoneyConvert(t *testing.T) {
+	tests := []struct {
+		name       string
+		money      Money
+		toCurrency string
+		rate       Rate
+		want       map[RoundingMode]int64
+	}{
+		{"half to an exponent of 0", NewMoney("USD", 100), "JPY", NewRate(1505, 1),
+			map[RoundingMode]int64{RoundHalfEven: 150, RoundHalfUp: 151, RoundDown: 150, RoundUp: 151}},
+		{"half to an odd amount", NewMoney("USD", 100), "JPY", NewRate(1515, 1),
+			map[RoundingMode]int64{RoundHalfEven: 152, RoundHalfUp: 152, RoundDown: 151, RoundUp: 152}},
+		{"negative half", NewMoney("USD", -100), "JPY", NewRate(1505, 1),
+			map[RoundingMode]int64{RoundHalfEven: -150, RoundHalfUp: -151, RoundDown: -150, RoundUp: -151}},
+		{"half to an exponent of 3", NewMoney("USD", 1000), "KWD", NewRate(30705, 5),
+			map[RoundingMode]int64{RoundHalfEven: 3070, RoundHalfUp: 3071, RoundDown: 3070, RoundUp: 3071}},
+		{"from an exponent of 3", NewMoney("KWD", 1), "USD", NewRate(325, 2),
+			map[RoundingMode]int64{RoundHalfEven: 0, RoundHalfUp: 0, RoundDown: 0, RoundUp: 1}},
+		{"below half", NewMoney("JPY", 1), "USD", NewRate(624, 5),
+			map[RoundingMode]int64{RoundHalfEven: 1, RoundHalfUp: 1, RoundDown: 0, RoundUp: 1}},
+		{"negative below half", NewMoney("JPY", -1000), "USD", NewRate(6241, 6),
+			map[RoundingMode]int64{RoundHalfEven: -624, RoundHalfUp: -624, RoundDown: -624, RoundUp: -625}},
+		{"exact", NewMoney("USD", 1250), "EUR", NewRate(2, 0),
+			map[RoundingMode]int64{RoundHalfEven: 2500, RoundHalfUp: 2500, RoundDown: 2500, RoundUp: 2500}},
+	}
+
+	for _, tt := range tests {
+		for mode, minorUnits := range tt.want {
+			got, err := tt.money.Convert(tt.toCurrency, tt.rate, mode)
+
+			if want := NewMoney(tt.toCurrency, minorUnits); err != nil || got != want {
+				t.Errorf("%v : Convert(%v, %v, %d) of %v = %v, %v, want %v", tt.name, tt.toCurrency, tt.rate, mode,
+					tt.money, got, err, want)
+			}
+		}
+	}
+}
+
+func TestMoneyConvertFailures(t *testing.T) {
+	tests := []struct {
+		name  string
+		money Money
+		rate  Rate
+		isErr error
+	}{
+		{"overflow", NewMoney("USD", math.MaxInt64), NewRate(2, 0), ErrInvalidAmount},
+		{"negative overflow", NewMoney("USD", math.MinInt64), NewRate(15, 1), ErrInvalidAmount},
+		{"zero rate", NewMoney("USD", 100), NewRate(0, 0), nil},
+		{"negative rate", NewMoney("USD", 100), NewRate(-1, 0), nil},
+		{"missing rate", NewMoney("USD", 100), Rate{}, nil},
+	}
+
+	for _, tt := range tests {
+		got, err := tt.money.Convert("USD", tt.rate, DefaultRoundingMode)
+
+		if err == nil || (tt.isErr != nil && !errors.Is(err, tt.isErr)) {
+			t.Errorf("%v : Convert of %v at %v = %v, %v, want an error", tt.name, tt.money, tt.rate, got, err)
+		}
+	}
+}
+
+func TestRoundRat(t *testing.T) {
+	tests := []struct {
+		num, denom int64
+		want       map[RoundingMode]int64
+	}{
+		{5, 2, map[RoundingMode]int64{RoundHalfEven: 2, RoundHalfUp: 3, RoundDown: 2, RoundUp: 3}},
+		{7, 2, map[RoundingMode]int64{RoundHalfEven: 4, RoundHalfUp: 4, RoundDown: 3, RoundUp: 4}},
+		{-5, 2, map[RoundingMode]int64{RoundHalfEven: -2, RoundHalfUp: -3, RoundDown: -2, RoundUp: -3}},
+		{-7, 2, map[RoundingMode]int64{RoundHalfEven: -4, RoundHalfUp: -4, RoundDown: -3, RoundUp: -4}},
+		{26, 10, map[RoundingMode]int64{RoundHalfEven: 3, RoundHalfUp: 3, RoundDown: 2, RoundUp: 3}},
+		{24, 10, map[RoundingMode]int64{RoundHalfEven: 2, RoundHalfUp: 2, RoundDown: 2, RoundUp: 3}},
+		{-26, 10, map[RoundingMode]int64{RoundHalfEven: -3, RoundHalfUp: -3, RoundDown: -2, RoundUp: -3}},
+		{-24, 10, map[RoundingMode]int64{RoundHalfEven: -2, RoundHalfUp: -2, RoundDown: -2, RoundUp: -3}},
+		{1, 3, map[RoundingMode]int64{RoundHalfEven: 0, RoundHalfUp: 0, RoundDown: 0, RoundUp: 1}},
+		{1, 2, map[RoundingMode]int64{RoundHalfEven: 0, RoundHalfUp: 1, RoundDown: 0, RoundUp: 1}},
+		{-1, 2, map[RoundingMode]int64{RoundHalfEven: 0, RoundHalfUp: -1, RoundDown: 0, RoundUp: -1}},
+		{8, 2, map[RoundingMode]int64{RoundHalfEven: 4, RoundHalfUp: 4, RoundDown: 4, RoundUp: 4}},
+		{0, 5, map[RoundingMode]int64{RoundHalfEven: 0, RoundHalfUp: 0, RoundDown: 0, RoundUp: 0}},
+	}
+
+	for _, tt := range tests {
+		for mode, want := range tt.want {
+			x := big.NewRat(tt.num, tt.denom)
+
+			if got := roundRat(x, mode); got.Cmp(big.NewInt(want)) != 0 {
+				t.Errorf("roundRat(%v, %d) = %v, want %d", x, mode, got, want)
+			}
+		}
+	}
+}
+
+func TestRateRound(t *testing.T) {
+	tests := []struct {
+		rate Rate
+		mode RoundingMode
+		want string
+	}{
+		{NewRate(3, 0).Invert(), RoundHalfEven, "0.3333333333"},
+		{NewRate(3, 0).Invert(), RoundUp, "0.3333333334"},
+		{NewRate(64515, 9).Invert(), RoundHalfEven, "15500.2712547470"},
+		{NewRate(15500, 0).Invert(), RoundHalfEven, "0.0000645161"},
+		{NewRate(15, 11), RoundHalfEven, "0.0000000002"},
+		{NewRate(25, 11), RoundHalfEven, "0.0000000002"},
+		{NewRate(25, 11), RoundHalfUp, "0.0000000003"},
+		{NewRate(1550025, 2), RoundHalfEven, "15500.2500000000"},
+	}
+
+	for _, tt := range tests {
+		got := tt.rate.Round(tt.mode)
+
+		if got.String() != tt.want {
+			t.Errorf("%v.Round(%d) = %v, want %v", tt.rate.value, tt.mode, got, tt.want)
+		}
+
+		// the rounded rate survives being stored with RateScale decimals
+		if parsed, err := ParseRate(got.String()); err != nil || !parsed.Equal(got) {
+			t.Errorf("ParseRate(%v) = %v, %v, want it equal to %v", got, parsed.value, err, got.value)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// Permission allows a principal to call a group of bank operations.
+type Permission string
+
+const (
+	// PermissionAccountsRead reads the balance and transactions of owned accounts.
+	PermissionAccountsRead Permission = "accounts.read"
+	// PermissionTransactionsCreate posts transactions on owned accounts.
+	PermissionTransactionsCreate Permission = "transactions.create"
+	// PermissionTransfersCreate transfers money out of owned accounts.
+	PermissionTransfersCreate Permission = "transfers.create"
+	// PermissionAccountsAny extends the other account permissions to accounts owned by anyone.
+	PermissionAccountsAny         Permission = "accounts.any"
+	PermissionExchangeRatesRead   Permission = "exchange_rates.read"
+	PermissionExchangeRatesCreate Permission = "exchange_rates.create"
+)
+
+var Permissions = []Permission{
+	PermissionAccountsRead,
+	PermissionTransactionsCreate,
+	PermissionTransfersCreate,
+	PermissionAccountsAny,
+	PermissionExchangeRatesRead,
+	PermissionExchangeRatesCreate,
+}
+
+// Principal is the authenticated caller of the bank service.
+type Principal struct {
+	// Subject identifies the caller, it is matched against the owner of an account.
+	Subject string
+	// Roles and Permissions are resolved from the role bindings once the caller is authorized.
+	Roles       []string
+	Permissions map[Permission]bool
+}
+
+func (p Principal) Can(permission Permission) bool {
+	return p.Permissions[permission]
+}
+
+// AuditEvent records an operation denied to a principal.
+type AuditEvent struct {
+	Timestamp  time.Time
+	Subject    string
+	Roles      []string
+	Operation  string
+	Permission Permission
+	Resource   string
+	Reason     string
+}
+
+type principalKey struct{}
+
+func ContextWithPrincipal(ctx context.Context, principal Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// PrincipalFromContext returns the caller of ctx. Calls without a principal come from inside the
+// server, e.g. the rate generator, or from a server running without authentication.
+func PrincipalFromContext(ctx context.Context) (Principal, bool) {
+	principal, ok := ctx.Value(principalKey{}).(Principal)
+	return principal, ok
+}
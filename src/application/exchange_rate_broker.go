@@ -0,0 +1,84 @@
+package application
+
+import (
+	"grpcbank/src/application/domain"
+	"log/slog"
+	"sync"
+)
+
+const exchangeRateSubscriptionBuffer = 64
+
+type exchangeRateSubscription struct {
+	rates chan domain.ExchangeRate
+	// lagged is signalled when the subscriber missed a rate, which it then has to look up in storage
+	lagged  chan struct{}
+	lagging bool
+}
+
+// ExchangeRateBroker fans newly created exchange rates out to in-process subscribers.
+type ExchangeRateBroker struct {
+	mu            sync.Mutex
+	subscriptions map[*exchangeRateSubscription]struct{}
+	logger        *slog.Logger
+}
+
+func NewExchangeRateBroker(logger *slog.Logger) *ExchangeRateBroker {
+	return &ExchangeRateBroker{
+		subscriptions: make(map[*exchangeRateSubscription]struct{}),
+		logger:        logger,
+	}
+}
+
+// Subscribe returns a channel of every created rate, a channel signalled when the subscriber missed
+// rates because it fell behind, and a function ending the subscription.
+func (b *ExchangeRateBroker) Subscribe() (<-chan domain.ExchangeRate, <-chan struct{}, func()) {
+	sub := &exchangeRateSubscription{
+		rates:  make(chan domain.ExchangeRate, exchangeRateSubscriptionBuffer),
+		lagged: make(chan struct{}, 1),
+	}
+
+	b.mu.Lock()
+	b.subscriptions[sub] = struct{}{}
+	b.mu.Unlock()
+
+	var once sync.Once
+
+	unsubscribe := func() {
+		once.Do(func() {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+
+			delete(b.subscriptions, sub)
+			close(sub.rates)
+		})
+	}
+
+	return sub.rates, sub.lagged, unsubscribe
+}
+
+// Publish never blocks. A subscriber whose buffer is full misses the rate and is marked lagging
+// until it has room again.
+func (b *ExchangeRateBroker) Publish(rate domain.ExchangeRate) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for sub := range b.subscriptions {
+		select {
+		case sub.rates <- rate:
+			sub.lagging = false
+			continue
+		default:
+		}
+
+		select {
+		case sub.lagged <- struct{}{}:
+		default:
+		}
+
+		if !sub.lagging {
+			sub.lagging = true
+			b.logger.Warn("Exchange rate subscriber is lagging, it will resync from storage",
+				slog.String("from_currency", rate.FromCurrency), slog.String("to_currency", rate.ToCurrency))
+		}
+	}
+}
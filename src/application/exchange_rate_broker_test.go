@@ -0,0 +1,56 @@
+package application_test
+
+import (
+	"grpcbank/src/application"
+	"grpcbank/src/application/domain"
+	"io"
+	"log/slog"
+	"testing"
+)
+
+func TestExchangeRateBrokerMarksLaggingSubscribers(t *testing.T) {
+	broker := application.NewExchangeRateBroker(slog.New(slog.NewTextHandler(io.Discard, nil)))
+	rates, lagged, unsubscribe := broker.Subscribe()
+	defer unsubscribe()
+
+	rate := domain.ExchangeRate{FromCurrency: "USD", ToCurrency: "IDR", Rate: domain.NewRate(15000, 0)}
+
+	for range 64 {
+		broker.Publish(rate)
+	}
+
+	select {
+	case <-lagged:
+		t.Fatalf("subscriber lagging with room for every rate")
+	default:
+	}
+
+	broker.Publish(rate)
+	broker.Publish(rate)
+
+	select {
+	case <-lagged:
+	default:
+		t.Fatalf("subscriber not lagging after missing rates")
+	}
+
+	if len(rates) != 64 {
+		t.Errorf("queued rates = %d, want 64", len(rates))
+	}
+
+	for len(rates) > 0 {
+		<-rates
+	}
+
+	broker.Publish(rate)
+
+	if len(rates) != 1 {
+		t.Errorf("queued rates after catching up = %d, want 1", len(rates))
+	}
+
+	select {
+	case <-lagged:
+		t.Errorf("subscriber lagging again after catching up")
+	default:
+	}
+}
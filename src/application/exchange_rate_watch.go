@@ -0,0 +1,243 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"grpcbank/src/application/domain"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// WatchExchangeRates streams the effective rate of each currency pair. The current rates are sent
+// first, after that a rate is sent only when the effective rate of its pair changes, either because
+// a new rate covering the present was created or because a validity window started or ended.
+// A slow reader gets only the latest change of each pair, and a watch that missed created rates
+// looks the pairs up in storage again. The returned function stops the watch and closes the channel.
+func (s *BankService) WatchExchangeRates(ctx context.Context, pairs []domain.CurrencyPair) (<-chan domain.ExchangeRate,
+	func(), error) {
+	created, lagged, unsubscribe := s.exchangeRates.Subscribe()
+	now := time.Now()
+	current := make(map[domain.CurrencyPair]domain.ExchangeRate, len(pairs))
+
+	for _, pair := range pairs {
+		rate, err := s.findEffectiveExchangeRate(ctx, pair, now)
+
+		if err != nil {
+			unsubscribe()
+			return nil, nil, fmt.Errorf("can't find exchange rate of %v to %v : %w", pair.FromCurrency,
+				pair.ToCurrency, err)
+		}
+
+		current[pair] = rate
+	}
+
+	watch := &exchangeRateWatch{
+		ctx:        ctx,
+		service:    s,
+		created:    created,
+		lagged:     lagged,
+		out:        make(chan domain.ExchangeRate, len(pairs)),
+		done:       make(chan struct{}),
+		current:    current,
+		pending:    make(map[domain.CurrencyPair]domain.ExchangeRate, len(pairs)),
+		boundaries: make(map[domain.CurrencyPair][]time.Time, len(pairs)),
+	}
+
+	go watch.run(pairs)
+
+	var once sync.Once
+
+	stop := func() {
+		once.Do(func() {
+			close(watch.done)
+			unsubscribe()
+		})
+	}
+
+	return watch.out, stop, nil
+}
+
+func (s *BankService) findEffectiveExchangeRate(ctx context.Context, pair domain.CurrencyPair,
+	at time.Time) (domain.ExchangeRate, error) {
+	return s.db.GetExchangeRateAtTimestamp(ctx, pair.FromCurrency, pair.ToCurrency, at)
+}
+
+type exchangeRateWatch struct {
+	// ctx is the context of the watching call, its request ID tags the logs of the watch
+	ctx     context.Context
+	service *BankService
+	created <-chan domain.ExchangeRate
+	lagged  <-chan struct{}
+	out     chan domain.ExchangeRate
+	done    chan struct{}
+	current map[domain.CurrencyPair]domain.ExchangeRate
+	// pending holds the rates not sent yet, the latest one of each pair, sent in the order of queued
+	pending map[domain.CurrencyPair]domain.ExchangeRate
+	queued  []domain.CurrencyPair
+	// boundaries holds the moments at which the effective rate of a pair may change
+	boundaries map[domain.CurrencyPair][]time.Time
+}
+
+func (w *exchangeRateWatch) run(pairs []domain.CurrencyPair) {
+	defer close(w.out)
+
+	now := time.Now()
+
+	for _, pair := range pairs {
+		w.addBoundaries(pair, w.current[pair], now)
+		w.queue(w.current[pair])
+	}
+
+	timer := time.NewTimer(time.Until(w.nextBoundary()))
+	defer timer.Stop()
+
+	for {
+		// sending is enabled only while a rate is pending, so a slow reader doesn't hold up the watch
+		var out chan domain.ExchangeRate
+		var next domain.ExchangeRate
+
+		if len(w.queued) > 0 {
+			out = w.out
+			next = w.pending[w.queued[0]]
+		}
+
+		select {
+		case <-w.done:
+			return
+		case out <- next:
+			delete(w.pending, w.queued[0])
+			w.queued = w.queued[1:]
+		case <-w.lagged:
+			w.service.logger.WarnContext(w.ctx, "Exchange rate watch missed created rates, resyncing")
+
+			now := time.Now()
+
+			for _, pair := range pairs {
+				w.refresh(pair, now)
+			}
+		case rate, ok := <-w.created:
+			if !ok {
+				return
+			}
+
+			pair := domain.CurrencyPair{FromCurrency: rate.FromCurrency, ToCurrency: rate.ToCurrency}
+
+			if _, watched := w.current[pair]; !watched {
+				continue
+			}
+
+			now := time.Now()
+
+			if rate.ValidFromTimestamp.After(now) {
+				w.addBoundary(pair, rate.ValidFromTimestamp)
+			} else if !rate.ValidToTimestamp.Before(now) {
+				w.refresh(pair, now)
+			}
+		case <-timer.C:
+			now := time.Now()
+
+			for pair, boundaries := range w.boundaries {
+				remaining := boundaries[:0]
+				due := false
+
+				for _, boundary := range boundaries {
+					if boundary.After(now) {
+						remaining = append(remaining, boundary)
+					} else {
+						due = true
+					}
+				}
+
+				w.boundaries[pair] = remaining
+
+				if due {
+					w.refresh(pair, now)
+				}
+			}
+		}
+
+		if !timer.Stop() {
+			select {
+			case <-timer.C:
+			default:
+			}
+		}
+
+		timer.Reset(time.Until(w.nextBoundary()))
+	}
+}
+
+// refresh looks up the effective rate of pair and queues it when it differs from the last one queued.
+func (w *exchangeRateWatch) refresh(pair domain.CurrencyPair, now time.Time) {
+	rate, err := w.service.findEffectiveExchangeRate(w.ctx, pair, now)
+
+	if err != nil {
+		w.service.logger.WarnContext(w.ctx, "No exchange rate", slog.String("from_currency", pair.FromCurrency),
+			slog.String("to_currency", pair.ToCurrency), slog.Time("at", now), slog.Any("error", err))
+		return
+	}
+
+	w.addBoundaries(pair, rate, now)
+
+	previous := w.current[pair]
+	w.current[pair] = rate
+
+	if !previous.Rate.Equal(rate.Rate) {
+		w.queue(rate)
+	}
+}
+
+// queue replaces the rate of the pair waiting to be sent, if any.
+func (w *exchangeRateWatch) queue(rate domain.ExchangeRate) {
+	pair := domain.CurrencyPair{FromCurrency: rate.FromCurrency, ToCurrency: rate.ToCurrency}
+
+	if _, queued := w.pending[pair]; !queued {
+		w.queued = append(w.queued, pair)
+	}
+
+	w.pending[pair] = rate
+}
+
+// addBoundaries adds the end of the effective rate and the next window start stored for pair.
+func (w *exchangeRateWatch) addBoundaries(pair domain.CurrencyPair, effective domain.ExchangeRate, now time.Time) {
+	w.addBoundary(pair, effective.ValidToTimestamp.Add(time.Millisecond))
+
+	next, ok, err := w.service.db.FindNextExchangeRateStart(w.ctx, pair.FromCurrency, pair.ToCurrency, now)
+
+	if err != nil {
+		w.service.logger.WarnContext(w.ctx, "Can't find the next exchange rate window",
+			slog.String("from_currency", pair.FromCurrency), slog.String("to_currency", pair.ToCurrency),
+			slog.Any("error", err))
+		return
+	}
+
+	if ok {
+		w.addBoundary(pair, next)
+	}
+}
+
+func (w *exchangeRateWatch) addBoundary(pair domain.CurrencyPair, boundary time.Time) {
+	for _, existing := range w.boundaries[pair] {
+		if existing.Equal(boundary) {
+			return
+		}
+	}
+
+	w.boundaries[pair] = append(w.boundaries[pair], boundary)
+}
+
+// nextBoundary returns the earliest pending boundary, or a day from now when there is none.
+func (w *exchangeRateWatch) nextBoundary() time.Time {
+	next := time.Now().Add(24 * time.Hour)
+
+	for _, boundaries := range w.boundaries {
+		for _, boundary := range boundaries {
+			if boundary.Before(next) {
+				next = boundary
+			}
+		}
+	}
+
+	return next
+}
@@ -0,0 +1,194 @@
+package application_test
+
+import (
+	"context"
+	"grpcbank/src/adapter/memory"
+	"grpcbank/src/application"
+	"grpcbank/src/application/domain"
+	"grpcbank/src/port"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+)
+
+var usdToIdr = domain.CurrencyPair{FromCurrency: "USD", ToCurrency: "IDR"}
+
+func usdToIdrRate(rate int64, validFrom time.Time, validTo time.Time) domain.ExchangeRate {
+	return domain.ExchangeRate{
+		FromCurrency:       "USD",
+		ToCurrency:         "IDR",
+		Rate:               domain.NewRate(rate, 0),
+		ValidFromTimestamp: validFrom,
+		ValidToTimestamp:   validTo,
+	}
+}
+
+// heldStorage holds the watch in its next lookup of the next window start, answering with what
+// storage held before it was released.
+type heldStorage struct {
+	port.BankDatabasePort
+	mu      sync.Mutex
+	armed   bool
+	entered chan struct{}
+	release chan struct{}
+}
+
+func (s *heldStorage) hold() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.armed = true
+	s.entered = make(chan struct{})
+	s.release = make(chan struct{})
+}
+
+func (s *heldStorage) FindNextExchangeRateStart(ctx context.Context, fromCur string, toCur string,
+	after time.Time) (time.Time, bool, error) {
+	next, ok, err := s.BankDatabasePort.FindNextExchangeRateStart(ctx, fromCur, toCur, after)
+
+	s.mu.Lock()
+	armed := s.armed
+	s.armed = false
+	s.mu.Unlock()
+
+	if armed {
+		close(s.entered)
+		<-s.release
+	}
+
+	return next, ok, err
+}
+
+func newRateWatchingBank(t *testing.T, current domain.ExchangeRate) (*application.BankService, *heldStorage) {
+	t.Helper()
+
+	db := &heldStorage{BankDatabasePort: memory.NewMemoryAdapter()}
+
+	if _, err := db.CreateExchangeRate(context.Background(), current); err != nil {
+		t.Fatalf("CreateExchangeRate : %v", err)
+	}
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	service := application.NewBankService(db, application.NewBalanceBroker(logger),
+		application.NewExchangeRateBroker(logger), time.Hour, logger)
+
+	return service, db
+}
+
+func watchRates(t *testing.T, service *application.BankService) <-chan domain.ExchangeRate {
+	t.Helper()
+
+	rates, stop, err := service.WatchExchangeRates(context.Background(), []domain.CurrencyPair{usdToIdr})
+
+	if err != nil {
+		t.Fatalf("WatchExchangeRates : %v", err)
+	}
+
+	t.Cleanup(stop)
+
+	return rates
+}
+
+// expectRate reads rates until one carries want, failing after a few seconds.
+func expectRate(t *testing.T, rates <-chan domain.ExchangeRate, want int64) int {
+	t.Helper()
+
+	timeout := time.After(5 * time.Second)
+
+	for received := 1; ; received++ {
+		select {
+		case rate := <-rates:
+			if rate.Rate.Equal(domain.NewRate(want, 0)) {
+				return received
+			}
+		case <-timeout:
+			t.Fatalf("rate %d not received", want)
+		}
+	}
+}
+
+func TestWatchExchangeRatesSendsChanges(t *testing.T) {
+	now := time.Now()
+	service, _ := newRateWatchingBank(t, usdToIdrRate(15000, now.Add(-time.Hour), now.Add(time.Hour)))
+	rates := watchRates(t, service)
+
+	expectRate(t, rates, 15000)
+
+	_, err := service.CreateExchangeRate(context.Background(), usdToIdrRate(15100, now, now.Add(time.Hour)))
+
+	if err != nil {
+		t.Fatalf("CreateExchangeRate : %v", err)
+	}
+
+	expectRate(t, rates, 15100)
+
+	// a window starting later is sent once it starts
+	_, err = service.CreateExchangeRate(context.Background(),
+		usdToIdrRate(15200, time.Now().Add(200*time.Millisecond), now.Add(time.Hour)))
+
+	if err != nil {
+		t.Fatalf("CreateExchangeRate : %v", err)
+	}
+
+	expectRate(t, rates, 15200)
+}
+
+func TestWatchExchangeRatesSendsOnlyTheLatestRateToSlowReaders(t *testing.T) {
+	now := time.Now()
+	service, _ := newRateWatchingBank(t, usdToIdrRate(15000, now.Add(-time.Hour), now.Add(time.Hour)))
+	rates := watchRates(t, service)
+
+	// nothing is read while more rates are created than a subscription buffers
+	for i := range int64(100) {
+		rate := usdToIdrRate(15001+i, now.Add(time.Duration(i)*time.Microsecond), now.Add(time.Hour))
+
+		if _, err := service.CreateExchangeRate(context.Background(), rate); err != nil {
+			t.Fatalf("CreateExchangeRate : %v", err)
+		}
+	}
+
+	if received := expectRate(t, rates, 15100); received > 3 {
+		t.Errorf("received %d rates before the latest, want at most 3", received)
+	}
+}
+
+func TestWatchExchangeRatesResyncsAfterMissingRates(t *testing.T) {
+	now := time.Now()
+	service, db := newRateWatchingBank(t, usdToIdrRate(15000, now.Add(-time.Hour), now.Add(time.Hour)))
+	rates := watchRates(t, service)
+
+	expectRate(t, rates, 15000)
+
+	db.hold()
+
+	_, err := service.CreateExchangeRate(context.Background(), usdToIdrRate(15100, now, now.Add(time.Hour)))
+
+	if err != nil {
+		t.Fatalf("CreateExchangeRate : %v", err)
+	}
+
+	<-db.entered
+
+	// the held watch misses the window starting soonest, created after the buffer filled up
+	for i := range int64(64) {
+		rate := usdToIdrRate(16000+i, now.Add(time.Hour+time.Duration(i)*time.Second), now.Add(2*time.Hour))
+
+		if _, err := service.CreateExchangeRate(context.Background(), rate); err != nil {
+			t.Fatalf("CreateExchangeRate : %v", err)
+		}
+	}
+
+	_, err = service.CreateExchangeRate(context.Background(),
+		usdToIdrRate(15200, time.Now().Add(200*time.Millisecond), now.Add(time.Hour)))
+
+	if err != nil {
+		t.Fatalf("CreateExchangeRate : %v", err)
+	}
+
+	close(db.release)
+
+	expectRate(t, rates, 15100)
+	expectRate(t, rates, 15200)
+}
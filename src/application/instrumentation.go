@@ -0,0 +1,111 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"grpcbank/src/application/domain"
+	"grpcbank/src/port"
+)
+
+// OutcomeSuccess is the outcome recorded for operations that succeeded.
+const OutcomeSuccess = "success"
+
+// outcomes names the errors of the service in metrics, in the same order as they are matched.
+// Errors not listed are storage failures.
+var outcomes = []struct {
+	err     error
+	outcome string
+}{
+	{context.Canceled, "canceled"},
+	{context.DeadlineExceeded, "deadline_exceeded"},
+	{domain.ErrPermissionDenied, "permission_denied"},
+	{domain.ErrTransferSourceAccountNotFound, "source_account_not_found"},
+	{domain.ErrTransferDestinationAccountNotFound, "destination_account_not_found"},
+	{domain.ErrAccountNotFound, "account_not_found"},
+	{domain.ErrInsufficientBalance, "insufficient_balance"},
+	{domain.ErrInvalidAmount, "invalid_amount"},
+	{domain.ErrCurrencyMismatch, "currency_mismatch"},
+	{domain.ErrExchangeRateNotFound, "exchange_rate_not_found"},
+	{domain.ErrInvalidTransactionType, "invalid_transaction_type"},
+	{domain.ErrIdempotencyKeyReused, "idempotency_key_reused"},
+	{domain.ErrDuplicateIdempotencyKey, "idempotency_key_reused"},
+	{domain.ErrTransferRecordFailed, "transfer_record_failed"},
+	{domain.ErrTransferTransactionPair, "transfer_transaction_pair_failed"},
+	{domain.ErrBatchTooLarge, "batch_too_large"},
+}
+
+// Outcome names err in metrics, OutcomeSuccess when it is nil.
+func Outcome(err error) string {
+	if err == nil {
+		return OutcomeSuccess
+	}
+
+	for _, outcome := range outcomes {
+		if errors.Is(err, outcome.err) {
+			return outcome.outcome
+		}
+	}
+
+	return "storage_error"
+}
+
+// InstrumentedBankService records the transfers, transactions and exchange rates handled by the
+// wrapped service.
+type InstrumentedBankService struct {
+	port.BankServicePort
+	metrics port.MetricsPort
+}
+
+func NewInstrumentedBankService(next port.BankServicePort, metrics port.MetricsPort) *InstrumentedBankService {
+	return &InstrumentedBankService{
+		BankServicePort: next,
+		metrics:         metrics,
+	}
+}
+
+func (s *InstrumentedBankService) CreateExchangeRate(ctx context.Context,
+	exchangeRate domain.ExchangeRate) (uuid.UUID, error) {
+	exchangeRateUuid, err := s.BankServicePort.CreateExchangeRate(ctx, exchangeRate)
+
+	if err == nil {
+		s.metrics.RecordExchangeRate(domain.CurrencyPair{
+			FromCurrency: exchangeRate.FromCurrency,
+			ToCurrency:   exchangeRate.ToCurrency,
+		})
+	}
+
+	return exchangeRateUuid, err
+}
+
+func (s *InstrumentedBankService) CreateTransaction(ctx context.Context, acct string,
+	bankTrx domain.Transaction) (uuid.UUID, error) {
+	trxUuid, err := s.BankServicePort.CreateTransaction(ctx, acct, bankTrx)
+	s.metrics.RecordTransaction(bankTrx.TransactionType, bankTrx.Amount, Outcome(err))
+
+	return trxUuid, err
+}
+
+func (s *InstrumentedBankService) CreateTransactionsAtomically(ctx context.Context,
+	bankTrxs []domain.Transaction) ([]domain.TransactionResult, error) {
+	results, err := s.BankServicePort.CreateTransactionsAtomically(ctx, bankTrxs)
+
+	for i, result := range results {
+		switch result.Status {
+		case domain.TransactionResultSuccess:
+			s.metrics.RecordTransaction(bankTrxs[i].TransactionType, bankTrxs[i].Amount, OutcomeSuccess)
+		case domain.TransactionResultFailed:
+			s.metrics.RecordTransaction(bankTrxs[i].TransactionType, bankTrxs[i].Amount, Outcome(result.Err))
+		}
+	}
+
+	return results, err
+}
+
+func (s *InstrumentedBankService) Transfer(ctx context.Context,
+	transferTrx domain.TransferTransaction) (domain.Transfer, error) {
+	transfer, err := s.BankServicePort.Transfer(ctx, transferTrx)
+	s.metrics.RecordTransfer(Outcome(err))
+
+	return transfer, err
+}
@@ -0,0 +1,323 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"grpcbank/src/application/domain"
+	"grpcbank/src/port"
+	"sort"
+	"time"
+)
+
+// DefaultRoles are used when no policy file defines roles.
+var DefaultRoles = map[string][]domain.Permission{
+	"customer": {
+		domain.PermissionAccountsRead,
+		domain.PermissionTransactionsCreate,
+		domain.PermissionTransfersCreate,
+		domain.PermissionExchangeRatesRead,
+	},
+	"teller": {
+		domain.PermissionAccountsRead,
+		domain.PermissionAccountsAny,
+		domain.PermissionTransactionsCreate,
+		domain.PermissionTransfersCreate,
+		domain.PermissionExchangeRatesRead,
+	},
+	"treasury": {
+		domain.PermissionExchangeRatesRead,
+		domain.PermissionExchangeRatesCreate,
+	},
+	"auditor": {
+		domain.PermissionAccountsRead,
+		domain.PermissionAccountsAny,
+		domain.PermissionExchangeRatesRead,
+	},
+}
+
+// Policy grants permissions to principals through the roles bound to their subject. Subjects
+// without a binding get the default roles.
+type Policy struct {
+	roles        map[string]map[domain.Permission]bool
+	bindings     map[string][]string
+	defaultRoles []string
+	audit        port.AuditLogPort
+}
+
+func NewPolicy(roles map[string][]domain.Permission, bindings map[string][]string, defaultRoles []string,
+	audit port.AuditLogPort) (*Policy, error) {
+	known := make(map[domain.Permission]bool, len(domain.Permissions))
+
+	for _, permission := range domain.Permissions {
+		known[permission] = true
+	}
+
+	policy := &Policy{
+		roles:        make(map[string]map[domain.Permission]bool, len(roles)),
+		bindings:     bindings,
+		defaultRoles: defaultRoles,
+		audit:        audit,
+	}
+
+	var errs []error
+
+	for role, permissions := range roles {
+		policy.roles[role] = make(map[domain.Permission]bool, len(permissions))
+
+		for _, permission := range permissions {
+			if !known[permission] {
+				errs = append(errs, fmt.Errorf("role %v has unknown permission %q", role, permission))
+			}
+
+			policy.roles[role][permission] = true
+		}
+	}
+
+	for subject, boundRoles := range bindings {
+		for _, role := range boundRoles {
+			if _, ok := roles[role]; !ok {
+				errs = append(errs, fmt.Errorf("subject %v is bound to unknown role %q", subject, role))
+			}
+		}
+	}
+
+	for _, role := range defaultRoles {
+		if _, ok := roles[role]; !ok {
+			errs = append(errs, fmt.Errorf("unknown default role %q", role))
+		}
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+
+	return policy, nil
+}
+
+// authorize resolves the roles of the principal of ctx and checks that they grant permission. The
+// returned context carries the resolved principal, so the service can check account ownership. Calls
+// without a principal are denied, the policy is only installed when authentication is enabled.
+func (p *Policy) authorize(ctx context.Context, operation string, permission domain.Permission,
+	resource string) (context.Context, error) {
+	principal, ok := domain.PrincipalFromContext(ctx)
+
+	if !ok {
+		p.recordDenial(ctx, operation, permission, resource, "no authenticated principal")
+
+		return ctx, fmt.Errorf("%w : %v requires an authenticated caller", domain.ErrPermissionDenied, operation)
+	}
+
+	principal.Roles = p.bindings[principal.Subject]
+
+	if principal.Roles == nil {
+		principal.Roles = p.defaultRoles
+	}
+
+	principal.Permissions = make(map[domain.Permission]bool)
+
+	for _, role := range principal.Roles {
+		for granted := range p.roles[role] {
+			principal.Permissions[granted] = true
+		}
+	}
+
+	ctx = domain.ContextWithPrincipal(ctx, principal)
+
+	if !principal.Can(permission) {
+		reason := fmt.Sprintf("roles %v don't grant %v", principal.Roles, permission)
+		p.recordDenial(ctx, operation, permission, resource, reason)
+
+		return ctx, fmt.Errorf("%w : %v requires %v", domain.ErrPermissionDenied, operation, permission)
+	}
+
+	return ctx, nil
+}
+
+// checkOutcome audits the ownership denials returned by the service.
+func (p *Policy) checkOutcome(ctx context.Context, operation string, permission domain.Permission,
+	resource string, err error) {
+	if errors.Is(err, domain.ErrPermissionDenied) {
+		p.recordDenial(ctx, operation, permission, resource, err.Error())
+	}
+}
+
+func (p *Policy) recordDenial(ctx context.Context, operation string, permission domain.Permission,
+	resource string, reason string) {
+	principal, _ := domain.PrincipalFromContext(ctx)
+	roles := append([]string(nil), principal.Roles...)
+	sort.Strings(roles)
+
+	p.audit.RecordDenial(domain.AuditEvent{
+		Timestamp:  time.Now(),
+		Subject:    principal.Subject,
+		Roles:      roles,
+		Operation:  operation,
+		Permission: permission,
+		Resource:   resource,
+		Reason:     reason,
+	})
+}
+
+// AuthorizedBankService checks the permission each operation requires before handing it to the
+// wrapped service, which still checks account ownership. Every operation is wrapped explicitly, so a
+// new port operation doesn't compile until its permission is decided.
+type AuthorizedBankService struct {
+	next   port.BankServicePort
+	policy *Policy
+}
+
+var _ port.BankServicePort = (*AuthorizedBankService)(nil)
+
+func NewAuthorizedBankService(next port.BankServicePort, policy *Policy) *AuthorizedBankService {
+	return &AuthorizedBankService{
+		next:   next,
+		policy: policy,
+	}
+}
+
+// Ping is passed through, health checks don't carry a caller.
+func (s *AuthorizedBankService) Ping(ctx context.Context) error {
+	return s.next.Ping(ctx)
+}
+
+func (s *AuthorizedBankService) FindCurrentBalance(ctx context.Context, accountNumber string) (domain.Money, error) {
+	const operation, permission = "FindCurrentBalance", domain.PermissionAccountsRead
+
+	ctx, err := s.policy.authorize(ctx, operation, permission, accountNumber)
+
+	if err != nil {
+		return domain.Money{}, err
+	}
+
+	balance, err := s.next.FindCurrentBalance(ctx, accountNumber)
+	s.policy.checkOutcome(ctx, operation, permission, accountNumber, err)
+
+	return balance, err
+}
+
+func (s *AuthorizedBankService) CreateExchangeRate(ctx context.Context,
+	exchangeRate domain.ExchangeRate) (uuid.UUID, error) {
+	resource := exchangeRate.FromCurrency + "/" + exchangeRate.ToCurrency
+	ctx, err := s.policy.authorize(ctx, "CreateExchangeRate", domain.PermissionExchangeRatesCreate, resource)
+
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	return s.next.CreateExchangeRate(ctx, exchangeRate)
+}
+
+func (s *AuthorizedBankService) FindExchangeRate(ctx context.Context, fromCur string, toCur string,
+	ts time.Time) (domain.Rate, error) {
+	ctx, err := s.policy.authorize(ctx, "FindExchangeRate", domain.PermissionExchangeRatesRead, fromCur+"/"+toCur)
+
+	if err != nil {
+		return domain.Rate{}, err
+	}
+
+	return s.next.FindExchangeRate(ctx, fromCur, toCur, ts)
+}
+
+func (s *AuthorizedBankService) WatchExchangeRates(ctx context.Context,
+	pairs []domain.CurrencyPair) (<-chan domain.ExchangeRate, func(), error) {
+	ctx, err := s.policy.authorize(ctx, "WatchExchangeRates", domain.PermissionExchangeRatesRead, "")
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return s.next.WatchExchangeRates(ctx, pairs)
+}
+
+func (s *AuthorizedBankService) CreateTransaction(ctx context.Context, acct string,
+	bankTrx domain.Transaction) (uuid.UUID, error) {
+	const operation, permission = "CreateTransaction", domain.PermissionTransactionsCreate
+
+	ctx, err := s.policy.authorize(ctx, operation, permission, acct)
+
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	trxUuid, err := s.next.CreateTransaction(ctx, acct, bankTrx)
+	s.policy.checkOutcome(ctx, operation, permission, acct, err)
+
+	return trxUuid, err
+}
+
+func (s *AuthorizedBankService) ListTransactions(ctx context.Context, accountNumber string,
+	filter domain.TransactionFilter, pageSize int, pageToken string) ([]domain.Transaction, string, error) {
+	const operation, permission = "ListTransactions", domain.PermissionAccountsRead
+
+	ctx, err := s.policy.authorize(ctx, operation, permission, accountNumber)
+
+	if err != nil {
+		return nil, "", err
+	}
+
+	transactions, nextPageToken, err := s.next.ListTransactions(ctx, accountNumber, filter, pageSize,
+		pageToken)
+	s.policy.checkOutcome(ctx, operation, permission, accountNumber, err)
+
+	return transactions, nextPageToken, err
+}
+
+func (s *AuthorizedBankService) CreateTransactionsAtomically(ctx context.Context,
+	bankTrxs []domain.Transaction) ([]domain.TransactionResult, error) {
+	const operation, permission = "CreateTransactionsAtomically", domain.PermissionTransactionsCreate
+
+	ctx, err := s.policy.authorize(ctx, operation, permission, "")
+
+	if err != nil {
+		return nil, err
+	}
+
+	results, err := s.next.CreateTransactionsAtomically(ctx, bankTrxs)
+
+	for i, result := range results {
+		s.policy.checkOutcome(ctx, operation, permission, bankTrxs[i].AccountNumber, result.Err)
+	}
+
+	return results, err
+}
+
+// CalculateTransactionSummary is passed through, it only adds up transactions the caller was allowed to
+// create.
+func (s *AuthorizedBankService) CalculateTransactionSummary(ctx context.Context,
+	trxSummary *domain.TransactionSummary, bankTrx domain.Transaction) error {
+	return s.next.CalculateTransactionSummary(ctx, trxSummary, bankTrx)
+}
+
+func (s *AuthorizedBankService) Transfer(ctx context.Context,
+	transferTrx domain.TransferTransaction) (domain.Transfer, error) {
+	const operation, permission = "Transfer", domain.PermissionTransfersCreate
+
+	ctx, err := s.policy.authorize(ctx, operation, permission, transferTrx.FromAccountNumber)
+
+	if err != nil {
+		return domain.Transfer{}, err
+	}
+
+	transfer, err := s.next.Transfer(ctx, transferTrx)
+	s.policy.checkOutcome(ctx, operation, permission, transferTrx.FromAccountNumber, err)
+
+	return transfer, err
+}
+
+func (s *AuthorizedBankService) WatchBalance(ctx context.Context,
+	accountNumber string) (<-chan domain.BalanceUpdate, func(), error) {
+	const operation, permission = "WatchBalance", domain.PermissionAccountsRead
+
+	ctx, err := s.policy.authorize(ctx, operation, permission, accountNumber)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	updates, unsubscribe, err := s.next.WatchBalance(ctx, accountNumber)
+	s.policy.checkOutcome(ctx, operation, permission, accountNumber, err)
+
+	return updates, unsubscribe, err
+}
@@ -0,0 +1,242 @@
+package application_test
+
+import (
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"grpcbank/src/adapter/memory"
+	"grpcbank/src/application"
+	"grpcbank/src/application/domain"
+	"io"
+	"log/slog"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+type recordingAuditLog struct {
+	mu     sync.Mutex
+	events []domain.AuditEvent
+}
+
+func (l *recordingAuditLog) RecordDenial(event domain.AuditEvent) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.events = append(l.events, event)
+}
+
+// newAuthorizedBank serves the account 7835697001 owned by "kate" and the account 7835697002 owned by
+// "rupert". Subjects without a binding are customers, "treasurer" is bound to the treasury role.
+func newAuthorizedBank(t *testing.T) (*application.AuthorizedBankService, *recordingAuditLog) {
+	t.Helper()
+
+	db := memory.NewMemoryAdapter()
+	fixtures := []domain.AccountFixture{
+		{
+			AccountUuid:    uuid.New(),
+			AccountNumber:  "7835697001",
+			AccountName:    "Kate",
+			OwnerSubject:   "kate",
+			InitialDeposit: domain.NewMoney("USD", 10000),
+		},
+		{
+			AccountUuid:    uuid.New(),
+			AccountNumber:  "7835697002",
+			AccountName:    "Rupert",
+			OwnerSubject:   "rupert",
+			InitialDeposit: domain.NewMoney("USD", 500),
+		},
+	}
+
+	if _, err := application.SeedAccounts(context.Background(), db, fixtures); err != nil {
+		t.Fatalf("SeedAccounts : %v", err)
+	}
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	service := application.NewBankService(db, application.NewBalanceBroker(logger),
+		application.NewExchangeRateBroker(logger), time.Hour, logger)
+
+	audit := &recordingAuditLog{}
+	policy, err := application.NewPolicy(application.DefaultRoles, map[string][]string{"treasurer": {"treasury"}},
+		[]string{"customer"}, audit)
+
+	if err != nil {
+		t.Fatalf("NewPolicy : %v", err)
+	}
+
+	return application.NewAuthorizedBankService(service, policy), audit
+}
+
+func asSubject(subject string) context.Context {
+	return domain.ContextWithPrincipal(context.Background(), domain.Principal{Subject: subject})
+}
+
+func TestAuthorizedBankServiceAllows(t *testing.T) {
+	bank, audit := newAuthorizedBank(t)
+
+	balance, err := bank.FindCurrentBalance(asSubject("kate"), "7835697001")
+
+	if err != nil {
+		t.Fatalf("FindCurrentBalance : %v", err)
+	}
+
+	if want := domain.NewMoney("USD", 10000); balance != want {
+		t.Errorf("FindCurrentBalance = %v, want %v", balance, want)
+	}
+
+	_, err = bank.CreateTransaction(asSubject("kate"), "7835697001", domain.Transaction{
+		Amount:          domain.NewMoney("USD", 100),
+		TransactionType: domain.TransactionTypeOut,
+	})
+
+	if err != nil {
+		t.Errorf("CreateTransaction : %v", err)
+	}
+
+	_, err = bank.CreateExchangeRate(asSubject("treasurer"), domain.ExchangeRate{
+		FromCurrency:       "USD",
+		ToCurrency:         "IDR",
+		Rate:               domain.NewRate(15000, 0),
+		ValidFromTimestamp: time.Now(),
+		ValidToTimestamp:   time.Now().Add(time.Hour),
+	})
+
+	if err != nil {
+		t.Errorf("CreateExchangeRate : %v", err)
+	}
+
+	// health checks don't carry a caller
+	if err := bank.Ping(context.Background()); err != nil {
+		t.Errorf("Ping : %v", err)
+	}
+
+	if len(audit.events) != 0 {
+		t.Errorf("audit events = %+v, want none", audit.events)
+	}
+}
+
+func TestAuthorizedBankServiceDenies(t *testing.T) {
+	tests := []struct {
+		name string
+		call func(bank *application.AuthorizedBankService) error
+		want domain.AuditEvent
+	}{
+		{
+			name: "no principal",
+			call: func(bank *application.AuthorizedBankService) error {
+				_, err := bank.FindCurrentBalance(context.Background(), "7835697001")
+				return err
+			},
+			want: domain.AuditEvent{
+				Operation:  "FindCurrentBalance",
+				Permission: domain.PermissionAccountsRead,
+				Resource:   "7835697001",
+				Reason:     "no authenticated principal",
+			},
+		},
+		{
+			name: "missing permission",
+			call: func(bank *application.AuthorizedBankService) error {
+				_, err := bank.CreateExchangeRate(asSubject("kate"), domain.ExchangeRate{
+					FromCurrency: "USD",
+					ToCurrency:   "IDR",
+					Rate:         domain.NewRate(15000, 0),
+				})
+				return err
+			},
+			want: domain.AuditEvent{
+				Subject:    "kate",
+				Roles:      []string{"customer"},
+				Operation:  "CreateExchangeRate",
+				Permission: domain.PermissionExchangeRatesCreate,
+				Resource:   "USD/IDR",
+				Reason:     "roles [customer] don't grant exchange_rates.create",
+			},
+		},
+		{
+			name: "missing permission of a bound role",
+			call: func(bank *application.AuthorizedBankService) error {
+				_, err := bank.FindCurrentBalance(asSubject("treasurer"), "7835697001")
+				return err
+			},
+			want: domain.AuditEvent{
+				Subject:    "treasurer",
+				Roles:      []string{"treasury"},
+				Operation:  "FindCurrentBalance",
+				Permission: domain.PermissionAccountsRead,
+				Resource:   "7835697001",
+				Reason:     "roles [treasury] don't grant accounts.read",
+			},
+		},
+		{
+			name: "account of another subject",
+			call: func(bank *application.AuthorizedBankService) error {
+				_, err := bank.Transfer(asSubject("kate"), domain.TransferTransaction{
+					FromAccountNumber: "7835697002",
+					ToAccountNumber:   "7835697001",
+					Amount:            domain.NewMoney("USD", 100),
+				})
+				return err
+			},
+			want: domain.AuditEvent{
+				Subject:    "kate",
+				Roles:      []string{"customer"},
+				Operation:  "Transfer",
+				Permission: domain.PermissionTransfersCreate,
+				Resource:   "7835697002",
+				Reason:     "permission denied : account number 7835697002 is not owned by the caller",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bank, audit := newAuthorizedBank(t)
+
+			if err := tt.call(bank); !errors.Is(err, domain.ErrPermissionDenied) {
+				t.Fatalf("err = %v, want %v", err, domain.ErrPermissionDenied)
+			}
+
+			if len(audit.events) != 1 {
+				t.Fatalf("audit events = %+v, want one", audit.events)
+			}
+
+			got := audit.events[0]
+
+			if got.Timestamp.IsZero() {
+				t.Errorf("audit event timestamp is zero")
+			}
+
+			got.Timestamp = time.Time{}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("audit event = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAuthorizedBankServiceDeniesBeforeCalling(t *testing.T) {
+	bank, _ := newAuthorizedBank(t)
+
+	_, err := bank.CreateTransaction(context.Background(), "7835697001", domain.Transaction{
+		Amount:          domain.NewMoney("USD", 100),
+		TransactionType: domain.TransactionTypeOut,
+	})
+
+	if !errors.Is(err, domain.ErrPermissionDenied) {
+		t.Fatalf("CreateTransaction err = %v, want %v", err, domain.ErrPermissionDenied)
+	}
+
+	balance, err := bank.FindCurrentBalance(asSubject("kate"), "7835697001")
+
+	if err != nil {
+		t.Fatalf("FindCurrentBalance : %v", err)
+	}
+
+	if want := domain.NewMoney("USD", 10000); balance != want {
+		t.Errorf("FindCurrentBalance = %v, want %v", balance, want)
+	}
+}
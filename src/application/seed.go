@@ -0,0 +1,62 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"grpcbank/src/application/domain"
+	"grpcbank/src/port"
+	"time"
+)
+
+// SeedAccounts opens the accounts of fixtures that don't exist yet, each with its initial deposit,
+// and returns how many it opened. Nothing is written to a database flagged as production.
+func SeedAccounts(ctx context.Context, db port.BankSeedPort, fixtures []domain.AccountFixture) (int, error) {
+	production, err := db.IsProduction(ctx)
+
+	if err != nil {
+		return 0, err
+	}
+
+	if production {
+		return 0, domain.ErrProductionDatabase
+	}
+
+	now := time.Now()
+	opened := 0
+
+	for _, fixture := range fixtures {
+		acct := domain.Account{
+			AccountUuid:   fixture.AccountUuid,
+			AccountNumber: fixture.AccountNumber,
+			AccountName:   fixture.AccountName,
+			OwnerSubject:  fixture.OwnerSubject,
+			Balance:       domain.ZeroMoney(fixture.InitialDeposit.Currency),
+		}
+
+		var entries []domain.LedgerEntry
+
+		if !fixture.InitialDeposit.IsZero() {
+			entries = append(entries, domain.LedgerEntry{
+				TransactionUuid: uuid.New(),
+				AccountUuid:     fixture.AccountUuid,
+				Timestamp:       now,
+				Amount:          fixture.InitialDeposit,
+				TransactionType: domain.TransactionTypeIn,
+				Notes:           "Initial deposit",
+			})
+		}
+
+		created, err := db.SeedAccount(ctx, acct, entries)
+
+		if err != nil {
+			return opened, fmt.Errorf("can't seed account %v : %w", fixture.AccountNumber, err)
+		}
+
+		if created {
+			opened++
+		}
+	}
+
+	return opened, nil
+}
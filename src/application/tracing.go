@@ -0,0 +1,58 @@
+package application
+
+import (
+	"context"
+	"grpcbank/src/application/domain"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("grpcbank/src/application")
+
+// failedOutcomes are the outcomes caused by the bank rather than by the request, only they mark a
+// span as failed.
+var failedOutcomes = map[string]bool{
+	"storage_error":                    true,
+	"deadline_exceeded":                true,
+	"transfer_record_failed":           true,
+	"transfer_transaction_pair_failed": true,
+}
+
+// recordSpanError records the outcome of err on span. The text of err is left out, it may hold
+// account numbers.
+func recordSpanError(span trace.Span, err error) {
+	outcome := Outcome(err)
+	span.SetAttributes(attribute.String("bank.outcome", outcome))
+
+	if err == nil {
+		return
+	}
+
+	span.AddEvent(semconv.ExceptionEventName, trace.WithAttributes(semconv.ExceptionType(outcome)))
+
+	if failedOutcomes[outcome] {
+		span.SetStatus(codes.Error, outcome)
+	}
+}
+
+// Transfer traces the transfer with the currencies involved but without the account numbers, which
+// would otherwise leave the bank in every exported span.
+func (s *BankService) Transfer(ctx context.Context, transferTrx domain.TransferTransaction) (domain.Transfer,
+	error) {
+	ctx, span := tracer.Start(ctx, "BankService.Transfer", trace.WithAttributes(
+		attribute.String("bank.currency", transferTrx.Amount.Currency),
+		attribute.Bool("bank.idempotent", transferTrx.IdempotencyKey != ""),
+	))
+	defer span.End()
+
+	transfer, err := s.transfer(ctx, transferTrx)
+
+	span.SetAttributes(attribute.String("bank.transfer.status", transfer.Status))
+	recordSpanError(span, err)
+
+	return transfer, err
+}
@@ -0,0 +1,135 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"grpcbank/src/application/domain"
+	"grpcbank/src/logging"
+	"log/slog"
+	"time"
+)
+
+// MaxTransactionBatchSize bounds how many transactions CreateTransactionsAtomically accepts,
+// since the whole batch is held in memory and locked in one database transaction.
+const MaxTransactionBatchSize = 1000
+
+// CreateTransactionsAtomically applies every transaction in one database transaction. If any of
+// them fails, it is reported as failed and every other one as not applied. A transaction replaying
+// an idempotency key succeeds with its original UUID without being posted again. The error is set
+// only when the batch couldn't be attempted at all.
+func (s *BankService) CreateTransactionsAtomically(ctx context.Context,
+	bankTrxs []domain.Transaction) ([]domain.TransactionResult, error) {
+	if len(bankTrxs) > MaxTransactionBatchSize {
+		return nil, fmt.Errorf("%w : %d transactions, at most %d are allowed", domain.ErrBatchTooLarge,
+			len(bankTrxs), MaxTransactionBatchSize)
+	}
+
+	now := time.Now()
+	results := make([]domain.TransactionResult, len(bankTrxs))
+	accounts := make(map[string]domain.Account)
+
+	var postedIndexes []int
+	var postedEntries []domain.LedgerEntry
+
+	for i, bankTrx := range bankTrxs {
+		acct, ok := accounts[bankTrx.AccountNumber]
+
+		if !ok {
+			var err error
+			acct, err = s.db.GetBankAccountByAccountNumber(ctx, bankTrx.AccountNumber)
+
+			if err != nil && !errors.Is(err, domain.ErrAccountNotFound) {
+				return nil, err
+			}
+
+			if err != nil {
+				return failBatch(results, i, fmt.Errorf("can't find account number %v : %w",
+					bankTrx.AccountNumber, err)), nil
+			}
+
+			accounts[bankTrx.AccountNumber] = acct
+		}
+
+		if err := authorizeAccount(ctx, acct); err != nil {
+			return failBatch(results, i, err), nil
+		}
+
+		if err := validateTransaction(acct, bankTrx); err != nil {
+			return failBatch(results, i, err), nil
+		}
+
+		if bankTrx.IdempotencyKey != "" {
+			existingUuid, found, err := s.findTransactionReplay(ctx, acct, bankTrx, now)
+
+			if err != nil {
+				return failBatch(results, i, err), nil
+			}
+
+			if found {
+				results[i] = domain.TransactionResult{
+					TransactionUuid: existingUuid,
+					Status:          domain.TransactionResultSuccess,
+				}
+
+				continue
+			}
+		}
+
+		postedIndexes = append(postedIndexes, i)
+		postedEntries = append(postedEntries, newLedgerEntry(acct, bankTrx, now))
+	}
+
+	if len(postedEntries) == 0 {
+		return results, nil
+	}
+
+	updatedAccounts, failedIndex, err := s.db.CreateTransactionBatch(ctx, postedEntries)
+
+	if err != nil && failedIndex < 0 {
+		return nil, err
+	}
+
+	if err != nil {
+		s.logger.WarnContext(ctx, "Transaction batch rolled back", slog.Int("index", postedIndexes[failedIndex]),
+			slog.String(logging.AccountNumberKey, bankTrxs[postedIndexes[failedIndex]].AccountNumber),
+			slog.Any("error", err))
+
+		if errors.Is(err, domain.ErrInsufficientBalance) {
+			err = fmt.Errorf("%w for [out] transaction amount %v", domain.ErrInsufficientBalance,
+				bankTrxs[postedIndexes[failedIndex]].Amount)
+		}
+
+		return failBatch(results, postedIndexes[failedIndex], err), nil
+	}
+
+	for i, entry := range postedEntries {
+		results[postedIndexes[i]] = domain.TransactionResult{
+			TransactionUuid: entry.TransactionUuid,
+			Status:          domain.TransactionResultSuccess,
+		}
+
+		s.publishBalance(ctx, updatedAccounts[i], entry)
+	}
+
+	return results, nil
+}
+
+// failBatch marks the transaction at failedIndex as failed and every other one as not applied,
+// except replays which were applied by an earlier request.
+func failBatch(results []domain.TransactionResult, failedIndex int, err error) []domain.TransactionResult {
+	for i := range results {
+		if results[i].Status != domain.TransactionResultSuccess {
+			results[i] = domain.TransactionResult{
+				Status: domain.TransactionResultNotApplied,
+			}
+		}
+	}
+
+	results[failedIndex] = domain.TransactionResult{
+		Status: domain.TransactionResultFailed,
+		Err:    err,
+	}
+
+	return results
+}
@@ -0,0 +1,450 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// clearEnv unsets the BANK_ variables of the environment for the duration of the test.
+func clearEnv(t *testing.T) {
+	t.Helper()
+
+	for _, entry := range os.Environ() {
+		name, _, _ := strings.Cut(entry, "=")
+
+		if strings.HasPrefix(name, "BANK_") {
+			t.Setenv(name, "")
+			os.Unsetenv(name)
+		}
+	}
+}
+
+func writeFile(t *testing.T, name string, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+func TestLoadPrecedence(t *testing.T) {
+	yamlFile := `
+server:
+  listen_address: ":9100"
+  shutdown_timeout: 1m
+log:
+  level: debug
+metrics:
+  enabled: false
+exchange_rates:
+  pairs:
+    - {from: EUR, to: USD, min_rate: 1.05, max_rate: 1.1}
+`
+	tomlFile := `
+[server]
+listen_address = ":9200"
+
+[log]
+level = "warn"
+`
+
+	tests := []struct {
+		name  string
+		files map[string]string
+		env   map[string]string
+		args  []string
+		check func(t *testing.T, cfg Config)
+	}{
+		{
+			name: "defaults",
+			check: func(t *testing.T, cfg Config) {
+				if !reflect.DeepEqual(cfg, Default()) {
+					t.Errorf("Load = %+v, want the defaults %+v", cfg, Default())
+				}
+			},
+		},
+		{
+			name:  "file over defaults",
+			files: map[string]string{"bank.yaml": yamlFile},
+			args:  []string{"-config", "bank.yaml"},
+			check: func(t *testing.T, cfg Config) {
+				want := Default()
+				want.Server.ListenAddress = ":9100"
+				want.Server.ShutdownTimeout = time.Minute
+				want.Log.Level = "debug"
+				want.Metrics.Enabled = false
+				want.ExchangeRates.Pairs = []CurrencyPairConfig{{From: "EUR", To: "USD", MinRate: 1.05, MaxRate: 1.1}}
+
+				if !reflect.DeepEqual(cfg, want) {
+					t.Errorf("Load = %+v, want %+v", cfg, want)
+				}
+			},
+		},
+		{
+			name:  "TOML file named by the environment",
+			files: map[string]string{"bank.toml": tomlFile},
+			env:   map[string]string{envConfigFile: "bank.toml"},
+			check: func(t *testing.T, cfg Config) {
+				if cfg.Server.ListenAddress != ":9200" || cfg.Log.Level != "warn" {
+					t.Errorf("listen address, log level = %v, %v, want :9200, warn", cfg.Server.ListenAddress,
+						cfg.Log.Level)
+				}
+			},
+		},
+		{
+			name:  "config flag over the environment",
+			files: map[string]string{"bank.yaml": yamlFile, "bank.toml": tomlFile},
+			env:   map[string]string{envConfigFile: "bank.toml"},
+			args:  []string{"-config", "bank.yaml"},
+			check: func(t *testing.T, cfg Config) {
+				if cfg.Server.ListenAddress != ":9100" {
+					t.Errorf("listen address = %v, want :9100 of the flag's file", cfg.Server.ListenAddress)
+				}
+			},
+		},
+		{
+			name:  "environment over file",
+			files: map[string]string{"bank.yaml": yamlFile},
+			env: map[string]string{
+				"BANK_LISTEN_ADDRESS":      ":9300",
+				"BANK_METRICS":             "true",
+				"BANK_EXCHANGE_RATE_PAIRS": "USD/JPY:140-150, EUR/GBP:0.8-0.9",
+			},
+			args: []string{"-config", "bank.yaml"},
+			check: func(t *testing.T, cfg Config) {
+				pairs := []CurrencyPairConfig{
+					{From: "USD", To: "JPY", MinRate: 140, MaxRate: 150},
+					{From: "EUR", To: "GBP", MinRate: 0.8, MaxRate: 0.9},
+				}
+
+				if cfg.Server.ListenAddress != ":9300" || !cfg.Metrics.Enabled ||
+					!reflect.DeepEqual(cfg.ExchangeRates.Pairs, pairs) {
+					t.Errorf("listen address, metrics, pairs = %v, %v, %v, want :9300, true, %v",
+						cfg.Server.ListenAddress, cfg.Metrics.Enabled, cfg.ExchangeRates.Pairs, pairs)
+				}
+
+				// settings the environment leaves alone keep the file values
+				if cfg.Log.Level != "debug" || cfg.Server.ShutdownTimeout != time.Minute {
+					t.Errorf("log level, shutdown timeout = %v, %v, want debug, 1m", cfg.Log.Level,
+						cfg.Server.ShutdownTimeout)
+				}
+			},
+		},
+		{
+			name:  "flags over environment",
+			files: map[string]string{"bank.yaml": yamlFile},
+			env:   map[string]string{"BANK_LISTEN_ADDRESS": ":9300", "BANK_DB_DRIVER": "sqlite"},
+			args:  []string{"-config", "bank.yaml", "-listen-address", ":9400", "-exchange-rate-pairs", "USD/SGD:1.3-1.4"},
+			check: func(t *testing.T, cfg Config) {
+				pairs := []CurrencyPairConfig{{From: "USD", To: "SGD", MinRate: 1.3, MaxRate: 1.4}}
+
+				if cfg.Server.ListenAddress != ":9400" || !reflect.DeepEqual(cfg.ExchangeRates.Pairs, pairs) {
+					t.Errorf("listen address, pairs = %v, %v, want :9400, %v", cfg.Server.ListenAddress,
+						cfg.ExchangeRates.Pairs, pairs)
+				}
+
+				if cfg.Database.Driver != "sqlite" {
+					t.Errorf("database driver = %v, want sqlite of the environment", cfg.Database.Driver)
+				}
+			},
+		},
+		{
+			name:  "flags set to their default",
+			files: map[string]string{"bank.yaml": yamlFile},
+			env:   map[string]string{"BANK_LISTEN_ADDRESS": ":9300"},
+			args:  []string{"-config", "bank.yaml", "-listen-address", ":9000", "-log-level", "info", "-metrics=true"},
+			check: func(t *testing.T, cfg Config) {
+				if cfg.Server.ListenAddress != ":9000" || cfg.Log.Level != "info" || !cfg.Metrics.Enabled {
+					t.Errorf("listen address, log level, metrics = %v, %v, %v, want :9000, info, true",
+						cfg.Server.ListenAddress, cfg.Log.Level, cfg.Metrics.Enabled)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearEnv(t)
+			dir := t.TempDir()
+
+			for name, content := range tt.files {
+				if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			for name, value := range tt.env {
+				if name == envConfigFile {
+					value = filepath.Join(dir, value)
+				}
+
+				t.Setenv(name, value)
+			}
+
+			args := append([]string(nil), tt.args...)
+
+			for i, arg := range args {
+				if i > 0 && args[i-1] == "-config" {
+					args[i] = filepath.Join(dir, arg)
+				}
+			}
+
+			cfg, rest, err := Load(append(args, "migrate", "up"))
+
+			if err != nil {
+				t.Fatalf("Load : %v", err)
+			}
+
+			if !reflect.DeepEqual(rest, []string{"migrate", "up"}) {
+				t.Errorf("remaining arguments = %v, want [migrate up]", rest)
+			}
+
+			tt.check(t, cfg)
+		})
+	}
+}
+
+func TestLoadRejects(t *testing.T) {
+	tests := []struct {
+		name string
+		file string
+		env  map[string]string
+		args []string
+		want string
+	}{
+		{
+			name: "integer variable",
+			env:  map[string]string{"BANK_DB_MAX_OPEN_CONNS": "twenty"},
+			want: "invalid value for BANK_DB_MAX_OPEN_CONNS",
+		},
+		{
+			name: "boolean variable",
+			env:  map[string]string{"BANK_REFLECTION": "maybe"},
+			want: "invalid value for BANK_REFLECTION",
+		},
+		{
+			name: "duration variable without unit",
+			env:  map[string]string{"BANK_SHUTDOWN_TIMEOUT": "30"},
+			want: "invalid value for BANK_SHUTDOWN_TIMEOUT",
+		},
+		{
+			name: "float variable",
+			env:  map[string]string{"BANK_TRACING_SAMPLE_RATIO": "half"},
+			want: "invalid value for BANK_TRACING_SAMPLE_RATIO",
+		},
+		{
+			name: "currency pairs variable",
+			env:  map[string]string{"BANK_EXCHANGE_RATE_PAIRS": "USD-IDR"},
+			want: "must look like USD/IDR:2000-2300",
+		},
+		{
+			name: "currency pair rate",
+			env:  map[string]string{"BANK_EXCHANGE_RATE_PAIRS": "USD/IDR:low-2300"},
+			want: "invalid min rate",
+		},
+		{
+			name: "missing file",
+			env:  map[string]string{envConfigFile: filepath.Join(os.TempDir(), "missing-bank-config.yaml")},
+			want: "can't read file",
+		},
+		{
+			name: "file extension",
+			file: "bank.ini",
+			want: "unsupported file",
+		},
+		{
+			name: "malformed YAML",
+			file: "bank.yaml",
+			want: "can't parse file",
+		},
+		{
+			name: "mistyped YAML value",
+			file: "typed.yaml",
+			want: "can't parse file",
+		},
+		{
+			name: "malformed TOML",
+			file: "bank.toml",
+			want: "can't parse file",
+		},
+		{
+			name: "flag value",
+			args: []string{"-db-max-open-conns", "twenty"},
+			want: "invalid value",
+		},
+		{
+			name: "unknown flag",
+			args: []string{"-listen"},
+			want: "flag provided but not defined",
+		},
+		{
+			name: "invalid setting",
+			env:  map[string]string{"BANK_LOG_LEVEL": "loud"},
+			want: `unknown log level "loud"`,
+		},
+	}
+
+	files := map[string]string{
+		"bank.ini":   "listen_address = :9000",
+		"bank.yaml":  "server: [listen_address",
+		"typed.yaml": "server:\n  shutdown_timeout: soon\n",
+		"bank.toml":  "[server\nlisten_address = \":9000\"",
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearEnv(t)
+
+			for name, value := range tt.env {
+				t.Setenv(name, value)
+			}
+
+			if tt.file != "" {
+				t.Setenv(envConfigFile, writeFile(t, tt.file, files[tt.file]))
+			}
+
+			if _, _, err := Load(tt.args); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Load = %v, want an error containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := Default().Validate(); err != nil {
+		t.Fatalf("Validate of the defaults = %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		change func(cfg *Config)
+		want   string
+	}{
+		{"database driver", func(cfg *Config) { cfg.Database.Driver = "mysql" },
+			`unknown database driver "mysql"`},
+		{"postgres without dsn", func(cfg *Config) { cfg.Database.DSN = "" },
+			"database dsn is required"},
+		{"sqlite without file", func(cfg *Config) { cfg.Database.Driver, cfg.Database.SQLiteFile = "sqlite", "" },
+			"database sqlite file is required"},
+		{"negative connection limit", func(cfg *Config) { cfg.Database.MaxIdleConns = -1 },
+			"database connection limits can't be negative"},
+		{"negative connection lifetime", func(cfg *Config) { cfg.Database.ConnMaxIdleTime = -time.Second },
+			"database connection lifetimes can't be negative"},
+		{"negative database timeout", func(cfg *Config) { cfg.Database.WriteTimeout = -time.Second },
+			"database timeouts can't be negative"},
+		{"listen address", func(cfg *Config) { cfg.Server.ListenAddress = "9000" },
+			`invalid listen address "9000"`},
+		{"shutdown timeout", func(cfg *Config) { cfg.Server.ShutdownTimeout = 0 },
+			"shutdown timeout must be positive"},
+		{"health check interval", func(cfg *Config) { cfg.Server.HealthCheckInterval = -time.Second },
+			"health check interval must be positive"},
+		{"idempotency retention", func(cfg *Config) { cfg.Idempotency.Retention = 0 },
+			"idempotency retention must be positive"},
+		{"tls cert without key", func(cfg *Config) { cfg.Server.TLS.CertFile = "server.crt" },
+			"tls cert file and key file must be set together"},
+		{"tls client ca without tls", func(cfg *Config) { cfg.Server.TLS.ClientCAFile = "ca.crt" },
+			"tls client ca file requires a tls cert file and key file"},
+		{"tls reload interval", func(cfg *Config) {
+			cfg.Server.TLS.CertFile, cfg.Server.TLS.KeyFile = "server.crt", "server.key"
+			cfg.Server.TLS.ReloadInterval = 0
+		}, "tls reload interval must be positive"},
+		{"policy without authentication", func(cfg *Config) { cfg.Auth.PolicyFile = "policy.yaml" },
+			"auth policy file requires an auth jwks file or a tls client ca file"},
+		{"negative default rate limit", func(cfg *Config) { cfg.RateLimits.Default.MessageBurst = -1 },
+			"rate limits of default can't be negative"},
+		{"negative method rate limit", func(cfg *Config) {
+			cfg.RateLimits.Methods = map[string]MethodRateLimitConfig{"GetBalance": {CallsPerSecond: -1}}
+		}, "rate limits of GetBalance can't be negative"},
+		{"log level", func(cfg *Config) { cfg.Log.Level = "verbose" },
+			`unknown log level "verbose"`},
+		{"metrics listen address", func(cfg *Config) { cfg.Metrics.ListenAddress = "metrics" },
+			`invalid metrics listen address "metrics"`},
+		{"tracing exporter", func(cfg *Config) { cfg.Tracing.Exporter = "jaeger" },
+			`unknown tracing exporter "jaeger"`},
+		{"otlp without endpoint", func(cfg *Config) { cfg.Tracing.Exporter, cfg.Tracing.Endpoint = "otlp", "" },
+			"tracing endpoint is required by the otlp exporter"},
+		{"file exporter without file", func(cfg *Config) { cfg.Tracing.Exporter = "file" },
+			"tracing file is required by the file exporter"},
+		{"sample ratio above 1", func(cfg *Config) { cfg.Tracing.SampleRatio = 1.5 },
+			"tracing sample ratio 1.5 must be between 0 and 1"},
+		{"negative sample ratio", func(cfg *Config) { cfg.Tracing.SampleRatio = -0.1 },
+			"tracing sample ratio -0.1 must be between 0 and 1"},
+		{"exchange rate interval", func(cfg *Config) { cfg.ExchangeRates.Interval = 0 },
+			"exchange rate interval must be positive"},
+		{"lowercase currency code", func(cfg *Config) { cfg.ExchangeRates.Pairs[0].From = "usd" },
+			"must use 3 letter uppercase currency codes"},
+		{"long currency code", func(cfg *Config) { cfg.ExchangeRates.Pairs[0].To = "IDRX" },
+			"must use 3 letter uppercase currency codes"},
+		{"pair of one currency", func(cfg *Config) { cfg.ExchangeRates.Pairs[0].To = "USD" },
+			"converts a currency into itself"},
+		{"zero min rate", func(cfg *Config) { cfg.ExchangeRates.Pairs[0].MinRate = 0 },
+			"needs 0 < min rate <= max rate"},
+		{"max rate below min rate", func(cfg *Config) { cfg.ExchangeRates.Pairs[0].MaxRate = 1000 },
+			"needs 0 < min rate <= max rate"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := Default()
+			tt.change(&cfg)
+
+			if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Validate = %v, want an error containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateReportsEverySetting(t *testing.T) {
+	cfg := Default()
+	cfg.Database.Driver = "mysql"
+	cfg.Log.Level = "verbose"
+	cfg.ExchangeRates.Interval = 0
+
+	err := cfg.Validate()
+
+	for _, want := range []string{"unknown database driver", "unknown log level", "exchange rate interval"} {
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("Validate = %v, want an error containing %q", err, want)
+		}
+	}
+}
+
+func TestValidateAccepts(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(cfg *Config)
+	}{
+		{"memory without dsn", func(cfg *Config) { cfg.Database.Driver, cfg.Database.DSN = "memory", "" }},
+		{"disabled metrics without address", func(cfg *Config) {
+			cfg.Metrics.Enabled, cfg.Metrics.ListenAddress = false, ""
+		}},
+		{"disabled tls without reload interval", func(cfg *Config) { cfg.Server.TLS.ReloadInterval = 0 }},
+		{"policy with client certificates", func(cfg *Config) {
+			cfg.Server.TLS.CertFile, cfg.Server.TLS.KeyFile = "server.crt", "server.key"
+			cfg.Server.TLS.ClientCAFile = "ca.crt"
+			cfg.Auth.PolicyFile = "policy.yaml"
+		}},
+		{"zero rate limits", func(cfg *Config) { cfg.RateLimits.Default = MethodRateLimitConfig{} }},
+		{"no currency pairs", func(cfg *Config) { cfg.ExchangeRates.Pairs = nil }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := Default()
+			tt.change(&cfg)
+
+			if err := cfg.Validate(); err != nil {
+				t.Errorf("Validate = %v, want nil", err)
+			}
+		})
+	}
+}
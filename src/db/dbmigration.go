@@ -2,31 +2,150 @@ package dbmigration
 
 import (
 	"database/sql"
-	"log"
+	"embed"
+	"errors"
+	"fmt"
 
 	migrate "github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database"
 	"github.com/golang-migrate/migrate/v4/database/postgres"
-	_ "github.com/golang-migrate/migrate/v4/database/postgres"
-	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"github.com/golang-migrate/migrate/v4/database/sqlite"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
 )
 
-func Migrate(conn *sql.DB) {
-	log.Println("Database migration start")
+// The migrations are built into the binary, so that it runs from any directory.
+var (
+	//go:embed migrations/*.sql
+	postgresMigrations embed.FS
 
-	driver, _ := postgres.WithInstance(conn, &postgres.Config{})
-	m, err := migrate.NewWithDatabaseInstance("file://src/db/migrations", "postgres", driver)
+	//go:embed sqlite_migrations/*.sql
+	sqliteMigrations embed.FS
+)
+
+// DemoFixtures is the YAML fixture file of the demo accounts, named DemoFixturesFile.
+//
+//go:embed seeds/demo.yaml
+var DemoFixtures []byte
+
+const DemoFixturesFile = "demo.yaml"
+
+// ErrDirty reports a database left half migrated by a failed migration. Its schema has to be
+// repaired by hand before Force records the version it matches.
+var ErrDirty = errors.New("database is dirty")
+
+// ErrNoVersion reports a database no migration was applied to.
+var ErrNoVersion = errors.New("no migration applied")
+
+// Migrator applies the embedded migrations to a database. It owns the connection it was created
+// with, which Close closes.
+type Migrator struct {
+	migrate *migrate.Migrate
+}
+
+// NewPostgresMigrator migrates the Postgres database of conn. Migrations hold an advisory lock, so
+// instances starting together apply them once.
+func NewPostgresMigrator(conn *sql.DB) (*Migrator, error) {
+	driver, err := postgres.WithInstance(conn, &postgres.Config{})
+
+	if err != nil {
+		return nil, fmt.Errorf("can't prepare postgres migrations : %w", err)
+	}
+
+	return newMigrator(postgresMigrations, "migrations", "postgres", driver)
+}
+
+// NewSQLiteMigrator migrates the SQLite database of conn.
+func NewSQLiteMigrator(conn *sql.DB) (*Migrator, error) {
+	driver, err := sqlite.WithInstance(conn, &sqlite.Config{})
+
+	if err != nil {
+		return nil, fmt.Errorf("can't prepare sqlite migrations : %w", err)
+	}
+
+	return newMigrator(sqliteMigrations, "sqlite_migrations", "sqlite", driver)
+}
+
+func newMigrator(migrations embed.FS, dir string, databaseName string, driver database.Driver) (*Migrator, error) {
+	source, err := iofs.New(migrations, dir)
+
+	if err != nil {
+		return nil, fmt.Errorf("can't read migrations : %w", err)
+	}
+
+	m, err := migrate.NewWithInstance("iofs", source, databaseName, driver)
 
 	if err != nil {
-		log.Println("Database migration failed :", err)
+		return nil, fmt.Errorf("can't prepare migrations : %w", err)
 	}
 
-	//if err := m.Down(); err != nil {
-	//	log.Println("Database migration (down) failed :", err)
-	//}
+	return &Migrator{migrate: m}, nil
+}
+
+// Up applies every pending migration.
+func (m *Migrator) Up() error {
+	return m.run(m.migrate.Up)
+}
+
+// Down reverts the last steps migrations.
+func (m *Migrator) Down(steps int) error {
+	return m.run(func() error {
+		return m.migrate.Steps(-steps)
+	})
+}
+
+// Goto applies or reverts migrations until version is the last one applied.
+func (m *Migrator) Goto(version uint) error {
+	return m.run(func() error {
+		return m.migrate.Migrate(version)
+	})
+}
+
+// Version returns the last migration applied and whether it failed half way, or ErrNoVersion.
+func (m *Migrator) Version() (uint, bool, error) {
+	version, dirty, err := m.migrate.Version()
+
+	if errors.Is(err, migrate.ErrNilVersion) {
+		return 0, false, ErrNoVersion
+	}
+
+	return version, dirty, err
+}
+
+// Force records version as the last migration applied and clears the dirty state, without running
+// any migration. A version of -1 records that none was applied.
+func (m *Migrator) Force(version int) error {
+	return m.migrate.Force(version)
+}
+
+// Close releases the lock and closes the connection of the migrator.
+func (m *Migrator) Close() error {
+	sourceErr, databaseErr := m.migrate.Close()
+
+	return errors.Join(sourceErr, databaseErr)
+}
+
+// run refuses to migrate a dirty database, and treats having nothing to migrate as success.
+func (m *Migrator) run(migration func() error) error {
+	version, dirty, err := m.Version()
+
+	if err != nil && !errors.Is(err, ErrNoVersion) {
+		return err
+	}
+
+	if dirty {
+		return fmt.Errorf("%w at version %d, repair its schema then force the version it matches", ErrDirty,
+			version)
+	}
+
+	if err := migration(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		var dirtyErr migrate.ErrDirty
+
+		if errors.As(err, &dirtyErr) {
+			return fmt.Errorf("%w at version %d : %w", ErrDirty, dirtyErr.Version, err)
+		}
 
-	if err := m.Up(); err != nil {
-		log.Println("Database migration (up) failed :", err)
+		return err
 	}
 
-	log.Println("Database migration end")
+	return nil
 }
@@ -1 +1,2 @@
-DELETE FROM bank_accounts; 
\ No newline at end of file
+-- Reverting the schema leaves the rows in place, see the up migration.
+SELECT 1;
@@ -1 +1,2 @@
-DELETE FROM bank_exchange_rates;
\ No newline at end of file
+-- Exchange rates used to be deleted here. Migrations only change the schema now.
+SELECT 1;
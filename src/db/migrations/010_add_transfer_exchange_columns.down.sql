@@ -0,0 +1,13 @@
+ALTER TABLE bank_transactions
+    DROP COLUMN IF EXISTS exchange_rate,
+    DROP COLUMN IF EXISTS destination_amount,
+    DROP COLUMN IF EXISTS destination_currency,
+    DROP COLUMN IF EXISTS source_amount,
+    DROP COLUMN IF EXISTS source_currency;
+
+ALTER TABLE bank_transfers
+    DROP COLUMN IF EXISTS exchange_rate,
+    DROP COLUMN IF EXISTS destination_amount,
+    DROP COLUMN IF EXISTS destination_currency,
+    DROP COLUMN IF EXISTS source_amount,
+    DROP COLUMN IF EXISTS source_currency;
@@ -0,0 +1,28 @@
+ALTER TABLE bank_transfers
+    ADD COLUMN IF NOT EXISTS source_currency        VARCHAR(5),
+    ADD COLUMN IF NOT EXISTS source_amount          NUMERIC(19,4),
+    ADD COLUMN IF NOT EXISTS destination_currency   VARCHAR(5),
+    ADD COLUMN IF NOT EXISTS destination_amount     NUMERIC(19,4),
+    ADD COLUMN IF NOT EXISTS exchange_rate          NUMERIC(20,10);
+
+UPDATE bank_transfers
+SET source_currency = currency,
+    source_amount = amount,
+    destination_currency = currency,
+    destination_amount = amount,
+    exchange_rate = 1
+WHERE exchange_rate IS NULL;
+
+ALTER TABLE bank_transfers
+    ALTER COLUMN source_currency SET NOT NULL,
+    ALTER COLUMN source_amount SET NOT NULL,
+    ALTER COLUMN destination_currency SET NOT NULL,
+    ALTER COLUMN destination_amount SET NOT NULL,
+    ALTER COLUMN exchange_rate SET NOT NULL;
+
+ALTER TABLE bank_transactions
+    ADD COLUMN IF NOT EXISTS source_currency        VARCHAR(5),
+    ADD COLUMN IF NOT EXISTS source_amount          NUMERIC(19,4),
+    ADD COLUMN IF NOT EXISTS destination_currency   VARCHAR(5),
+    ADD COLUMN IF NOT EXISTS destination_amount     NUMERIC(19,4),
+    ADD COLUMN IF NOT EXISTS exchange_rate          NUMERIC(20,10);
@@ -0,0 +1,4 @@
+ALTER TABLE bank_accounts ADD COLUMN IF NOT EXISTS owner_subject VARCHAR(255) NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS bank_accounts_owner_subject_idx
+    ON bank_accounts (owner_subject);
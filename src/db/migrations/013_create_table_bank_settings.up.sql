@@ -0,0 +1,6 @@
+-- Settings of the database itself. The seed subcommand refuses to run while environment is
+-- production.
+CREATE TABLE IF NOT EXISTS bank_settings(
+  name                VARCHAR(64)     PRIMARY KEY,
+  value               VARCHAR(255)    NOT NULL
+);
@@ -0,0 +1,7 @@
+ALTER TABLE bank_transfers ADD COLUMN IF NOT EXISTS transfer_success BOOLEAN NOT NULL DEFAULT FALSE;
+
+UPDATE bank_transfers SET transfer_success = TRUE WHERE transfer_status = 'SUCCEEDED';
+
+ALTER TABLE bank_transfers
+    DROP COLUMN IF EXISTS failure_reason,
+    DROP COLUMN IF EXISTS transfer_status;
@@ -0,0 +1,10 @@
+-- Transfers are recorded PENDING before their legs are posted, and end SUCCEEDED or FAILED with the
+-- reason the posting was refused for. An unsuccessful transfer recorded before can't tell a refused
+-- posting from an interrupted one, so it is left pending for a retry to post.
+ALTER TABLE bank_transfers
+    ADD COLUMN IF NOT EXISTS transfer_status    VARCHAR(16)     NOT NULL DEFAULT 'PENDING',
+    ADD COLUMN IF NOT EXISTS failure_reason     VARCHAR(255);
+
+UPDATE bank_transfers SET transfer_status = 'SUCCEEDED' WHERE transfer_success;
+
+ALTER TABLE bank_transfers DROP COLUMN IF EXISTS transfer_success;
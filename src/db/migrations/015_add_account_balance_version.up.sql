@@ -0,0 +1,3 @@
+-- Counts the entries posted to an account, so that balance updates relayed between instances can be
+-- put back in order.
+ALTER TABLE bank_accounts ADD COLUMN IF NOT EXISTS balance_version BIGINT NOT NULL DEFAULT 0;
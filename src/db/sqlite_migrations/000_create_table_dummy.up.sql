@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS dummy (
+  user_id       TEXT PRIMARY KEY,
+  user_name     TEXT NOT NULL,
+  created_at    TEXT,
+  updated_at    TEXT
+);
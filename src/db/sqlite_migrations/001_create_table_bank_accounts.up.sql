@@ -0,0 +1,15 @@
+-- Mirrors the Postgres migrations with SQLite types:
+--   UUIDs are lowercase TEXT, which sorts like the UUID type of Postgres.
+--   Amounts are INTEGER minor units of their currency, so sums and comparisons are exact.
+--   Rates are decimal TEXT, they are only stored and read back.
+--   Timestamps are UTC TEXT with 6 fractional digits (2006-01-02T15:04:05.000000Z), which sorts
+--   chronologically and keeps the microseconds of TIMESTAMPTZ.
+CREATE TABLE IF NOT EXISTS bank_accounts(
+  account_uuid            TEXT            PRIMARY KEY,
+  account_number          TEXT            UNIQUE NOT NULL,
+  account_name            TEXT            NOT NULL,
+  currency                TEXT            NOT NULL,
+  current_balance         INTEGER         NOT NULL,
+  created_at              TEXT,
+  updated_at              TEXT
+);
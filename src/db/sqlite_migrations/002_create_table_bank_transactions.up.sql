@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS bank_transactions(
+  transaction_uuid        TEXT            PRIMARY KEY,
+  account_uuid            TEXT            NOT NULL REFERENCES bank_accounts,
+  transaction_timestamp   TEXT            NOT NULL,
+  amount                  INTEGER         NOT NULL,
+  transaction_type        TEXT            NOT NULL,
+  notes                   TEXT,
+  created_at              TEXT,
+  updated_at              TEXT
+);
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS bank_exchange_rates(
+    exchange_rate_uuid          TEXT            PRIMARY KEY,
+    from_currency               TEXT            NOT NULL,
+    to_currency                 TEXT            NOT NULL,
+    rate                        TEXT            NOT NULL,
+    valid_from_timestamp        TEXT            NOT NULL,
+    valid_to_timestamp          TEXT            NOT NULL,
+    created_at                  TEXT,
+    updated_at                  TEXT
+);
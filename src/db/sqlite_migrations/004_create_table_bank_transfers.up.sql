@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS bank_transfers(
+    transfer_uuid           TEXT            PRIMARY KEY,
+    from_account_uuid       TEXT            REFERENCES bank_accounts,
+    to_account_uuid         TEXT            REFERENCES bank_accounts,
+    currency                TEXT            NOT NULL,
+    amount                  INTEGER         NOT NULL,
+    transfer_timestamp      TEXT            NOT NULL,
+    transfer_success        INTEGER         NOT NULL DEFAULT 0,
+    created_at              TEXT,
+    updated_at              TEXT
+);
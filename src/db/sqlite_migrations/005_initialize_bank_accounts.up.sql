@@ -0,0 +1,3 @@
+-- The demo accounts used to be inserted here, after deleting every account. They are loaded by the
+-- seed subcommand now, so that migrating a database never touches its rows.
+SELECT 1;
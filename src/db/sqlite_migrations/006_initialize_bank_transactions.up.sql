@@ -0,0 +1,2 @@
+-- The initial deposits of the demo accounts are loaded by the seed subcommand now.
+SELECT 1;
@@ -0,0 +1,2 @@
+-- Reverting the schema leaves the rows in place, see the up migration.
+SELECT 1;
@@ -0,0 +1,2 @@
+-- Exchange rates used to be deleted here. Migrations only change the schema now.
+SELECT 1;
@@ -0,0 +1,2 @@
+-- Amounts are INTEGER minor units, which already hold the precision Postgres gains here.
+SELECT 1;
@@ -0,0 +1,9 @@
+ALTER TABLE bank_transfers ADD COLUMN idempotency_key TEXT;
+
+CREATE UNIQUE INDEX IF NOT EXISTS bank_transfers_idempotency_key_idx
+    ON bank_transfers (idempotency_key);
+
+ALTER TABLE bank_transactions ADD COLUMN idempotency_key TEXT;
+
+CREATE UNIQUE INDEX IF NOT EXISTS bank_transactions_idempotency_key_idx
+    ON bank_transactions (idempotency_key);
@@ -0,0 +1,11 @@
+ALTER TABLE bank_transactions DROP COLUMN exchange_rate;
+ALTER TABLE bank_transactions DROP COLUMN destination_amount;
+ALTER TABLE bank_transactions DROP COLUMN destination_currency;
+ALTER TABLE bank_transactions DROP COLUMN source_amount;
+ALTER TABLE bank_transactions DROP COLUMN source_currency;
+
+ALTER TABLE bank_transfers DROP COLUMN exchange_rate;
+ALTER TABLE bank_transfers DROP COLUMN destination_amount;
+ALTER TABLE bank_transfers DROP COLUMN destination_currency;
+ALTER TABLE bank_transfers DROP COLUMN source_amount;
+ALTER TABLE bank_transfers DROP COLUMN source_currency;
@@ -0,0 +1,21 @@
+-- SQLite can't make the transfer columns NOT NULL after the fact like Postgres does, the adapter
+-- always sets them.
+ALTER TABLE bank_transfers ADD COLUMN source_currency TEXT;
+ALTER TABLE bank_transfers ADD COLUMN source_amount INTEGER;
+ALTER TABLE bank_transfers ADD COLUMN destination_currency TEXT;
+ALTER TABLE bank_transfers ADD COLUMN destination_amount INTEGER;
+ALTER TABLE bank_transfers ADD COLUMN exchange_rate TEXT;
+
+ALTER TABLE bank_transactions ADD COLUMN source_currency TEXT;
+ALTER TABLE bank_transactions ADD COLUMN source_amount INTEGER;
+ALTER TABLE bank_transactions ADD COLUMN destination_currency TEXT;
+ALTER TABLE bank_transactions ADD COLUMN destination_amount INTEGER;
+ALTER TABLE bank_transactions ADD COLUMN exchange_rate TEXT;
+
+UPDATE bank_transfers
+SET source_currency = currency,
+    source_amount = amount,
+    destination_currency = currency,
+    destination_amount = amount,
+    exchange_rate = '1'
+WHERE exchange_rate IS NULL;
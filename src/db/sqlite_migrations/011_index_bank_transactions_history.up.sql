@@ -0,0 +1,2 @@
+CREATE INDEX IF NOT EXISTS bank_transactions_account_history_idx
+    ON bank_transactions (account_uuid, transaction_timestamp DESC, transaction_uuid DESC);
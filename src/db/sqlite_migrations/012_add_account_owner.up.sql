@@ -0,0 +1,4 @@
+ALTER TABLE bank_accounts ADD COLUMN owner_subject TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS bank_accounts_owner_subject_idx
+    ON bank_accounts (owner_subject);
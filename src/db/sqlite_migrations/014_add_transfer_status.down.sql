@@ -0,0 +1,6 @@
+ALTER TABLE bank_transfers ADD COLUMN transfer_success INTEGER NOT NULL DEFAULT 0;
+
+UPDATE bank_transfers SET transfer_success = 1 WHERE transfer_status = 'SUCCEEDED';
+
+ALTER TABLE bank_transfers DROP COLUMN failure_reason;
+ALTER TABLE bank_transfers DROP COLUMN transfer_status;
@@ -0,0 +1,9 @@
+-- Transfers are recorded PENDING before their legs are posted, and end SUCCEEDED or FAILED with the
+-- reason the posting was refused for. An unsuccessful transfer recorded before can't tell a refused
+-- posting from an interrupted one, so it is left pending for a retry to post.
+ALTER TABLE bank_transfers ADD COLUMN transfer_status TEXT NOT NULL DEFAULT 'PENDING';
+ALTER TABLE bank_transfers ADD COLUMN failure_reason TEXT;
+
+UPDATE bank_transfers SET transfer_status = 'SUCCEEDED' WHERE transfer_success = 1;
+
+ALTER TABLE bank_transfers DROP COLUMN transfer_success;
@@ -0,0 +1,2 @@
+-- Counts the entries posted to an account, so that balance updates can be put back in order.
+ALTER TABLE bank_accounts ADD COLUMN balance_version INTEGER NOT NULL DEFAULT 0;
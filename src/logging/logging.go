@@ -0,0 +1,218 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"regexp"
+	"strings"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Keys of the log fields holding personal data. Their values are masked, and so is every occurrence
+// of them in the message and the other string fields of the same record, such as error texts.
+// Account numbers quoted by those texts are masked as well, since errors often quote the account
+// they are about without the record naming it.
+const (
+	AccountNumberKey     = "account_number"
+	FromAccountNumberKey = "from_account_number"
+	ToAccountNumberKey   = "to_account_number"
+	AccountNameKey       = "account_name"
+)
+
+// RequestIDKey is the log field carrying the request ID of the context.
+const RequestIDKey = "request_id"
+
+// Keys of the log fields carrying the span of the context, when it is traced.
+const (
+	TraceIDKey = "trace_id"
+	SpanIDKey  = "span_id"
+)
+
+var sensitiveKeys = map[string]func(string) string{
+	AccountNumberKey:     MaskAccountNumber,
+	FromAccountNumberKey: MaskAccountNumber,
+	ToAccountNumberKey:   MaskAccountNumber,
+	AccountNameKey:       MaskName,
+}
+
+// quotedAccountNumber matches an account number the way errors quote it, as in "account number
+// 7835697001" or "account 7835697001". Amounts, counts and other numbers in free text are left alone.
+var quotedAccountNumber = regexp.MustCompile(`(?i)\baccount(?: number)? [0-9a-z-]*[0-9][0-9a-z-]*`)
+
+type requestIDKey struct{}
+
+// ContextWithRequestID returns a copy of ctx carrying requestID, which is then added to every record
+// logged with that context.
+func ContextWithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+func RequestIDFromContext(ctx context.Context) (string, bool) {
+	requestID, ok := ctx.Value(requestIDKey{}).(string)
+	return requestID, ok && requestID != ""
+}
+
+// New creates a logger writing JSON lines to w, from level upwards.
+func New(w io.Writer, level slog.Leveler) *slog.Logger {
+	return slog.New(&handler{
+		next: slog.NewJSONHandler(w, &slog.HandlerOptions{Level: level}),
+	})
+}
+
+// MaskAccountNumber keeps the last 4 characters of accountNumber.
+func MaskAccountNumber(accountNumber string) string {
+	if len(accountNumber) <= 4 {
+		return strings.Repeat("*", len(accountNumber))
+	}
+
+	return strings.Repeat("*", len(accountNumber)-4) + accountNumber[len(accountNumber)-4:]
+}
+
+// MaskName keeps the first letter of every word of name.
+func MaskName(name string) string {
+	words := strings.Fields(name)
+
+	for i, word := range words {
+		letters := []rune(word)
+		words[i] = string(letters[0]) + strings.Repeat("*", len(letters)-1)
+	}
+
+	return strings.Join(words, " ")
+}
+
+// handler adds the request ID of the context and masks personal data before handing records to next.
+type handler struct {
+	next slog.Handler
+	// replacements hides the sensitive values bound with WithAttrs in later messages
+	replacements []string
+}
+
+func (h *handler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *handler) Handle(ctx context.Context, r slog.Record) error {
+	replacements := h.replacements
+
+	r.Attrs(func(attr slog.Attr) bool {
+		replacements = collectSensitive(replacements, attr)
+		return true
+	})
+
+	replacer := textMasker{strings.NewReplacer(replacements...)}
+	masked := slog.NewRecord(r.Time, r.Level, replacer.Replace(r.Message), r.PC)
+
+	if requestID, ok := RequestIDFromContext(ctx); ok {
+		masked.AddAttrs(slog.String(RequestIDKey, requestID))
+	}
+
+	if span := trace.SpanContextFromContext(ctx); span.IsValid() {
+		masked.AddAttrs(slog.String(TraceIDKey, span.TraceID().String()),
+			slog.String(SpanIDKey, span.SpanID().String()))
+	}
+
+	r.Attrs(func(attr slog.Attr) bool {
+		masked.AddAttrs(maskAttr(attr, replacer))
+		return true
+	})
+
+	return h.next.Handle(ctx, masked)
+}
+
+func (h *handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	replacements := h.replacements
+
+	for _, attr := range attrs {
+		replacements = collectSensitive(replacements, attr)
+	}
+
+	replacer := textMasker{strings.NewReplacer(replacements...)}
+	masked := make([]slog.Attr, 0, len(attrs))
+
+	for _, attr := range attrs {
+		masked = append(masked, maskAttr(attr, replacer))
+	}
+
+	return &handler{
+		next:         h.next.WithAttrs(masked),
+		replacements: replacements,
+	}
+}
+
+func (h *handler) WithGroup(name string) slog.Handler {
+	return &handler{
+		next:         h.next.WithGroup(name),
+		replacements: h.replacements,
+	}
+}
+
+// collectSensitive appends the value of every sensitive field of attr and its mask to replacements.
+func collectSensitive(replacements []string, attr slog.Attr) []string {
+	attr.Value = attr.Value.Resolve()
+
+	if attr.Value.Kind() == slog.KindGroup {
+		for _, member := range attr.Value.Group() {
+			replacements = collectSensitive(replacements, member)
+		}
+
+		return replacements
+	}
+
+	if mask, ok := sensitiveKeys[attr.Key]; ok {
+		if value := attr.Value.String(); value != "" {
+			replacements = append(replacements, value, mask(value))
+		}
+	}
+
+	return replacements
+}
+
+// textMasker masks the sensitive values of a record, and the account numbers it quotes, in free text.
+type textMasker struct {
+	values *strings.Replacer
+}
+
+func (m textMasker) Replace(text string) string {
+	return quotedAccountNumber.ReplaceAllStringFunc(m.values.Replace(text), func(quote string) string {
+		at := strings.LastIndexByte(quote, ' ') + 1
+		return quote[:at] + MaskAccountNumber(quote[at:])
+	})
+}
+
+func maskAttr(attr slog.Attr, replacer textMasker) slog.Attr {
+	attr.Value = attr.Value.Resolve()
+
+	if attr.Value.Kind() == slog.KindGroup {
+		members := attr.Value.Group()
+		masked := make([]slog.Attr, 0, len(members))
+
+		for _, member := range members {
+			masked = append(masked, maskAttr(member, replacer))
+		}
+
+		return slog.Attr{Key: attr.Key, Value: slog.GroupValue(masked...)}
+	}
+
+	if mask, ok := sensitiveKeys[attr.Key]; ok {
+		return slog.String(attr.Key, mask(attr.Value.String()))
+	}
+
+	switch attr.Value.Kind() {
+	case slog.KindString:
+		return slog.String(attr.Key, replacer.Replace(attr.Value.String()))
+	case slog.KindAny:
+		// errors and other values are logged as text, which may quote an account number
+		if value, ok := attr.Value.Any().(error); ok {
+			return slog.String(attr.Key, replacer.Replace(value.Error()))
+		}
+
+		if value, ok := attr.Value.Any().(fmt.Stringer); ok {
+			return slog.String(attr.Key, replacer.Replace(value.String()))
+		}
+	}
+
+	return attr
+}
@@ -0,0 +1,204 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"testing"
+)
+
+// logLine logs msg with attrs and returns the fields of the written line.
+func logLine(t *testing.T, logger func(*slog.Logger) *slog.Logger, msg string, attrs ...any) map[string]any {
+	t.Helper()
+
+	var buf bytes.Buffer
+	l := New(&buf, slog.LevelDebug)
+
+	if logger != nil {
+		l = logger(l)
+	}
+
+	l.Info(msg, attrs...)
+
+	var line map[string]any
+
+	if err := json.Unmarshal(buf.Bytes(), &line); err != nil {
+		t.Fatalf("can't parse %q : %v", buf.String(), err)
+	}
+
+	return line
+}
+
+func TestHandlerMasks(t *testing.T) {
+	tests := []struct {
+		name   string
+		logger func(*slog.Logger) *slog.Logger
+		msg    string
+		attrs  []any
+		want   map[string]string
+	}{
+		{
+			name:  "sensitive fields",
+			msg:   "Transfer",
+			attrs: []any{FromAccountNumberKey, "7835697001", ToAccountNumberKey, "AB-12", AccountNameKey, "Kate Bell"},
+			want: map[string]string{
+				FromAccountNumberKey: "******7001",
+				ToAccountNumberKey:   "*B-12",
+				AccountNameKey:       "K*** B***",
+			},
+		},
+		{
+			name:  "sensitive value repeated in the message and an error",
+			msg:   "Can't find 7835697001",
+			attrs: []any{AccountNumberKey, "7835697001", "error", errors.New("no row for 7835697001")},
+			want: map[string]string{
+				"msg":            "Can't find ******7001",
+				AccountNumberKey: "******7001",
+				"error":          "no row for ******7001",
+			},
+		},
+		{
+			name: "sensitive value bound to the logger",
+			logger: func(l *slog.Logger) *slog.Logger {
+				return l.With(AccountNumberKey, "7835697001")
+			},
+			msg:   "Balance of 7835697001 changed",
+			attrs: []any{"detail", "watching 7835697001"},
+			want: map[string]string{
+				"msg":            "Balance of ******7001 changed",
+				AccountNumberKey: "******7001",
+				"detail":         "watching ******7001",
+			},
+		},
+		{
+			name:  "account number quoted by an error",
+			msg:   "RPC finished",
+			attrs: []any{"error", errors.New("permission denied : account number 7835697002 is not owned by the caller")},
+			want: map[string]string{
+				"error": "permission denied : account number ******7002 is not owned by the caller",
+			},
+		},
+		{
+			name:  "account quoted by a message",
+			msg:   "Account 7835697003 not found",
+			attrs: []any{"status", "can't list transactions of account AB7835697003"},
+			want: map[string]string{
+				"msg":    "Account ******7003 not found",
+				"status": "can't list transactions of account ********7003",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			line := logLine(t, tt.logger, tt.msg, tt.attrs...)
+
+			for key, want := range tt.want {
+				if got := line[key]; got != want {
+					t.Errorf("%v = %q, want %q", key, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestHandlerMasksGroupMembers(t *testing.T) {
+	line := logLine(t, nil, "Transfer", slog.Group("transfer", AccountNumberKey, "7835697001"))
+	group, _ := line["transfer"].(map[string]any)
+
+	if got := group[AccountNumberKey]; got != "******7001" {
+		t.Errorf("transfer.%v = %v, want ******7001", AccountNumberKey, got)
+	}
+}
+
+func TestHandlerLeavesOtherNumbers(t *testing.T) {
+	tests := []struct {
+		name  string
+		msg   string
+		attrs []any
+		want  map[string]any
+	}{
+		{
+			name:  "IDR amounts",
+			msg:   "Converted 10000 USD to 155002500 IDR",
+			attrs: []any{"amount", "155002500.00 IDR", "error", errors.New("balance 12345678901 IDR is too low")},
+			want: map[string]any{
+				"msg":    "Converted 10000 USD to 155002500 IDR",
+				"amount": "155002500.00 IDR",
+				"error":  "balance 12345678901 IDR is too low",
+			},
+		},
+		{
+			name:  "counts and sizes",
+			msg:   "Pruned 12000000 idempotency keys",
+			attrs: []any{"rows", int64(12000000), "bytes", "123456789012"},
+			want: map[string]any{
+				"msg":   "Pruned 12000000 idempotency keys",
+				"rows":  float64(12000000),
+				"bytes": "123456789012",
+			},
+		},
+		{
+			name:  "identifiers and timestamps",
+			msg:   "Exchange rate created",
+			attrs: []any{"uuid", "01234567-89ab-cdef-0123-456789abcdef", "valid_from", "20261017123456"},
+			want: map[string]any{
+				"uuid":       "01234567-89ab-cdef-0123-456789abcdef",
+				"valid_from": "20261017123456",
+			},
+		},
+		{
+			name:  "account without a number",
+			msg:   "Account is locked",
+			attrs: []any{"error", errors.New("account number is required")},
+			want: map[string]any{
+				"msg":   "Account is locked",
+				"error": "account number is required",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			line := logLine(t, nil, tt.msg, tt.attrs...)
+
+			for key, want := range tt.want {
+				if got := line[key]; got != want {
+					t.Errorf("%v = %v, want %v", key, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestMaskAccountNumber(t *testing.T) {
+	tests := map[string]string{
+		"":           "",
+		"123":        "***",
+		"1234":       "****",
+		"12345":      "*2345",
+		"7835697001": "******7001",
+	}
+
+	for accountNumber, want := range tests {
+		if got := MaskAccountNumber(accountNumber); got != want {
+			t.Errorf("MaskAccountNumber(%q) = %q, want %q", accountNumber, got, want)
+		}
+	}
+}
+
+func TestMaskName(t *testing.T) {
+	tests := map[string]string{
+		"":             "",
+		"Kate":         "K***",
+		" Kate  Bell ": "K*** B***",
+		"Çelik Öztürk": "Ç**** Ö*****",
+	}
+
+	for name, want := range tests {
+		if got := MaskName(name); got != want {
+			t.Errorf("MaskName(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
@@ -0,0 +1,10 @@
+package port
+
+import (
+	"grpcbank/src/application/domain"
+)
+
+// AuditLogPort records denied operations.
+type AuditLogPort interface {
+	RecordDenial(event domain.AuditEvent)
+}
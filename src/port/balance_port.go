@@ -0,0 +1,12 @@
+package port
+
+import (
+	"context"
+	"grpcbank/src/application/domain"
+)
+
+// BalanceRelayPort carries balance updates between server instances. Updates published to the
+// relay are delivered back to every instance, including the publishing one.
+type BalanceRelayPort interface {
+	PublishBalanceUpdate(ctx context.Context, update domain.BalanceUpdate) error
+}
@@ -0,0 +1,15 @@
+package port
+
+import (
+	"context"
+	"grpcbank/src/application/domain"
+)
+
+// BankSeedPort loads demo fixtures into storage.
+type BankSeedPort interface {
+	// IsProduction reports whether the database is flagged as production, which fixtures must never reach.
+	IsProduction(ctx context.Context) (bool, error)
+	// SeedAccount opens acct with a zero balance and posts entries to it in one transaction, unless
+	// its UUID or number is taken. It reports whether the account was opened.
+	SeedAccount(ctx context.Context, acct domain.Account, entries []domain.LedgerEntry) (bool, error)
+}
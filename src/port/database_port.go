@@ -1,18 +1,36 @@
 package port
 
 import (
+	"context"
 	"github.com/google/uuid"
-	"grpcbank/src/adapter/database"
+	"grpcbank/src/application/domain"
 	"time"
 )
 
 type BankDatabasePort interface {
-	GetBankAccountByAccountNumber(accountNumber string) (database.BankAccountOrm, error)
-	CreateExchangeRate(exchangeRate database.BankExchangeRateOrm) (uuid.UUID, error)
-	GetExchangeRateAtTimestamp(fromCur string, toCur string, timeStamp time.Time) (database.BankExchangeRateOrm, error)
-	CreateTransaction(acct database.BankAccountOrm, bankTrx database.BankTransactionOrm) (uuid.UUID, error)
-	CreateTransfer(transfer database.BankTransferOrm) (uuid.UUID, error)
-	CreateTransferTransactionPair(fromAccountOrm database.BankAccountOrm, toAccountOrm database.BankAccountOrm,
-		fromTransactionOrm database.BankTransactionOrm, toTransactionOrm database.BankTransactionOrm) (bool, error)
-	UpdateTransferStatus(transfer database.BankTransferOrm, status bool) error
+	Ping(ctx context.Context) error
+	GetBankAccountByAccountNumber(ctx context.Context, accountNumber string) (domain.Account, error)
+	CreateExchangeRate(ctx context.Context, exchangeRate domain.ExchangeRate) (uuid.UUID, error)
+	GetExchangeRateAtTimestamp(ctx context.Context, fromCur string, toCur string,
+		timeStamp time.Time) (domain.ExchangeRate, error)
+	// FindNextExchangeRateStart returns the earliest start of a validity window of the pair after the
+	// given time, at which the effective rate of the pair may change.
+	FindNextExchangeRateStart(ctx context.Context, fromCur string, toCur string,
+		after time.Time) (time.Time, bool, error)
+	FindTransactionByIdempotencyKey(ctx context.Context, key string,
+		retainedSince time.Time) (domain.LedgerEntry, bool, error)
+	ListTransactions(ctx context.Context, acct domain.Account, filter domain.TransactionFilter,
+		after *domain.TransactionCursor, limit int) ([]domain.LedgerEntry, error)
+	CreateTransaction(ctx context.Context, entry domain.LedgerEntry) (domain.Account, error)
+	CreateTransactionBatch(ctx context.Context, entries []domain.LedgerEntry) ([]domain.Account, int, error)
+	FindTransferByIdempotencyKey(ctx context.Context, key string,
+		retainedSince time.Time) (domain.Transfer, bool, error)
+	CreateTransfer(ctx context.Context, transfer domain.Transfer) (uuid.UUID, error)
+	// CreateTransferTransactionPair posts both legs of a pending transfer and marks it succeeded at
+	// once. It fails with domain.ErrTransferNotPending, posting nothing, once the transfer is no
+	// longer pending.
+	CreateTransferTransactionPair(ctx context.Context, transfer domain.Transfer, fromEntry domain.LedgerEntry,
+		toEntry domain.LedgerEntry) (domain.Account, domain.Account, error)
+	// FailTransfer marks a pending transfer failed for reason, or fails with domain.ErrTransferNotPending.
+	FailTransfer(ctx context.Context, transferUuid uuid.UUID, reason string) error
 }
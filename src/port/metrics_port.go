@@ -0,0 +1,14 @@
+package port
+
+import (
+	"grpcbank/src/application/domain"
+)
+
+// MetricsPort counts business events for monitoring.
+type MetricsPort interface {
+	// RecordTransfer counts a transfer by outcome, "success" or the reason it failed.
+	RecordTransfer(outcome string)
+	// RecordTransaction counts a transaction posted to an account, or rejected when outcome isn't "success".
+	RecordTransaction(transactionType string, amount domain.Money, outcome string)
+	RecordExchangeRate(pair domain.CurrencyPair)
+}
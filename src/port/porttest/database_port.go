@@ -0,0 +1,1190 @@
+// Package porttest checks that implementations of the ports behave alike, so that the application
+// can run on any of them.
+package porttest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"grpcbank/src/application"
+	"grpcbank/src/application/domain"
+	"grpcbank/src/port"
+	"io"
+	"log/slog"
+	"math/rand"
+	"sync"
+	"testing"
+	"time"
+)
+
+// NewDatabase returns a storage holding accounts, and nothing else the suite could look up. It is
+// called once per subtest.
+type NewDatabase func(t *testing.T, accounts []domain.Account) port.BankDatabasePort
+
+// TestBankDatabasePort runs the conformance suite of port.BankDatabasePort against the storages
+// newDatabase returns. The suite creates its own accounts, currencies and idempotency keys, so it can
+// run against a database shared with other data.
+func TestBankDatabasePort(t *testing.T, newDatabase NewDatabase) {
+	tests := []struct {
+		name string
+		test func(t *testing.T, newDatabase NewDatabase)
+	}{
+		{"AccountLookup", testAccountLookup},
+		{"ExchangeRateWindow", testExchangeRateWindow},
+		{"NextExchangeRateStart", testNextExchangeRateStart},
+		{"Transaction", testTransaction},
+		{"TransactionIdempotencyKey", testTransactionIdempotencyKey},
+		{"ListTransactions", testListTransactions},
+		{"ListTransactionsAmountExponents", testListTransactionsAmountExponents},
+		{"TransactionBatch", testTransactionBatch},
+		{"TransactionBatchRollback", testTransactionBatchRollback},
+		{"Transfer", testTransfer},
+		{"TransferInsufficientBalance", testTransferInsufficientBalance},
+		{"TransferIdempotencyKey", testTransferIdempotencyKey},
+		{"ConcurrentTransfers", testConcurrentTransfers},
+		{"ConcurrentServiceTransfers", testConcurrentServiceTransfers},
+		{"ServiceTransferReplays", testServiceTransferReplays},
+		{"ServiceTransferResumesPosting", testServiceTransferResumesPosting},
+		{"ServiceTransferRecordsAppliedRate", testServiceTransferRecordsAppliedRate},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.test(t, newDatabase)
+		})
+	}
+}
+
+func testAccountLookup(t *testing.T, newDatabase NewDatabase) {
+	acct := newAccount(100)
+	db := newDatabase(t, []domain.Account{acct})
+	ctx := context.Background()
+
+	found, err := db.GetBankAccountByAccountNumber(ctx, acct.AccountNumber)
+
+	if err != nil {
+		t.Fatalf("GetBankAccountByAccountNumber(%v) : %v", acct.AccountNumber, err)
+	}
+
+	expectAccount(t, found, acct)
+
+	_, err = db.GetBankAccountByAccountNumber(ctx, newAccountNumber())
+
+	if !errors.Is(err, domain.ErrAccountNotFound) {
+		t.Errorf("GetBankAccountByAccountNumber of an unknown account = %v, want %v", err, domain.ErrAccountNotFound)
+	}
+}
+
+func testExchangeRateWindow(t *testing.T, newDatabase NewDatabase) {
+	db := newDatabase(t, nil)
+	ctx := context.Background()
+	fromCur, toCur := newCurrency(), newCurrency()
+	start := now()
+
+	rates := []domain.ExchangeRate{
+		newExchangeRate(fromCur, toCur, domain.NewRate(11, 1), start, start.Add(time.Hour)),
+		// overlaps the end of the first window
+		newExchangeRate(fromCur, toCur, domain.NewRate(12, 1), start.Add(30*time.Minute), start.Add(2*time.Hour)),
+		// the inverse pair is looked up on its own
+		newExchangeRate(toCur, fromCur, domain.NewRate(9, 1), start, start.Add(2*time.Hour)),
+	}
+
+	for _, rate := range rates {
+		if _, err := db.CreateExchangeRate(ctx, rate); err != nil {
+			t.Fatalf("CreateExchangeRate(%v) : %v", rate, err)
+		}
+	}
+
+	tests := []struct {
+		at   time.Time
+		want *domain.Rate
+	}{
+		{start.Add(-time.Second), nil},
+		{start, &rates[0].Rate},
+		{start.Add(29 * time.Minute), &rates[0].Rate},
+		{start.Add(30 * time.Minute), &rates[1].Rate},
+		{start.Add(2 * time.Hour), &rates[1].Rate},
+		{start.Add(2*time.Hour + time.Second), nil},
+	}
+
+	for _, tt := range tests {
+		rate, err := db.GetExchangeRateAtTimestamp(ctx, fromCur, toCur, tt.at)
+
+		if tt.want == nil {
+			if !errors.Is(err, domain.ErrExchangeRateNotFound) {
+				t.Errorf("GetExchangeRateAtTimestamp at %v = %v, %v, want %v", tt.at.Sub(start), rate, err,
+					domain.ErrExchangeRateNotFound)
+			}
+
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("GetExchangeRateAtTimestamp at %v : %v", tt.at.Sub(start), err)
+		} else if !rate.Rate.Equal(*tt.want) {
+			t.Errorf("GetExchangeRateAtTimestamp at %v = %v, want %v", tt.at.Sub(start), rate.Rate, *tt.want)
+		}
+	}
+}
+
+func testNextExchangeRateStart(t *testing.T, newDatabase NewDatabase) {
+	db := newDatabase(t, nil)
+	ctx := context.Background()
+	fromCur, toCur := newCurrency(), newCurrency()
+	start := now()
+
+	rates := []domain.ExchangeRate{
+		newExchangeRate(fromCur, toCur, domain.NewRate(11, 1), start, start.Add(3*time.Hour)),
+		newExchangeRate(fromCur, toCur, domain.NewRate(13, 1), start.Add(2*time.Hour), start.Add(3*time.Hour)),
+		newExchangeRate(fromCur, toCur, domain.NewRate(12, 1), start.Add(time.Hour), start.Add(3*time.Hour)),
+		// starts sooner, but for the inverse pair
+		newExchangeRate(toCur, fromCur, domain.NewRate(9, 1), start.Add(time.Minute), start.Add(3*time.Hour)),
+	}
+
+	for _, rate := range rates {
+		if _, err := db.CreateExchangeRate(ctx, rate); err != nil {
+			t.Fatalf("CreateExchangeRate(%v) : %v", rate, err)
+		}
+	}
+
+	tests := []struct {
+		after time.Time
+		want  time.Time
+	}{
+		{start.Add(-time.Second), start},
+		{start, start.Add(time.Hour)},
+		{start.Add(time.Hour - time.Second), start.Add(time.Hour)},
+		{start.Add(time.Hour), start.Add(2 * time.Hour)},
+		{start.Add(2 * time.Hour), time.Time{}},
+	}
+
+	for _, tt := range tests {
+		next, ok, err := db.FindNextExchangeRateStart(ctx, fromCur, toCur, tt.after)
+
+		if err != nil {
+			t.Errorf("FindNextExchangeRateStart after %v : %v", tt.after.Sub(start), err)
+		} else if ok != !tt.want.IsZero() || !next.Equal(tt.want) {
+			t.Errorf("FindNextExchangeRateStart after %v = %v, %v, want %v", tt.after.Sub(start), next.Sub(start),
+				ok, tt.want.Sub(start))
+		}
+	}
+}
+
+func testTransaction(t *testing.T, newDatabase NewDatabase) {
+	acct := newAccount(100)
+	db := newDatabase(t, []domain.Account{acct})
+	ctx := context.Background()
+
+	updated, err := db.CreateTransaction(ctx, newEntry(acct, domain.TransactionTypeIn, 2550, now()))
+
+	if err != nil {
+		t.Fatalf("CreateTransaction (in) : %v", err)
+	}
+
+	expectBalance(t, updated, 12550)
+	expectVersion(t, updated, acct.Version+1)
+
+	updated, err = db.CreateTransaction(ctx, newEntry(acct, domain.TransactionTypeOut, 12550, now()))
+
+	if err != nil {
+		t.Fatalf("CreateTransaction (out) : %v", err)
+	}
+
+	expectBalance(t, updated, 0)
+	expectVersion(t, updated, acct.Version+2)
+
+	_, err = db.CreateTransaction(ctx, newEntry(acct, domain.TransactionTypeOut, 1, now()))
+
+	if !errors.Is(err, domain.ErrInsufficientBalance) {
+		t.Errorf("CreateTransaction overdrawing = %v, want %v", err, domain.ErrInsufficientBalance)
+	}
+
+	expectStoredBalance(t, db, acct, 0)
+	expectVersion(t, storedAccount(t, db, acct), acct.Version+2)
+	expectTransactionCount(t, db, acct, 2)
+}
+
+func testTransactionIdempotencyKey(t *testing.T, newDatabase NewDatabase) {
+	acct := newAccount(100)
+	db := newDatabase(t, []domain.Account{acct})
+	ctx := context.Background()
+	createdAt := now()
+
+	entry := newEntry(acct, domain.TransactionTypeIn, 100, createdAt)
+	entry.IdempotencyKey = uuid.NewString()
+
+	if _, err := db.CreateTransaction(ctx, entry); err != nil {
+		t.Fatalf("CreateTransaction : %v", err)
+	}
+
+	found, ok, err := db.FindTransactionByIdempotencyKey(ctx, entry.IdempotencyKey, createdAt.Add(-time.Hour))
+
+	if err != nil || !ok {
+		t.Fatalf("FindTransactionByIdempotencyKey = %v, %v, want the transaction", ok, err)
+	}
+
+	expectEntry(t, found, entry)
+
+	if _, ok, err := db.FindTransactionByIdempotencyKey(ctx, uuid.NewString(), createdAt); err != nil || ok {
+		t.Errorf("FindTransactionByIdempotencyKey of an unknown key = %v, %v, want not found", ok, err)
+	}
+
+	duplicate := newEntry(acct, domain.TransactionTypeIn, 100, now())
+	duplicate.IdempotencyKey = entry.IdempotencyKey
+
+	if _, err := db.CreateTransaction(ctx, duplicate); !errors.Is(err, domain.ErrDuplicateIdempotencyKey) {
+		t.Errorf("CreateTransaction with a used key = %v, want %v", err, domain.ErrDuplicateIdempotencyKey)
+	}
+
+	expectStoredBalance(t, db, acct, 10100)
+
+	// expired keys are released
+	_, ok, err = db.FindTransactionByIdempotencyKey(ctx, entry.IdempotencyKey, createdAt.Add(time.Second))
+
+	if err != nil || ok {
+		t.Fatalf("FindTransactionByIdempotencyKey of an expired key = %v, %v, want not found", ok, err)
+	}
+
+	if _, err := db.CreateTransaction(ctx, duplicate); err != nil {
+		t.Errorf("CreateTransaction with a released key : %v", err)
+	}
+
+	expectStoredBalance(t, db, acct, 10200)
+}
+
+func testListTransactions(t *testing.T, newDatabase NewDatabase) {
+	acct := newAccount(100)
+	other := newAccount(100)
+	db := newDatabase(t, []domain.Account{acct, other})
+	ctx := context.Background()
+	start := now()
+
+	entries := []domain.LedgerEntry{
+		newEntry(acct, domain.TransactionTypeIn, 1000, start),
+		newEntry(acct, domain.TransactionTypeOut, 250, start.Add(time.Minute)),
+		newEntry(acct, domain.TransactionTypeIn, 5000, start.Add(2*time.Minute)),
+		// same timestamp, ordered by UUID
+		newEntry(acct, domain.TransactionTypeIn, 700, start.Add(2*time.Minute)),
+		newEntry(acct, domain.TransactionTypeOut, 300, start.Add(3*time.Minute)),
+	}
+
+	entries[2].Notes = "Salary for MARCH"
+
+	for _, entry := range entries {
+		if _, err := db.CreateTransaction(ctx, entry); err != nil {
+			t.Fatalf("CreateTransaction : %v", err)
+		}
+	}
+
+	if _, err := db.CreateTransaction(ctx, newEntry(other, domain.TransactionTypeIn, 100, start)); err != nil {
+		t.Fatalf("CreateTransaction : %v", err)
+	}
+
+	newest := []domain.LedgerEntry{entries[4], entries[3], entries[2], entries[1], entries[0]}
+
+	if entries[3].TransactionUuid.String() < entries[2].TransactionUuid.String() {
+		newest[1], newest[2] = newest[2], newest[1]
+	}
+
+	var listed []domain.LedgerEntry
+	var after *domain.TransactionCursor
+
+	for page := 0; page < 3; page++ {
+		entries, err := db.ListTransactions(ctx, acct, domain.TransactionFilter{}, after, 2)
+
+		if err != nil {
+			t.Fatalf("ListTransactions page %d : %v", page, err)
+		}
+
+		listed = append(listed, entries...)
+
+		if len(entries) > 0 {
+			last := entries[len(entries)-1]
+			after = &domain.TransactionCursor{Timestamp: last.Timestamp, TransactionUuid: last.TransactionUuid}
+		}
+	}
+
+	expectEntries(t, "paged", listed, newest)
+
+	minAmount := domain.NewMoney("USD", 300)
+	maxAmount := domain.NewMoney("USD", 1000)
+
+	filters := []struct {
+		name   string
+		filter domain.TransactionFilter
+		want   []domain.LedgerEntry
+	}{
+		{"from", domain.TransactionFilter{FromTimestamp: start.Add(2 * time.Minute)}, newest[:3]},
+		{"to", domain.TransactionFilter{ToTimestamp: start.Add(time.Minute)}, newest[3:]},
+		{"type", domain.TransactionFilter{TransactionType: domain.TransactionTypeOut},
+			[]domain.LedgerEntry{entries[4], entries[1]}},
+		{"amount", domain.TransactionFilter{MinAmount: &minAmount, MaxAmount: &maxAmount},
+			[]domain.LedgerEntry{entries[4], entries[3], entries[0]}},
+		{"notes", domain.TransactionFilter{NotesContains: "salary for march"}, entries[2:3]},
+	}
+
+	for _, tt := range filters {
+		listed, err := db.ListTransactions(ctx, acct, tt.filter, nil, 10)
+
+		if err != nil {
+			t.Errorf("ListTransactions by %v : %v", tt.name, err)
+			continue
+		}
+
+		expectEntries(t, tt.name, listed, tt.want)
+	}
+}
+
+// testListTransactionsAmountExponents filters accounts whose currency has no minor unit or three of
+// them, whose amounts only compare right when the bounds are read in the account currency.
+func testListTransactionsAmountExponents(t *testing.T, newDatabase NewDatabase) {
+	yen := newAccount(0)
+	yen.Balance = domain.ZeroMoney("JPY")
+	dinar := newAccount(0)
+	dinar.Balance = domain.ZeroMoney("KWD")
+	db := newDatabase(t, []domain.Account{yen, dinar})
+	ctx := context.Background()
+	start := now()
+
+	yenEntries := []domain.LedgerEntry{
+		newEntry(yen, domain.TransactionTypeIn, 5, start),
+		newEntry(yen, domain.TransactionTypeIn, 300, start.Add(time.Minute)),
+		newEntry(yen, domain.TransactionTypeIn, 1000, start.Add(2*time.Minute)),
+	}
+
+	dinarEntries := []domain.LedgerEntry{
+		newEntry(dinar, domain.TransactionTypeIn, 5, start),
+		newEntry(dinar, domain.TransactionTypeIn, 1500, start.Add(time.Minute)),
+		newEntry(dinar, domain.TransactionTypeIn, 2250, start.Add(2*time.Minute)),
+	}
+
+	for _, entry := range append(yenEntries, dinarEntries...) {
+		if _, err := db.CreateTransaction(ctx, entry); err != nil {
+			t.Fatalf("CreateTransaction : %v", err)
+		}
+	}
+
+	minYen, maxYen := domain.NewMoney("JPY", 300), domain.NewMoney("JPY", 1000)
+	minDinar, maxDinar := domain.NewMoney("KWD", 1500), domain.NewMoney("KWD", 2250)
+
+	filters := []struct {
+		name   string
+		acct   domain.Account
+		filter domain.TransactionFilter
+		want   []domain.LedgerEntry
+	}{
+		{"JPY", yen, domain.TransactionFilter{MinAmount: &minYen, MaxAmount: &maxYen},
+			[]domain.LedgerEntry{yenEntries[2], yenEntries[1]}},
+		{"JPY max", yen, domain.TransactionFilter{MaxAmount: &minYen},
+			[]domain.LedgerEntry{yenEntries[1], yenEntries[0]}},
+		{"KWD", dinar, domain.TransactionFilter{MinAmount: &minDinar, MaxAmount: &maxDinar},
+			[]domain.LedgerEntry{dinarEntries[2], dinarEntries[1]}},
+		{"KWD max", dinar, domain.TransactionFilter{MaxAmount: &minDinar},
+			[]domain.LedgerEntry{dinarEntries[1], dinarEntries[0]}},
+	}
+
+	for _, tt := range filters {
+		listed, err := db.ListTransactions(ctx, tt.acct, tt.filter, nil, 10)
+
+		if err != nil {
+			t.Errorf("ListTransactions by %v : %v", tt.name, err)
+			continue
+		}
+
+		expectEntries(t, tt.name, listed, tt.want)
+	}
+}
+
+func testTransactionBatch(t *testing.T, newDatabase NewDatabase) {
+	first := newAccount(100)
+	second := newAccount(0)
+	db := newDatabase(t, []domain.Account{first, second})
+	ctx := context.Background()
+
+	accounts, failedIndex, err := db.CreateTransactionBatch(ctx, []domain.LedgerEntry{
+		newEntry(first, domain.TransactionTypeOut, 4000, now()),
+		newEntry(second, domain.TransactionTypeIn, 4000, now()),
+		newEntry(first, domain.TransactionTypeOut, 1000, now()),
+	})
+
+	if err != nil {
+		t.Fatalf("CreateTransactionBatch = %d, %v", failedIndex, err)
+	}
+
+	if len(accounts) != 3 {
+		t.Fatalf("CreateTransactionBatch returned %d accounts, want 3", len(accounts))
+	}
+
+	expectBalance(t, accounts[0], 6000)
+	expectBalance(t, accounts[1], 4000)
+	expectBalance(t, accounts[2], 5000)
+	expectVersion(t, accounts[0], first.Version+1)
+	expectVersion(t, accounts[1], second.Version+1)
+	expectVersion(t, accounts[2], first.Version+2)
+	expectStoredBalance(t, db, first, 5000)
+	expectStoredBalance(t, db, second, 4000)
+}
+
+func testTransactionBatchRollback(t *testing.T, newDatabase NewDatabase) {
+	first := newAccount(100)
+	second := newAccount(0)
+	db := newDatabase(t, []domain.Account{first, second})
+	ctx := context.Background()
+
+	_, failedIndex, err := db.CreateTransactionBatch(ctx, []domain.LedgerEntry{
+		newEntry(first, domain.TransactionTypeOut, 4000, now()),
+		newEntry(second, domain.TransactionTypeIn, 4000, now()),
+		newEntry(first, domain.TransactionTypeOut, 7000, now()),
+	})
+
+	if !errors.Is(err, domain.ErrInsufficientBalance) || failedIndex != 2 {
+		t.Errorf("CreateTransactionBatch overdrawing = %d, %v, want 2, %v", failedIndex, err,
+			domain.ErrInsufficientBalance)
+	}
+
+	key := uuid.NewString()
+	keyed := newEntry(second, domain.TransactionTypeIn, 100, now())
+	keyed.IdempotencyKey = key
+	reused := newEntry(first, domain.TransactionTypeIn, 100, now())
+	reused.IdempotencyKey = key
+
+	_, failedIndex, err = db.CreateTransactionBatch(ctx, []domain.LedgerEntry{keyed, reused})
+
+	if !errors.Is(err, domain.ErrDuplicateIdempotencyKey) || failedIndex != 1 {
+		t.Errorf("CreateTransactionBatch reusing a key = %d, %v, want 1, %v", failedIndex, err,
+			domain.ErrDuplicateIdempotencyKey)
+	}
+
+	expectStoredBalance(t, db, first, 10000)
+	expectStoredBalance(t, db, second, 0)
+	expectVersion(t, storedAccount(t, db, first), first.Version)
+	expectVersion(t, storedAccount(t, db, second), second.Version)
+	expectTransactionCount(t, db, first, 0)
+	expectTransactionCount(t, db, second, 0)
+
+	if _, ok, err := db.FindTransactionByIdempotencyKey(ctx, key, now().Add(-time.Hour)); err != nil || ok {
+		t.Errorf("FindTransactionByIdempotencyKey of a rolled back key = %v, %v, want not found", ok, err)
+	}
+}
+
+func testTransfer(t *testing.T, newDatabase NewDatabase) {
+	from := newAccount(100)
+	to := newAccount(5)
+	db := newDatabase(t, []domain.Account{from, to})
+	ctx := context.Background()
+
+	transfer, fromEntry, toEntry := newTransfer(from, to, 2500, now())
+	transfer.IdempotencyKey = uuid.NewString()
+
+	if _, err := db.CreateTransfer(ctx, transfer); err != nil {
+		t.Fatalf("CreateTransfer : %v", err)
+	}
+
+	found, ok, err := db.FindTransferByIdempotencyKey(ctx, transfer.IdempotencyKey, transfer.Timestamp.Add(-time.Hour))
+
+	if err != nil || !ok || found.Status != domain.TransferStatusPending {
+		t.Fatalf("FindTransferByIdempotencyKey before posting = %+v, %v, %v, want a pending transfer",
+			found, ok, err)
+	}
+
+	updatedFrom, updatedTo, err := db.CreateTransferTransactionPair(ctx, transfer, fromEntry, toEntry)
+
+	if err != nil {
+		t.Fatalf("CreateTransferTransactionPair : %v", err)
+	}
+
+	expectBalance(t, updatedFrom, 7500)
+	expectBalance(t, updatedTo, 3000)
+	expectVersion(t, updatedFrom, from.Version+1)
+	expectVersion(t, updatedTo, to.Version+1)
+	expectStoredBalance(t, db, from, 7500)
+	expectStoredBalance(t, db, to, 3000)
+
+	found, ok, err = db.FindTransferByIdempotencyKey(ctx, transfer.IdempotencyKey, transfer.Timestamp.Add(-time.Hour))
+
+	if err != nil || !ok {
+		t.Fatalf("FindTransferByIdempotencyKey = %v, %v, want the transfer", ok, err)
+	}
+
+	transfer.Status = domain.TransferStatusSucceeded
+	expectTransfer(t, found, transfer)
+
+	listed, err := db.ListTransactions(ctx, to, domain.TransactionFilter{}, nil, 10)
+
+	if err != nil {
+		t.Fatalf("ListTransactions : %v", err)
+	}
+
+	expectEntries(t, "destination", listed, []domain.LedgerEntry{toEntry})
+
+	// a transfer is posted once, whoever else tries again
+	_, again, _ := newTransfer(from, to, 2500, transfer.Timestamp)
+
+	if _, _, err := db.CreateTransferTransactionPair(ctx, transfer, fromEntry, again); !errors.Is(err,
+		domain.ErrTransferNotPending) {
+		t.Errorf("CreateTransferTransactionPair of a posted transfer = %v, want %v", err, domain.ErrTransferNotPending)
+	}
+
+	if err := db.FailTransfer(ctx, transfer.TransferUuid, "too late"); !errors.Is(err, domain.ErrTransferNotPending) {
+		t.Errorf("FailTransfer of a posted transfer = %v, want %v", err, domain.ErrTransferNotPending)
+	}
+
+	expectStoredBalance(t, db, from, 7500)
+	expectStoredBalance(t, db, to, 3000)
+}
+
+func testTransferInsufficientBalance(t *testing.T, newDatabase NewDatabase) {
+	from := newAccount(10)
+	to := newAccount(0)
+	db := newDatabase(t, []domain.Account{from, to})
+	ctx := context.Background()
+
+	transfer, fromEntry, toEntry := newTransfer(from, to, 1001, now())
+	transfer.IdempotencyKey = uuid.NewString()
+
+	if _, err := db.CreateTransfer(ctx, transfer); err != nil {
+		t.Fatalf("CreateTransfer : %v", err)
+	}
+
+	_, _, err := db.CreateTransferTransactionPair(ctx, transfer, fromEntry, toEntry)
+
+	if !errors.Is(err, domain.ErrInsufficientBalance) {
+		t.Errorf("CreateTransferTransactionPair overdrawing = %v, want %v", err, domain.ErrInsufficientBalance)
+	}
+
+	expectStoredBalance(t, db, from, 1000)
+	expectStoredBalance(t, db, to, 0)
+	expectTransactionCount(t, db, from, 0)
+	expectTransactionCount(t, db, to, 0)
+
+	found, ok, err := db.FindTransferByIdempotencyKey(ctx, transfer.IdempotencyKey, transfer.Timestamp.Add(-time.Hour))
+
+	// the storage leaves failing it to the caller
+	if err != nil || !ok || found.Status != domain.TransferStatusPending {
+		t.Errorf("FindTransferByIdempotencyKey = %+v, %v, %v, want a pending transfer", found, ok, err)
+	}
+
+	reason := domain.ErrInsufficientBalance.Error()
+
+	if err := db.FailTransfer(ctx, transfer.TransferUuid, reason); err != nil {
+		t.Fatalf("FailTransfer : %v", err)
+	}
+
+	found, ok, err = db.FindTransferByIdempotencyKey(ctx, transfer.IdempotencyKey, transfer.Timestamp.Add(-time.Hour))
+
+	if err != nil || !ok {
+		t.Fatalf("FindTransferByIdempotencyKey = %v, %v, want the transfer", ok, err)
+	}
+
+	transfer.Status = domain.TransferStatusFailed
+	transfer.FailureReason = reason
+	expectTransfer(t, found, transfer)
+
+	if err := db.FailTransfer(ctx, transfer.TransferUuid, reason); !errors.Is(err, domain.ErrTransferNotPending) {
+		t.Errorf("FailTransfer of a failed transfer = %v, want %v", err, domain.ErrTransferNotPending)
+	}
+
+	// money arriving later doesn't post a failed transfer
+	if _, err := db.CreateTransaction(ctx, newEntry(from, domain.TransactionTypeIn, 1000, now())); err != nil {
+		t.Fatalf("CreateTransaction : %v", err)
+	}
+
+	if _, _, err := db.CreateTransferTransactionPair(ctx, transfer, fromEntry, toEntry); !errors.Is(err,
+		domain.ErrTransferNotPending) {
+		t.Errorf("CreateTransferTransactionPair of a failed transfer = %v, want %v", err, domain.ErrTransferNotPending)
+	}
+
+	expectStoredBalance(t, db, from, 2000)
+	expectStoredBalance(t, db, to, 0)
+}
+
+func testTransferIdempotencyKey(t *testing.T, newDatabase NewDatabase) {
+	from := newAccount(100)
+	to := newAccount(0)
+	db := newDatabase(t, []domain.Account{from, to})
+	ctx := context.Background()
+	createdAt := now()
+
+	transfer, _, _ := newTransfer(from, to, 100, createdAt)
+	transfer.IdempotencyKey = uuid.NewString()
+
+	if _, err := db.CreateTransfer(ctx, transfer); err != nil {
+		t.Fatalf("CreateTransfer : %v", err)
+	}
+
+	duplicate, _, _ := newTransfer(from, to, 100, now())
+	duplicate.IdempotencyKey = transfer.IdempotencyKey
+
+	if _, err := db.CreateTransfer(ctx, duplicate); !errors.Is(err, domain.ErrDuplicateIdempotencyKey) {
+		t.Errorf("CreateTransfer with a used key = %v, want %v", err, domain.ErrDuplicateIdempotencyKey)
+	}
+
+	if _, ok, err := db.FindTransferByIdempotencyKey(ctx, uuid.NewString(), createdAt); err != nil || ok {
+		t.Errorf("FindTransferByIdempotencyKey of an unknown key = %v, %v, want not found", ok, err)
+	}
+
+	// expired keys are released
+	_, ok, err := db.FindTransferByIdempotencyKey(ctx, transfer.IdempotencyKey, createdAt.Add(time.Second))
+
+	if err != nil || ok {
+		t.Fatalf("FindTransferByIdempotencyKey of an expired key = %v, %v, want not found", ok, err)
+	}
+
+	if _, err := db.CreateTransfer(ctx, duplicate); err != nil {
+		t.Errorf("CreateTransfer with a released key : %v", err)
+	}
+}
+
+func testConcurrentTransfers(t *testing.T, newDatabase NewDatabase) {
+	first := newAccount(10)
+	second := newAccount(10)
+	db := newDatabase(t, []domain.Account{first, second})
+	ctx := context.Background()
+
+	const transfers = 40
+
+	var wg sync.WaitGroup
+	errs := make(chan error, transfers)
+
+	for i := 0; i < transfers; i++ {
+		from, to := first, second
+
+		if i%2 == 1 {
+			from, to = second, first
+		}
+
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			transfer, fromEntry, toEntry := newTransfer(from, to, 300, now())
+
+			if _, err := db.CreateTransfer(ctx, transfer); err != nil {
+				errs <- err
+				return
+			}
+
+			_, _, err := db.CreateTransferTransactionPair(ctx, transfer, fromEntry, toEntry)
+
+			if err != nil && !errors.Is(err, domain.ErrInsufficientBalance) {
+				errs <- err
+			}
+		}()
+	}
+
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Errorf("concurrent transfer : %v", err)
+	}
+
+	firstAccount := storedAccount(t, db, first)
+	secondAccount := storedAccount(t, db, second)
+
+	if firstAccount.Balance.IsNegative() || secondAccount.Balance.IsNegative() {
+		t.Errorf("balances went negative : %v and %v", firstAccount.Balance, secondAccount.Balance)
+	}
+
+	if total := firstAccount.Balance.MinorUnits + secondAccount.Balance.MinorUnits; total != 2000 {
+		t.Errorf("balances add up to %d minor units, want 2000", total)
+	}
+}
+
+// testConcurrentServiceTransfers moves money both ways between two accounts from many goroutines
+// through application.BankService, which leaves checking and updating the balances to the storage.
+// No update may be lost: each balance has to match the transfers that succeeded and its ledger.
+func testConcurrentServiceTransfers(t *testing.T, newDatabase NewDatabase) {
+	first := newAccount(10)
+	second := newAccount(10)
+	db := newDatabase(t, []domain.Account{first, second})
+	service := newBankService(db)
+	ctx := context.Background()
+
+	const transfers = 40
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	moved := make(map[string]int64)
+	errs := make(chan error, transfers)
+
+	for i := 0; i < transfers; i++ {
+		from, to := first, second
+
+		if i%2 == 1 {
+			from, to = second, first
+		}
+
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			transfer, err := service.Transfer(ctx, domain.TransferTransaction{
+				FromAccountNumber: from.AccountNumber,
+				ToAccountNumber:   to.AccountNumber,
+				Amount:            domain.NewMoney("USD", 300),
+			})
+
+			if errors.Is(err, domain.ErrInsufficientBalance) {
+				return
+			}
+
+			if err != nil || transfer.Status != domain.TransferStatusSucceeded {
+				errs <- fmt.Errorf("transfer = %+v, %w", transfer, err)
+				return
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			moved[from.AccountNumber] -= 300
+			moved[to.AccountNumber] += 300
+		}()
+	}
+
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Errorf("concurrent transfer : %v", err)
+	}
+
+	for _, acct := range []domain.Account{first, second} {
+		want := acct.Balance.MinorUnits + moved[acct.AccountNumber]
+		expectStoredBalance(t, db, acct, want)
+
+		entries, err := db.ListTransactions(ctx, acct, domain.TransactionFilter{}, nil, transfers+1)
+
+		if err != nil {
+			t.Fatalf("ListTransactions : %v", err)
+		}
+
+		posted := acct.Balance.MinorUnits
+
+		for _, entry := range entries {
+			if entry.TransactionType == domain.TransactionTypeIn {
+				posted += entry.Amount.MinorUnits
+			} else {
+				posted -= entry.Amount.MinorUnits
+			}
+		}
+
+		if posted != want {
+			t.Errorf("ledger of %v adds up to %d minor units, want %d", acct.AccountNumber, posted, want)
+		}
+	}
+}
+
+// testServiceTransferReplays replays a transfer while the request that recorded it is still posting
+// it. The money has to move once and both requests be answered with that one transfer, and a
+// refused transfer has to be answered with its reason each time.
+func testServiceTransferReplays(t *testing.T, newDatabase NewDatabase) {
+	from := newAccount(10)
+	to := newAccount(0)
+	held, release := make(chan struct{}), make(chan struct{})
+	db := &interceptedStorage{BankDatabasePort: newDatabase(t, []domain.Account{from, to})}
+	db.beforePosting = func() error {
+		// only the first posting is held
+		db.beforePosting = nil
+		close(held)
+		<-release
+
+		return nil
+	}
+	service := newBankService(db)
+	ctx := context.Background()
+
+	transferTrx := domain.TransferTransaction{
+		FromAccountNumber: from.AccountNumber,
+		ToAccountNumber:   to.AccountNumber,
+		Amount:            domain.NewMoney("USD", 300),
+		IdempotencyKey:    uuid.NewString(),
+	}
+
+	type result struct {
+		transfer domain.Transfer
+		err      error
+	}
+
+	first := make(chan result, 1)
+
+	go func() {
+		transfer, err := service.Transfer(ctx, transferTrx)
+		first <- result{transfer, err}
+	}()
+
+	<-held
+
+	replayed, err := service.Transfer(ctx, transferTrx)
+
+	if err != nil || replayed.Status != domain.TransferStatusSucceeded {
+		t.Errorf("Transfer replayed while posting = %+v, %v, want a successful transfer", replayed, err)
+	}
+
+	close(release)
+	original := <-first
+
+	if original.err != nil || original.transfer.Status != domain.TransferStatusSucceeded ||
+		original.transfer.TransferUuid != replayed.TransferUuid {
+		t.Errorf("original Transfer = %+v, %v, want transfer %v succeeded", original.transfer, original.err,
+			replayed.TransferUuid)
+	}
+
+	expectStoredBalance(t, db, from, 700)
+	expectStoredBalance(t, db, to, 300)
+	expectTransactionCount(t, db, to, 1)
+
+	refusedTrx := transferTrx
+	refusedTrx.Amount = domain.NewMoney("USD", 701)
+	refusedTrx.IdempotencyKey = uuid.NewString()
+
+	refused, err := service.Transfer(ctx, refusedTrx)
+
+	if !errors.Is(err, domain.ErrInsufficientBalance) || refused.Status != domain.TransferStatusFailed {
+		t.Fatalf("Transfer overdrawing = %+v, %v, want a failed transfer and %v", refused, err,
+			domain.ErrInsufficientBalance)
+	}
+
+	// the deposit doesn't turn the refused transfer into a successful one
+	if _, err := db.CreateTransaction(ctx, newEntry(from, domain.TransactionTypeIn, 1000, now())); err != nil {
+		t.Fatalf("CreateTransaction : %v", err)
+	}
+
+	replayed, err = service.Transfer(ctx, refusedTrx)
+
+	if !errors.Is(err, domain.ErrInsufficientBalance) || replayed.TransferUuid != refused.TransferUuid ||
+		replayed.Status != domain.TransferStatusFailed {
+		t.Errorf("replayed Transfer = %+v, %v, want transfer %v failed with %v", replayed, err, refused.TransferUuid,
+			domain.ErrInsufficientBalance)
+	}
+
+	expectStoredBalance(t, db, from, 1700)
+}
+
+// interceptedStorage runs beforePosting, when it is set, before posting a transfer and fails the
+// posting with its error, so that a test can hold postings or fail them like a storage losing its
+// connection. beforePosting is set by the test while no transfer runs.
+type interceptedStorage struct {
+	port.BankDatabasePort
+	beforePosting func() error
+}
+
+var errInterrupted = errors.New("connection reset by peer")
+
+func (s *interceptedStorage) CreateTransferTransactionPair(ctx context.Context, transfer domain.Transfer,
+	fromEntry domain.LedgerEntry, toEntry domain.LedgerEntry) (domain.Account, domain.Account, error) {
+	if s.beforePosting != nil {
+		if err := s.beforePosting(); err != nil {
+			return domain.Account{}, domain.Account{}, err
+		}
+	}
+
+	return s.BankDatabasePort.CreateTransferTransactionPair(ctx, transfer, fromEntry, toEntry)
+}
+
+// testServiceTransferResumesPosting retries a transfer whose posting failed, which has to post the
+// transfer recorded the first time rather than report it failed or move the money twice.
+func testServiceTransferResumesPosting(t *testing.T, newDatabase NewDatabase) {
+	from := newAccount(10)
+	to := newAccount(0)
+	db := &interceptedStorage{BankDatabasePort: newDatabase(t, []domain.Account{from, to})}
+	service := newBankService(db)
+	ctx := context.Background()
+
+	transferTrx := domain.TransferTransaction{
+		FromAccountNumber: from.AccountNumber,
+		ToAccountNumber:   to.AccountNumber,
+		Amount:            domain.NewMoney("USD", 300),
+		IdempotencyKey:    uuid.NewString(),
+	}
+
+	db.beforePosting = func() error { return errInterrupted }
+
+	interrupted, err := service.Transfer(ctx, transferTrx)
+
+	if !errors.Is(err, errInterrupted) {
+		t.Fatalf("interrupted Transfer = %+v, %v, want %v", interrupted, err, errInterrupted)
+	}
+
+	// retried while the storage is still failing
+	if _, err := service.Transfer(ctx, transferTrx); !errors.Is(err, errInterrupted) {
+		t.Fatalf("Transfer retried during the failure = %v, want %v", err, errInterrupted)
+	}
+
+	expectStoredBalance(t, db, from, 1000)
+
+	db.beforePosting = nil
+
+	resumed, err := service.Transfer(ctx, transferTrx)
+
+	if err != nil || resumed.Status != domain.TransferStatusSucceeded || resumed.TransferUuid != interrupted.TransferUuid {
+		t.Fatalf("retried Transfer = %+v, %v, want transfer %v succeeded", resumed, err, interrupted.TransferUuid)
+	}
+
+	replayed, err := service.Transfer(ctx, transferTrx)
+
+	if err != nil || replayed.Status != domain.TransferStatusSucceeded || replayed.TransferUuid != resumed.TransferUuid {
+		t.Errorf("replayed Transfer = %+v, %v, want transfer %v succeeded", replayed, err, resumed.TransferUuid)
+	}
+
+	expectStoredBalance(t, db, from, 700)
+	expectStoredBalance(t, db, to, 300)
+	expectTransactionCount(t, db, from, 1)
+}
+
+// testServiceTransferRecordsAppliedRate converts with only the opposite rate stored, whose inverse
+// has more decimals than rates are stored with.
+func testServiceTransferRecordsAppliedRate(t *testing.T, newDatabase NewDatabase) {
+	cur := newCurrency()
+	from := newAccount(100)
+	to := newAccount(0)
+	to.Balance = domain.ZeroMoney(cur)
+	db := newDatabase(t, []domain.Account{from, to})
+	service := newBankService(db)
+	ctx := context.Background()
+
+	_, err := db.CreateExchangeRate(ctx, newExchangeRate(cur, "USD", domain.NewRate(3, 0), now().Add(-time.Hour),
+		now().Add(time.Hour)))
+
+	if err != nil {
+		t.Fatalf("CreateExchangeRate : %v", err)
+	}
+
+	for _, amount := range []domain.Money{domain.NewMoney("USD", 1000), domain.NewMoney(cur, 300)} {
+		transferTrx := domain.TransferTransaction{
+			FromAccountNumber: from.AccountNumber,
+			ToAccountNumber:   to.AccountNumber,
+			Amount:            amount,
+			IdempotencyKey:    uuid.NewString(),
+		}
+
+		transfer, err := service.Transfer(ctx, transferTrx)
+
+		if err != nil {
+			t.Fatalf("Transfer of %v : %v", amount, err)
+		}
+
+		conversion := transfer.Conversion
+
+		// a rate with more decimals than are stored would print the same
+		if want, _ := domain.ParseRate("0.3333333333"); !conversion.Rate.Equal(want) {
+			t.Errorf("rate of %v = %v, want exactly %v", amount, conversion.Rate, want)
+		}
+
+		want := conversion.DestinationAmount
+		got, err := conversion.SourceAmount.Convert(cur, conversion.Rate, domain.DefaultRoundingMode)
+
+		if amount.Currency == cur {
+			want = conversion.SourceAmount
+			got, err = conversion.DestinationAmount.Convert("USD", conversion.Rate.Invert(), domain.DefaultRoundingMode)
+		}
+
+		if err != nil || got != want {
+			t.Errorf("%v converted at %v = %v, %v, want %v", amount, conversion.Rate, got, err, want)
+		}
+
+		stored, found, err := db.FindTransferByIdempotencyKey(ctx, transferTrx.IdempotencyKey,
+			now().Add(-time.Hour))
+
+		if err != nil || !found {
+			t.Fatalf("FindTransferByIdempotencyKey = %v, %v", found, err)
+		}
+
+		if !stored.Conversion.Rate.Equal(conversion.Rate) {
+			t.Errorf("stored rate of %v = %v, want the applied %v", amount, stored.Conversion.Rate, conversion.Rate)
+		}
+	}
+}
+
+func newBankService(db port.BankDatabasePort) *application.BankService {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	return application.NewBankService(db, application.NewBalanceBroker(logger),
+		application.NewExchangeRateBroker(logger), time.Hour, logger)
+}
+
+// now is truncated to the microseconds Postgres keeps.
+func now() time.Time {
+	return time.Now().Truncate(time.Microsecond)
+}
+
+func newAccountNumber() string {
+	return fmt.Sprintf("%010d", rand.Int63n(1e10))
+}
+
+// newCurrency returns a made up currency code, so that rates of other tests don't match.
+func newCurrency() string {
+	const letters = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+
+	code := []byte("Z")
+
+	for i := 0; i < 4; i++ {
+		code = append(code, letters[rand.Intn(len(letters))])
+	}
+
+	return string(code)
+}
+
+// newAccount returns a USD account holding dollars.
+func newAccount(dollars int64) domain.Account {
+	return domain.Account{
+		AccountUuid:   uuid.New(),
+		AccountNumber: newAccountNumber(),
+		AccountName:   "Conformance " + uuid.NewString()[:8],
+		OwnerSubject:  "conformance",
+		Balance:       domain.NewMoney("USD", dollars*100),
+	}
+}
+
+func newExchangeRate(fromCur string, toCur string, rate domain.Rate, validFrom time.Time,
+	validTo time.Time) domain.ExchangeRate {
+	return domain.ExchangeRate{
+		FromCurrency:       fromCur,
+		ToCurrency:         toCur,
+		Rate:               rate,
+		ValidFromTimestamp: validFrom,
+		ValidToTimestamp:   validTo,
+	}
+}
+
+func newEntry(acct domain.Account, transactionType string, cents int64, timestamp time.Time) domain.LedgerEntry {
+	return domain.LedgerEntry{
+		TransactionUuid: uuid.New(),
+		AccountUuid:     acct.AccountUuid,
+		Timestamp:       timestamp,
+		Amount:          domain.NewMoney(acct.Currency(), cents),
+		TransactionType: transactionType,
+		Notes:           transactionType + " " + uuid.NewString()[:8],
+	}
+}
+
+// newTransfer returns a transfer of cents between two accounts of the same currency and its legs.
+func newTransfer(from domain.Account, to domain.Account, cents int64,
+	timestamp time.Time) (domain.Transfer, domain.LedgerEntry, domain.LedgerEntry) {
+	amount := domain.NewMoney(from.Currency(), cents)
+	conversion := domain.Conversion{
+		SourceAmount:      amount,
+		DestinationAmount: amount,
+		Rate:              domain.NewRate(1, 0),
+	}
+
+	transfer := domain.Transfer{
+		TransferUuid:    uuid.New(),
+		FromAccountUuid: from.AccountUuid,
+		ToAccountUuid:   to.AccountUuid,
+		Amount:          amount,
+		Conversion:      conversion,
+		Timestamp:       timestamp,
+		Status:          domain.TransferStatusPending,
+	}
+
+	fromEntry := newEntry(from, domain.TransactionTypeOut, cents, timestamp)
+	fromEntry.Conversion = &conversion
+	toEntry := newEntry(to, domain.TransactionTypeIn, cents, timestamp)
+	toEntry.Conversion = &conversion
+
+	return transfer, fromEntry, toEntry
+}
+
+func storedAccount(t *testing.T, db port.BankDatabasePort, acct domain.Account) domain.Account {
+	t.Helper()
+
+	found, err := db.GetBankAccountByAccountNumber(context.Background(), acct.AccountNumber)
+
+	if err != nil {
+		t.Fatalf("GetBankAccountByAccountNumber(%v) : %v", acct.AccountNumber, err)
+	}
+
+	return found
+}
+
+func expectStoredBalance(t *testing.T, db port.BankDatabasePort, acct domain.Account, cents int64) {
+	t.Helper()
+	expectBalance(t, storedAccount(t, db, acct), cents)
+}
+
+func expectBalance(t *testing.T, acct domain.Account, cents int64) {
+	t.Helper()
+
+	if want := domain.NewMoney("USD", cents); acct.Balance != want {
+		t.Errorf("balance of %v = %v, want %v", acct.AccountNumber, acct.Balance, want)
+	}
+}
+
+func expectVersion(t *testing.T, acct domain.Account, version int64) {
+	t.Helper()
+
+	if acct.Version != version {
+		t.Errorf("version of %v = %d, want %d", acct.AccountNumber, acct.Version, version)
+	}
+}
+
+func expectTransactionCount(t *testing.T, db port.BankDatabasePort, acct domain.Account, count int) {
+	t.Helper()
+
+	entries, err := db.ListTransactions(context.Background(), acct, domain.TransactionFilter{}, nil, 100)
+
+	if err != nil {
+		t.Fatalf("ListTransactions : %v", err)
+	}
+
+	if len(entries) != count {
+		t.Errorf("%v has %d transactions, want %d", acct.AccountNumber, len(entries), count)
+	}
+}
+
+func expectAccount(t *testing.T, got domain.Account, want domain.Account) {
+	t.Helper()
+
+	if got != want {
+		t.Errorf("account = %+v, want %+v", got, want)
+	}
+}
+
+func expectEntries(t *testing.T, name string, got []domain.LedgerEntry, want []domain.LedgerEntry) {
+	t.Helper()
+
+	if len(got) != len(want) {
+		t.Errorf("%v : got %d transactions, want %d", name, len(got), len(want))
+		return
+	}
+
+	for i := range want {
+		expectEntry(t, got[i], want[i])
+	}
+}
+
+func expectEntry(t *testing.T, got domain.LedgerEntry, want domain.LedgerEntry) {
+	t.Helper()
+
+	if got.TransactionUuid != want.TransactionUuid || got.AccountUuid != want.AccountUuid ||
+		!got.Timestamp.Equal(want.Timestamp) || got.Amount != want.Amount ||
+		got.TransactionType != want.TransactionType || got.Notes != want.Notes ||
+		got.IdempotencyKey != want.IdempotencyKey || !equalConversions(got.Conversion, want.Conversion) {
+		t.Errorf("transaction = %+v, want %+v", got, want)
+	}
+}
+
+func expectTransfer(t *testing.T, got domain.Transfer, want domain.Transfer) {
+	t.Helper()
+
+	if got.TransferUuid != want.TransferUuid || got.FromAccountUuid != want.FromAccountUuid ||
+		got.ToAccountUuid != want.ToAccountUuid || got.Amount != want.Amount ||
+		!equalConversions(&got.Conversion, &want.Conversion) || !got.Timestamp.Equal(want.Timestamp) ||
+		got.Status != want.Status || got.FailureReason != want.FailureReason ||
+		got.IdempotencyKey != want.IdempotencyKey {
+		t.Errorf("transfer = %+v, want %+v", got, want)
+	}
+}
+
+func equalConversions(got *domain.Conversion, want *domain.Conversion) bool {
+	if got == nil || want == nil {
+		return got == want
+	}
+
+	return got.SourceAmount == want.SourceAmount && got.DestinationAmount == want.DestinationAmount &&
+		got.Rate.Equal(want.Rate)
+}
@@ -0,0 +1,146 @@
+package porttest
+
+import (
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"grpcbank/src/application"
+	"grpcbank/src/application/domain"
+	"grpcbank/src/port"
+	"testing"
+)
+
+// SeedDatabase is a storage fixtures are seeded into and read back from.
+type SeedDatabase interface {
+	port.BankSeedPort
+	port.BankDatabasePort
+}
+
+// NewSeedDatabase returns a storage without accounts, flagged as production when production is set.
+// It is called once per subtest.
+type NewSeedDatabase func(t *testing.T, production bool) SeedDatabase
+
+// TestBankSeedPort runs the conformance suite of port.BankSeedPort, through application.SeedAccounts,
+// against the storages newDatabase returns.
+func TestBankSeedPort(t *testing.T, newDatabase NewSeedDatabase) {
+	tests := []struct {
+		name string
+		test func(t *testing.T, newDatabase NewSeedDatabase)
+	}{
+		{"SeedAccounts", testSeedAccounts},
+		{"SeedAccountsAgain", testSeedAccountsAgain},
+		{"SeedAccountsProduction", testSeedAccountsProduction},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.test(t, newDatabase)
+		})
+	}
+}
+
+func testSeedAccounts(t *testing.T, newDatabase NewSeedDatabase) {
+	db := newDatabase(t, false)
+	funded, empty := newFixture(100), newFixture(0)
+	funded.OwnerSubject = "kate"
+
+	opened, err := application.SeedAccounts(context.Background(), db, []domain.AccountFixture{funded, empty})
+
+	if err != nil || opened != 2 {
+		t.Fatalf("SeedAccounts = %d, %v, want 2 opened", opened, err)
+	}
+
+	expectAccount(t, storedFixture(t, db, funded), domain.Account{
+		AccountUuid:   funded.AccountUuid,
+		AccountNumber: funded.AccountNumber,
+		AccountName:   funded.AccountName,
+		OwnerSubject:  "kate",
+		Balance:       funded.InitialDeposit,
+		Version:       1,
+	})
+
+	expectAccount(t, storedFixture(t, db, empty), domain.Account{
+		AccountUuid:   empty.AccountUuid,
+		AccountNumber: empty.AccountNumber,
+		AccountName:   empty.AccountName,
+		Balance:       domain.ZeroMoney("USD"),
+	})
+
+	expectTransactionCount(t, db, storedFixture(t, db, funded), 1)
+	expectTransactionCount(t, db, storedFixture(t, db, empty), 0)
+}
+
+func testSeedAccountsAgain(t *testing.T, newDatabase NewSeedDatabase) {
+	db := newDatabase(t, false)
+	seeded := newFixture(100)
+
+	if _, err := application.SeedAccounts(context.Background(), db, []domain.AccountFixture{seeded}); err != nil {
+		t.Fatalf("SeedAccounts : %v", err)
+	}
+
+	sameUuid := newFixture(50)
+	sameUuid.AccountUuid = seeded.AccountUuid
+	sameNumber := newFixture(70)
+	sameNumber.AccountNumber = seeded.AccountNumber
+	fresh := newFixture(10)
+
+	opened, err := application.SeedAccounts(context.Background(), db,
+		[]domain.AccountFixture{seeded, sameUuid, sameNumber, fresh})
+
+	if err != nil || opened != 1 {
+		t.Fatalf("SeedAccounts again = %d, %v, want 1 opened", opened, err)
+	}
+
+	acct := storedFixture(t, db, seeded)
+
+	if acct.AccountUuid != seeded.AccountUuid {
+		t.Errorf("account %v = %v, want %v", seeded.AccountNumber, acct.AccountUuid, seeded.AccountUuid)
+	}
+
+	expectBalance(t, acct, 10000)
+	expectTransactionCount(t, db, acct, 1)
+	expectBalance(t, storedFixture(t, db, fresh), 1000)
+
+	if _, err := db.GetBankAccountByAccountNumber(context.Background(), sameUuid.AccountNumber); !errors.Is(err,
+		domain.ErrAccountNotFound) {
+		t.Errorf("account reusing a seeded UUID = %v, want %v", err, domain.ErrAccountNotFound)
+	}
+}
+
+func testSeedAccountsProduction(t *testing.T, newDatabase NewSeedDatabase) {
+	db := newDatabase(t, true)
+	fixture := newFixture(100)
+
+	opened, err := application.SeedAccounts(context.Background(), db, []domain.AccountFixture{fixture})
+
+	if !errors.Is(err, domain.ErrProductionDatabase) || opened != 0 {
+		t.Fatalf("SeedAccounts into production = %d, %v, want 0, %v", opened, err, domain.ErrProductionDatabase)
+	}
+
+	if _, err := db.GetBankAccountByAccountNumber(context.Background(), fixture.AccountNumber); !errors.Is(err,
+		domain.ErrAccountNotFound) {
+		t.Errorf("account seeded into production = %v, want %v", err, domain.ErrAccountNotFound)
+	}
+}
+
+// newFixture returns a USD account fixture depositing dollars.
+func newFixture(dollars int64) domain.AccountFixture {
+	return domain.AccountFixture{
+		AccountUuid:    uuid.New(),
+		AccountNumber:  newAccountNumber(),
+		AccountName:    "Kate",
+		InitialDeposit: domain.NewMoney("USD", dollars*100),
+	}
+}
+
+func storedFixture(t *testing.T, db port.BankDatabasePort, fixture domain.AccountFixture) domain.Account {
+	t.Helper()
+
+	acct, err := db.GetBankAccountByAccountNumber(context.Background(), fixture.AccountNumber)
+
+	if err != nil {
+		t.Fatalf("GetBankAccountByAccountNumber(%v) : %v", fixture.AccountNumber, err)
+	}
+
+	return acct
+}
@@ -1,16 +1,25 @@
 package port
 
 import (
+	"context"
 	"github.com/google/uuid"
 	"grpcbank/src/application/domain"
 	"time"
 )
 
 type BankServicePort interface {
-	FindCurrentBalance(accountNumber string) (float64, error)
-	CreateExchangeRate(exchangeRate domain.ExchangeRate) (uuid.UUID, error)
-	FindExchangeRate(fromCur string, toCur string, ts time.Time) (float64, error)
-	CreateTransaction(acct string, bankTrx domain.Transaction) (uuid.UUID, error)
-	CalculateTransactionSummary(trxSummary *domain.TransactionSummary, bankTrx domain.Transaction) error
-	Transfer(transferTrx domain.TransferTransaction) (uuid.UUID, bool, error)
+	Ping(ctx context.Context) error
+	FindCurrentBalance(ctx context.Context, accountNumber string) (domain.Money, error)
+	CreateExchangeRate(ctx context.Context, exchangeRate domain.ExchangeRate) (uuid.UUID, error)
+	FindExchangeRate(ctx context.Context, fromCur string, toCur string, ts time.Time) (domain.Rate, error)
+	WatchExchangeRates(ctx context.Context, pairs []domain.CurrencyPair) (<-chan domain.ExchangeRate, func(),
+		error)
+	CreateTransaction(ctx context.Context, acct string, bankTrx domain.Transaction) (uuid.UUID, error)
+	ListTransactions(ctx context.Context, accountNumber string, filter domain.TransactionFilter, pageSize int,
+		pageToken string) ([]domain.Transaction, string, error)
+	CreateTransactionsAtomically(ctx context.Context, bankTrxs []domain.Transaction) ([]domain.TransactionResult, error)
+	CalculateTransactionSummary(ctx context.Context, trxSummary *domain.TransactionSummary,
+		bankTrx domain.Transaction) error
+	Transfer(ctx context.Context, transferTrx domain.TransferTransaction) (domain.Transfer, error)
+	WatchBalance(ctx context.Context, accountNumber string) (<-chan domain.BalanceUpdate, func(), error)
 }